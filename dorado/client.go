@@ -22,6 +22,18 @@ type Client struct {
 	RemoteDevice *Device
 
 	PortGroupName string
+	// PortGroupNameFunc select port group name per host in AttachVolume.
+	// use PortGroupName if nil or return empty.
+	PortGroupNameFunc func(hostname string) string
+
+	// ProtectionMode is how volumes are protected between LocalDevice and RemoteDevice.
+	// default is ProtectionHyperMetro.
+	ProtectionMode ProtectionMode
+	// ReplicationOption is used by CreateReplicatedVolume, use default if nil.
+	ReplicationOption *ReplicationOption
+	// DRHostStore persist hosts that are detached by DR workflows, and they are remapped after resumed.
+	// in-memory store is set by NewClient, nil is not persisted.
+	DRHostStore DRHostStore
 
 	Logger *log.Logger
 }
@@ -38,6 +50,9 @@ type Device struct {
 
 	Username string
 	Password string
+
+	healthMu             sync.Mutex
+	ethernetPortCounters map[string]EthernetPortCounters // for EthernetPortHealth
 }
 
 // Result is response of REST API
@@ -117,12 +132,24 @@ func NewClientDefaultToken(localIPs, remoteIPs []string, username, password, por
 		LocalDevice:   localDevice,
 		RemoteDevice:  remoteDevice,
 		PortGroupName: portgroupName,
+		DRHostStore:   NewMemoryDRHostStore(),
 		Logger:        logger,
 	}
 
 	return c, nil
 }
 
+// portGroupName return port group name for hostname
+func (c *Client) portGroupName(hostname string) string {
+	if c.PortGroupNameFunc != nil {
+		if name := c.PortGroupNameFunc(hostname); name != "" {
+			return name
+		}
+	}
+
+	return c.PortGroupName
+}
+
 func newDevice(ips []string, username, password string, httpClient *http.Client, logger *log.Logger) (*Device, error) {
 	var parsedURLs []*url.URL
 	for _, ipStr := range ips {
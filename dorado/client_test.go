@@ -2,6 +2,7 @@ package dorado
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -48,6 +49,28 @@ func testMethod(t *testing.T, r *http.Request, want string) {
 	}
 }
 
+// testDecodeBody decode JSON body of request. it is called in HTTP handlers, so report error by t.Errorf and return nil.
+func testDecodeBody(t *testing.T, r *http.Request) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		t.Errorf("failed to decode request body: %s", err)
+		return nil
+	}
+	return body
+}
+
+func testBody(t *testing.T, r *http.Request, want map[string]interface{}) {
+	t.Helper()
+	got := testDecodeBody(t, r)
+	if got == nil {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Request body: %+v, want %+v", got, want)
+	}
+}
+
 // TestDevice_UnAuthorizedRetry test retry function
 func TestDevice_UnAuthorizedRetry(t *testing.T) {
 	client, mux, _, teardown := setup()
@@ -15,18 +15,31 @@ const (
 
 // Object Type Numbers
 const (
-	TypeHost             = 21
-	TypeHostGroup        = 14
-	TypeLUN              = 11
-	TypeLUNGroup         = 256
-	TypeLUNCopy          = 219
-	TypeSnapshot         = 27
-	TypePortGroup        = 257
-	TypeInitiator        = 222
-	TypeMappingView      = 245
-	TypeEthernetPort     = 213
-	TypeHyperMetroPair   = 15361
-	TypeHyperMetroDomain = 15362
+	TypeHost                     = 21
+	TypeHostGroup                = 14
+	TypeLUN                      = 11
+	TypeLUNGroup                 = 256
+	TypeLUNCopy                  = 219
+	TypeLUNMigration             = 253
+	TypeSnapshot                 = 27
+	TypeProtectionGroup          = 57956
+	TypeSnapshotConsistencyGroup = 57955
+	TypePortGroup                = 257
+	TypeInitiator                = 222
+	TypeFCInitiator              = 223
+	TypeFCPort                   = 212
+	TypeMappingView              = 245
+	TypeEthernetPort             = 213
+	TypeBondPort                 = 235
+	TypeLogicalPort              = 279
+	TypeVLAN                     = 280
+	TypeHyperMetroPair           = 15361
+	TypeHyperMetroDomain         = 15362
+	TypeHyperMetroCG             = 15364
+	TypeRemoteReplicationPair    = 263
+	TypeSmartQoS                 = 230
+	TypeNVMeOverRoCEInitiator    = 57870
+	TypeNVMeOverTCPInitiator     = 57871
 )
 
 // For HyperMetroPair RUNNINGSTATUS
@@ -46,37 +59,80 @@ const (
 
 // For a some RUNNNINGSTATUS
 const (
-	StatusVolumeReady      = 27
-	StatusLunCopyReady     = 40
-	StatusSnapshotActive   = 43
-	StatusSnapshotInactive = 45
+	StatusVolumeReady         = 27
+	StatusLunCopyReady        = 40
+	StatusSnapshotActive      = 43
+	StatusSnapshotRollingBack = 44
+	StatusSnapshotInactive    = 45
+)
+
+// For port (ex: Ethernet, FC) RUNNINGSTATUS
+const (
+	StatusLinkUp   = 10
+	StatusLinkDown = 11
+)
+
+// Speed is speed of background copy (ex: snapshot rollback, LUN copy, clone split)
+type Speed int
+
+// Speed const
+const (
+	SpeedLow     Speed = 1
+	SpeedMedium  Speed = 2
+	SpeedHigh    Speed = 3
+	SpeedHighest Speed = 4
 )
 
 // Dorado return Error Codes
 const (
 	ErrorCodeUnAuthorized  = -401
 	ErrorCodeUserIsOffline = 1077949069
+	ErrorCodeNotSupported  = 1077949002
 )
 
 // Error Values
 var (
-	ErrEthernetPortNotFound     = errors.New("ethernet port is not found")
-	ErrHostNotFound             = errors.New("host is not found")
-	ErrHostGroupNotFound        = errors.New("host group is not found")
-	ErrHyperMetroDomainNotFound = errors.New("HyperMetroDomain ID is not found")
-	ErrHyperMetroPairNotFound   = errors.New("HyperMetroPair is not found")
-	ErrInitiatorNotFound        = errors.New("initiator is not found")
-	ErrLunNotFound              = errors.New("LUN is not found")
-	ErrLunGroupNotFound         = errors.New("LUN Group is not found")
-	ErrLunCopyNotFound          = errors.New("LUN Copy is not found")
-	ErrMappingViewNotFound      = errors.New("mapping view is not found")
-	ErrPortGroupNotFound        = errors.New("port group is not found")
-	ErrSnapshotNotFound         = errors.New("snapshot is not found")
-	ErrStoragePoolNotFound      = errors.New("storage pool is not found")
-	ErrTargetPortNotFound       = errors.New("target port is not found")
+	ErrEthernetPortNotFound             = errors.New("ethernet port is not found")
+	ErrFCInitiatorNotFound              = errors.New("FC initiator is not found")
+	ErrFCPortNotFound                   = errors.New("FC port is not found")
+	ErrHostNotFound                     = errors.New("host is not found")
+	ErrHostGroupNotFound                = errors.New("host group is not found")
+	ErrHyperMetroDomainNotFound         = errors.New("HyperMetroDomain ID is not found")
+	ErrHyperMetroPairNotFound           = errors.New("HyperMetroPair is not found")
+	ErrHyperMetroCGNotFound             = errors.New("HyperMetro consistency group is not found")
+	ErrInitiatorNotFound                = errors.New("initiator is not found")
+	ErrLogicalPortNotFound              = errors.New("logical port is not found")
+	ErrLunNotFound                      = errors.New("LUN is not found")
+	ErrLunGroupNotFound                 = errors.New("LUN Group is not found")
+	ErrLunCopyNotFound                  = errors.New("LUN Copy is not found")
+	ErrLunMigrationNotFound             = errors.New("LUN Migration is not found")
+	ErrMappingViewNotFound              = errors.New("mapping view is not found")
+	ErrNVMeInitiatorNotFound            = errors.New("NVMe initiator is not found")
+	ErrPortGroupNotFound                = errors.New("port group is not found")
+	ErrProtectionGroupNotFound          = errors.New("protection group is not found")
+	ErrQuorumServerNotFound             = errors.New("quorum server is not found")
+	ErrQuorumServerLinkNotFound         = errors.New("quorum server link is not found")
+	ErrRemoteArrayNotFound              = errors.New("remote array is not found")
+	ErrRemoteReplicationPairNotFound    = errors.New("RemoteReplicationPair is not found")
+	ErrSmartQoSNotFound                 = errors.New("SmartQoS is not found")
+	ErrSnapshotNotFound                 = errors.New("snapshot is not found")
+	ErrSnapshotConsistencyGroupNotFound = errors.New("snapshot consistency group is not found")
+	ErrStoragePoolNotFound              = errors.New("storage pool is not found")
+	ErrTargetPortNotFound               = errors.New("target port is not found")
+	ErrVLANNotFound                     = errors.New("VLAN is not found")
 
 	ErrUnAuthorized = errors.New("failed to authorized token")
 	ErrTimeoutWait  = errors.New("timeout to wait")
+	ErrNotSupported = errors.New("operation is not supported by device")
+
+	ErrHasDependents = errors.New("object has dependents")
+
+	ErrLunCopyPaused = errors.New("LUN Copy is paused")
+
+	ErrInvalidProtectionMode = errors.New("invalid protection mode")
+
+	ErrVolumeIsAttached         = errors.New("volume is attached to host")
+	ErrMigratedSourceNotDeleted = errors.New("volume is migrated, but failed to delete source volume")
 
 	// parent Error
 	ErrCreateRequest    = "failed to create request"
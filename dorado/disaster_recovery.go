@@ -0,0 +1,690 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DRStepStatus is result of step in DR workflow
+type DRStepStatus string
+
+// DRStepStatus const
+const (
+	DRStepDone    DRStepStatus = "Done"
+	DRStepSkipped DRStepStatus = "Skipped" // already done (ex: resumed workflow)
+	DRStepFailed  DRStepStatus = "Failed"
+)
+
+// DRStep is step of DR workflow
+type DRStep struct {
+	Name   string
+	Status DRStepStatus
+	Err    error
+}
+
+// DRReport is step-by-step report of DR workflow.
+// workflows are idempotent, so call same workflow again to resume after failed.
+type DRReport struct {
+	Workflow string
+	PairID   string
+	Steps    []DRStep
+	// Hosts are mapped to source LUN before detach, and remapped to target LUN.
+	Hosts []DRHost
+}
+
+// String is function compatible for fmt.Stringer
+func (r *DRReport) String() string {
+	s := fmt.Sprintf("%s (RemoteReplicationPair: %s)", r.Workflow, r.PairID)
+	for i, step := range r.Steps {
+		s += fmt.Sprintf("\n%d: %s: %s", i+1, step.Name, step.Status)
+		if step.Err != nil {
+			s += fmt.Sprintf(" (%v)", step.Err)
+		}
+	}
+	return s
+}
+
+// run execute step. fn return false if step is already done.
+func (r *DRReport) run(name string, fn func() (bool, error)) error {
+	executed, err := fn()
+	switch {
+	case err != nil:
+		r.Steps = append(r.Steps, DRStep{Name: name, Status: DRStepFailed, Err: err})
+		return fmt.Errorf("failed to %s: %w", name, err)
+	case executed:
+		r.Steps = append(r.Steps, DRStep{Name: name, Status: DRStepDone})
+	default:
+		r.Steps = append(r.Steps, DRStep{Name: name, Status: DRStepSkipped})
+	}
+
+	return nil
+}
+
+// DRHost is host that volume is attached, a host that has multiple initiators is multiple DRHosts.
+type DRHost struct {
+	Hostname string
+	IQN      string
+}
+
+// DRHostStore persist hosts that are detached by DR workflows until they are remapped.
+// hosts can not be read from device after detached, so resumed workflow load them from store.
+// implement it by file or database to resume after process is restarted.
+type DRHostStore interface {
+	// SaveHosts save hosts of RemoteReplicationPair
+	SaveHosts(pairID string, hosts []DRHost) error
+	// LoadHosts return saved hosts of RemoteReplicationPair, empty if not saved
+	LoadHosts(pairID string) ([]DRHost, error)
+	// DeleteHosts delete saved hosts of RemoteReplicationPair after remapped
+	DeleteHosts(pairID string) error
+}
+
+type memoryDRHostStore struct {
+	mu    sync.Mutex
+	hosts map[string][]DRHost
+}
+
+// NewMemoryDRHostStore create DRHostStore in memory, hosts are lost when process is restarted.
+func NewMemoryDRHostStore() DRHostStore {
+	return &memoryDRHostStore{hosts: map[string][]DRHost{}}
+}
+
+func (s *memoryDRHostStore) SaveHosts(pairID string, hosts []DRHost) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hosts[pairID] = append([]DRHost(nil), hosts...)
+	return nil
+}
+
+func (s *memoryDRHostStore) LoadHosts(pairID string) ([]DRHost, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DRHost(nil), s.hosts[pairID]...), nil
+}
+
+func (s *memoryDRHostStore) DeleteHosts(pairID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.hosts, pairID)
+	return nil
+}
+
+// DefaultDRSyncTimeout is default timeout of final sync in DR workflows
+var DefaultDRSyncTimeout = 1 * time.Hour
+
+// DROption is option of DR workflows
+type DROption struct {
+	// Host is attached to LUN in surviving device in addition to hosts that are mapped to source LUN.
+	Host *DRHost
+	// SyncTimeout is max duration to wait final sync. 0 is DefaultDRSyncTimeout.
+	SyncTimeout time.Duration
+	// Force run UnplannedFailover even if RemoteReplicationPair is not interrupted.
+	// host must be stopped in LocalDevice by operator, or both LUNs become writable (split-brain).
+	Force bool
+}
+
+// NewDROption create default DROption
+func NewDROption() *DROption {
+	return &DROption{
+		Host:        nil,
+		SyncTimeout: DefaultDRSyncTimeout,
+		Force:       false,
+	}
+}
+
+func (o *DROption) withDefault() *DROption {
+	opt := NewDROption()
+	if o == nil {
+		return opt
+	}
+
+	opt.Host = o.Host
+	opt.Force = o.Force
+	if o.SyncTimeout > 0 {
+		opt.SyncTimeout = o.SyncTimeout
+	}
+	return opt
+}
+
+// drSide is a device and RemoteReplicationPair in a view of device
+type drSide struct {
+	device *Device
+	pair   *RemoteReplicationPair
+}
+
+func (s *drSide) refresh(ctx context.Context) error {
+	pair, err := s.device.GetRemoteReplicationPair(ctx, s.pair.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get RemoteReplicationPair: %w", err)
+	}
+	s.pair = pair
+	return nil
+}
+
+func (s *drSide) isPrimary() bool {
+	return s.pair.ISPRIMARY == "true"
+}
+
+func newDRSide(ctx context.Context, d *Device, pairID string) (*drSide, error) {
+	pair, err := d.GetRemoteReplicationPair(ctx, pairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RemoteReplicationPair: %w", err)
+	}
+	return &drSide{device: d, pair: pair}, nil
+}
+
+// PlannedFailover move primary of RemoteReplicationPair from LocalDevice to RemoteDevice without data loss.
+// host is detached from LocalDevice, data written until detach is synchronized,
+// and host is attached to RemoteDevice. replication is continued from RemoteDevice to LocalDevice.
+func (c *Client) PlannedFailover(ctx context.Context, pairID string, opt *DROption) (*DRReport, error) {
+	report := &DRReport{Workflow: "PlannedFailover", PairID: pairID}
+	if err := c.requireProtectionMode(ProtectionAsyncReplication); err != nil {
+		return report, err
+	}
+
+	return report, c.switchover(ctx, report, c.LocalDevice, c.RemoteDevice, pairID, opt.withDefault())
+}
+
+// UnplannedFailover promote secondary LUN in RemoteDevice when LocalDevice is down.
+// data that is not replicated yet is lost. call Failback after LocalDevice is recovered.
+// RemoteReplicationPair must be Interrupted or Invalid (link to LocalDevice is down) unless opt.Force is true.
+func (c *Client) UnplannedFailover(ctx context.Context, pairID string, opt *DROption) (*DRReport, error) {
+	report := &DRReport{Workflow: "UnplannedFailover", PairID: pairID}
+	if err := c.requireProtectionMode(ProtectionAsyncReplication); err != nil {
+		return report, err
+	}
+	opt = opt.withDefault()
+
+	// all operations are in RemoteDevice, LocalDevice may be down
+	to, err := newDRSide(ctx, c.RemoteDevice, pairID)
+	if err != nil {
+		return report, err
+	}
+
+	err = report.run("check RemoteReplicationPair", func() (bool, error) {
+		switch {
+		case to.pair.State() == ReplicationStateInterrupted, to.pair.State() == ReplicationStateInvalid:
+		case to.isPrimary(), !to.pair.IsSecondaryWriteProtected():
+			// already failed over
+		case opt.Force:
+		default:
+			return false, fmt.Errorf("RemoteReplicationPair is %s, LocalDevice may be serving host (stop host and use Force)", to.pair.State())
+		}
+		return true, nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	err = report.run("split replication", func() (bool, error) {
+		switch to.pair.State() {
+		case ReplicationStateSplit, ReplicationStateInterrupted, ReplicationStateInvalid:
+			// replication is already stopped
+			return false, nil
+		}
+		return true, to.device.SplitRemoteReplicationPair(ctx, pairID)
+	})
+	if err != nil {
+		return report, err
+	}
+
+	err = report.run("enable write to secondary LUN", func() (bool, error) {
+		if err := to.refresh(ctx); err != nil {
+			return false, err
+		}
+		if to.isPrimary() || !to.pair.IsSecondaryWriteProtected() {
+			return false, nil
+		}
+		return true, to.device.SetSecondaryWriteProtect(ctx, pairID, false)
+	})
+	if err != nil {
+		return report, err
+	}
+
+	err = report.run("attach host to RemoteDevice", func() (bool, error) {
+		// LocalDevice may be down, so only hosts that are saved by previous workflow and opt.Host are attached
+		hosts, err := c.loadDRHosts(pairID)
+		if err != nil {
+			return false, err
+		}
+		report.Hosts = hosts
+		return c.drAttach(ctx, to, report.Hosts, opt.Host)
+	})
+
+	return report, err
+}
+
+// Failback move primary of RemoteReplicationPair back to LocalDevice after PlannedFailover or UnplannedFailover.
+// stale mapping in LocalDevice is removed, and data written in RemoteDevice is synchronized to LocalDevice before switch.
+func (c *Client) Failback(ctx context.Context, pairID string, opt *DROption) (*DRReport, error) {
+	report := &DRReport{Workflow: "Failback", PairID: pairID}
+	if err := c.requireProtectionMode(ProtectionAsyncReplication); err != nil {
+		return report, err
+	}
+	opt = opt.withDefault()
+
+	local, err := newDRSide(ctx, c.LocalDevice, pairID)
+	if err != nil {
+		return report, err
+	}
+	from, err := newDRSide(ctx, c.RemoteDevice, pairID)
+	if err != nil {
+		return report, err
+	}
+
+	// after UnplannedFailover, LocalDevice LUN may be mapped yet and will be overwritten by reverse sync
+	err = report.run("detach host from LocalDevice", func() (bool, error) {
+		return c.drDetach(ctx, local)
+	})
+	if err != nil {
+		return report, err
+	}
+
+	// after UnplannedFailover, RemoteDevice is writable secondary and has newest data.
+	// protected secondary has not been failed over (or failback is already switched).
+	err = report.run("promote RemoteDevice to primary", func() (bool, error) {
+		if from.isPrimary() || from.pair.IsSecondaryWriteProtected() {
+			return false, nil
+		}
+		switch from.pair.State() {
+		case ReplicationStateSplit, ReplicationStateInterrupted, ReplicationStateInvalid:
+		default:
+			if err := from.device.SplitRemoteReplicationPair(ctx, pairID); err != nil {
+				return false, fmt.Errorf("failed to split: %w", err)
+			}
+		}
+		return true, from.device.SwitchRemoteReplicationPair(ctx, pairID)
+	})
+	if err != nil {
+		return report, err
+	}
+
+	err = report.run("protect LocalDevice LUN", func() (bool, error) {
+		if err := from.refresh(ctx); err != nil {
+			return false, err
+		}
+		if !from.isPrimary() || from.pair.IsSecondaryWriteProtected() {
+			return false, nil
+		}
+		return true, from.device.SetSecondaryWriteProtect(ctx, pairID, true)
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if err := c.switchover(ctx, report, c.RemoteDevice, c.LocalDevice, pairID, opt); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// switchover move primary of RemoteReplicationPair from -> to with final sync.
+func (c *Client) switchover(ctx context.Context, report *DRReport, fromDevice, toDevice *Device, pairID string, opt *DROption) error {
+	from, err := newDRSide(ctx, fromDevice, pairID)
+	if err != nil {
+		return err
+	}
+	to := &drSide{device: toDevice, pair: &RemoteReplicationPair{ID: pairID}}
+
+	err = report.run("check RemoteReplicationPair", func() (bool, error) {
+		switch from.pair.State() {
+		case ReplicationStateInvalid, ReplicationStateInterrupted:
+			return false, fmt.Errorf("RemoteReplicationPair is %s, use UnplannedFailover", from.pair.State())
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = report.run("record host of source", func() (bool, error) {
+		hosts, err := c.loadDRHosts(pairID)
+		if err != nil {
+			return false, err
+		}
+		if len(hosts) != 0 {
+			// saved by interrupted workflow, source LUN may be already detached
+			report.Hosts = hosts
+			return false, nil
+		}
+
+		hosts, err = from.device.getMappedHosts(ctx, from.pair.LOCALRESID)
+		if err != nil {
+			return false, err
+		}
+		if len(hosts) == 0 {
+			return false, nil
+		}
+		report.Hosts = hosts
+		return true, c.saveDRHosts(pairID, hosts)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = report.run("detach host from source", func() (bool, error) {
+		return c.drDetach(ctx, from)
+	})
+	if err != nil {
+		return err
+	}
+
+	// sync even if Normal, data written after last sync is not replicated yet
+	err = report.run("final sync", func() (bool, error) {
+		if !from.isPrimary() {
+			return false, nil
+		}
+		return true, syncRemoteReplicationPairWithWait(ctx, from.device, pairID, opt.SyncTimeout)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = report.run("split replication", func() (bool, error) {
+		if err := from.refresh(ctx); err != nil {
+			return false, err
+		}
+		if !from.isPrimary() || from.pair.State() == ReplicationStateSplit {
+			return false, nil
+		}
+		return true, from.device.SplitRemoteReplicationPair(ctx, pairID)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = report.run("switch primary", func() (bool, error) {
+		if err := from.refresh(ctx); err != nil {
+			return false, err
+		}
+		if !from.isPrimary() {
+			return false, nil
+		}
+		return true, from.device.SwitchRemoteReplicationPair(ctx, pairID)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = report.run("protect new secondary LUN", func() (bool, error) {
+		if err := to.refresh(ctx); err != nil {
+			return false, err
+		}
+		if to.pair.IsSecondaryWriteProtected() {
+			return false, nil
+		}
+		return true, to.device.SetSecondaryWriteProtect(ctx, pairID, true)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = report.run("start reverse replication", func() (bool, error) {
+		if to.pair.State() != ReplicationStateSplit {
+			return false, nil
+		}
+		return true, to.device.SyncRemoteReplicationPair(ctx, pairID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return report.run("attach host to target", func() (bool, error) {
+		return c.drAttach(ctx, to, report.Hosts, opt.Host)
+	})
+}
+
+// drAttach attach LUN in side to hosts and extra host (nil is ignored). return false if LUN is already attached to all hosts.
+// CHAP setting of initiators in side is kept. saved hosts are deleted after attached.
+func (c *Client) drAttach(ctx context.Context, side *drSide, hosts []DRHost, extra *DRHost) (bool, error) {
+	if extra != nil {
+		hosts = append(hosts, *extra)
+	}
+	if len(hosts) == 0 {
+		return false, nil
+	}
+
+	lun, err := side.device.GetLUN(ctx, side.pair.LOCALRESID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get LUN: %w", err)
+	}
+	lungroups, err := side.device.getLUNLunGroups(ctx, lun.ID)
+	if err != nil {
+		return false, err
+	}
+	attached := map[string]bool{}
+	for _, lungroup := range lungroups {
+		attached[lungroupHostname(lungroup)] = true
+	}
+
+	var hostnames []string
+	iqns := map[string][]string{}
+	for _, host := range hosts {
+		if _, ok := iqns[host.Hostname]; !ok {
+			hostnames = append(hostnames, host.Hostname)
+		}
+		iqns[host.Hostname] = appendIfMissing(iqns[host.Hostname], host.IQN)
+	}
+
+	executed := false
+	for _, hostname := range hostnames {
+		if attached[hostname] {
+			continue
+		}
+
+		setInitiators := func(host *Host) error {
+			for _, iqn := range iqns[hostname] {
+				if err := side.device.keepISCSIInitiator(ctx, iqn)(host); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if _, _, err := side.device.attachVolume(ctx, c.portGroupName(hostname), hostname, lun.ID, setInitiators); err != nil {
+			return executed, fmt.Errorf("failed to attach host (hostname: %s): %w", hostname, err)
+		}
+		executed = true
+	}
+
+	return executed, c.deleteDRHosts(side.pair.ID)
+}
+
+// drDetach detach LUN in side from all hosts. return false if LUN is not attached.
+func (c *Client) drDetach(ctx context.Context, side *drSide) (bool, error) {
+	lun, err := side.device.GetLUN(ctx, side.pair.LOCALRESID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get LUN: %w", err)
+	}
+	if !lun.ISADD2LUNGROUP {
+		return false, nil
+	}
+
+	lungroups, err := side.device.getLUNLunGroups(ctx, lun.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, lungroup := range lungroups {
+		if err := side.device.DisAssociateLun(ctx, lungroup.ID, lun.ID); err != nil {
+			return false, fmt.Errorf("failed to disassociate lun (lungroup: %d): %w", lungroup.ID, err)
+		}
+	}
+
+	return true, nil
+}
+
+// getMappedHosts return hosts and iSCSI initiators that LUN is mapped to.
+func (d *Device) getMappedHosts(ctx context.Context, lunID int) ([]DRHost, error) {
+	lungroups, err := d.getLUNLunGroups(ctx, lunID)
+	if err != nil {
+		return nil, err
+	}
+
+	var drHosts []DRHost
+	for _, lungroup := range lungroups {
+		hostname := lungroupHostname(lungroup)
+		hosts, err := d.GetHosts(ctx, NewSearchQueryHostname(hostname))
+		if err == ErrHostNotFound {
+			d.Logger.Printf("host of lungroup is not found (lungroup: %s)\n", lungroup.NAME)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get host (hostname: %s): %w", hostname, err)
+		}
+
+		for _, host := range hosts {
+			initiators, err := d.GetInitiators(ctx, &SearchQuery{Filter: ToFilter("PARENTID", strconv.Itoa(host.ID))})
+			if err == ErrInitiatorNotFound {
+				d.Logger.Printf("iSCSI initiator of host is not found (hostname: %s)\n", hostname)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to get initiators (hostname: %s): %w", hostname, err)
+			}
+			for _, initiator := range initiators {
+				drHosts = append(drHosts, DRHost{Hostname: hostname, IQN: initiator.ID})
+			}
+		}
+	}
+
+	return drHosts, nil
+}
+
+// getLUNLunGroups return all lun groups that LUN is associated.
+func (d *Device) getLUNLunGroups(ctx context.Context, lunID int) ([]LunGroup, error) {
+	lungroups, err := d.GetAssociateLunGroups(ctx, &SearchQuery{
+		AssociateObjType: strconv.Itoa(TypeLUN),
+		AssociateObjID:   strconv.Itoa(lunID),
+		Type:             strconv.Itoa(TypeLUNGroup),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lun group: %w", err)
+	}
+
+	return lungroups, nil
+}
+
+// lungroupHostname return hostname of lun group, DESCRIPTION is hostname if created by CreateLunGroup.
+func lungroupHostname(lungroup LunGroup) string {
+	if lungroup.DESCRIPTION != "" {
+		return lungroup.DESCRIPTION
+	}
+	return lungroup.NAME
+}
+
+func appendIfMissing(list []string, s string) []string {
+	for _, l := range list {
+		if l == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+func (c *Client) loadDRHosts(pairID string) ([]DRHost, error) {
+	if c.DRHostStore == nil {
+		return nil, nil
+	}
+	hosts, err := c.DRHostStore.LoadHosts(pairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load hosts: %w", err)
+	}
+	return hosts, nil
+}
+
+func (c *Client) saveDRHosts(pairID string, hosts []DRHost) error {
+	if c.DRHostStore == nil {
+		return nil
+	}
+	if err := c.DRHostStore.SaveHosts(pairID, hosts); err != nil {
+		return fmt.Errorf("failed to save hosts: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) deleteDRHosts(pairID string) error {
+	if c.DRHostStore == nil {
+		return nil
+	}
+	if err := c.DRHostStore.DeleteHosts(pairID); err != nil {
+		return fmt.Errorf("failed to delete saved hosts: %w", err)
+	}
+	return nil
+}
+
+// syncRemoteReplicationPairWithWait start sync and wait that it is completed.
+// a running sync may be started before host is detached, so a new sync is started after it.
+func syncRemoteReplicationPairWithWait(ctx context.Context, d *Device, pairID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	pair, err := d.GetRemoteReplicationPair(ctx, pairID)
+	if err != nil {
+		return fmt.Errorf("failed to get RemoteReplicationPair: %w", err)
+	}
+	if pair.State() == ReplicationStateSynchronizing {
+		if pair, err = waitRemoteReplicationPairSynced(ctx, d, pairID, "", deadline); err != nil {
+			return fmt.Errorf("failed to wait running sync: %w", err)
+		}
+	}
+
+	if err := d.SyncRemoteReplicationPair(ctx, pairID); err != nil {
+		return fmt.Errorf("failed to start sync: %w", err)
+	}
+
+	if _, err := waitRemoteReplicationPairSynced(ctx, d, pairID, pair.STARTTIME, deadline); err != nil {
+		return err
+	}
+	return nil
+}
+
+// waitRemoteReplicationPairSynced wait that a sync started after lastStart (STARTTIME) is completed.
+// empty lastStart wait that RemoteReplicationPair become Normal.
+func waitRemoteReplicationPairSynced(ctx context.Context, d *Device, pairID, lastStart string, deadline time.Time) (*RemoteReplicationPair, error) {
+	synchronizing := false
+
+	for {
+		pair, err := d.GetRemoteReplicationPair(ctx, pairID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get RemoteReplicationPair: %w", err)
+		}
+
+		switch pair.State() {
+		case ReplicationStateSynchronizing:
+			synchronizing = true
+		case ReplicationStateNormal:
+			if lastStart == "" || synchronizing || syncedAfter(pair, lastStart) {
+				return pair, nil
+			}
+		case ReplicationStateInterrupted, ReplicationStateInvalid, ReplicationStateSplit:
+			return nil, fmt.Errorf("RemoteReplicationPair is %s while sync", pair.State())
+		}
+
+		if time.Now().After(deadline) {
+			return nil, ErrTimeoutWait
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
+	}
+}
+
+// syncedAfter return true if a sync that started after lastStart is completed
+func syncedAfter(pair *RemoteReplicationPair, lastStart string) bool {
+	if pair.STARTTIME == lastStart {
+		return false
+	}
+
+	start, err := strconv.ParseInt(pair.STARTTIME, 10, 64)
+	if err != nil {
+		return false
+	}
+	end, err := strconv.ParseInt(pair.ENDTIME, 10, 64)
+	if err != nil {
+		return false
+	}
+	return end >= start
+}
@@ -0,0 +1,438 @@
+package dorado
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testDRPairID = "6e4c2d1100eaf02c0001"
+
+// fakeReplication emulate a RemoteReplicationPair and mappings of its LUNs in LocalDevice ("local") and RemoteDevice ("remote").
+type fakeReplication struct {
+	t  *testing.T
+	mu sync.Mutex
+
+	primary           string
+	state             RemoteReplicationPairState
+	secondaryWritable bool
+	syncCount         int
+	attached          map[string]bool
+	down              map[string]bool
+
+	calls  []string // mutating requests in order
+	failAt int      // index of mutating request that return error, -1 is never
+}
+
+var fakeReplicationLUNIDs = map[string]int{"local": 148, "remote": 151}
+
+func newFakeReplication(t *testing.T) *fakeReplication {
+	return &fakeReplication{
+		t:        t,
+		primary:  "local",
+		state:    ReplicationStateNormal,
+		attached: map[string]bool{},
+		down:     map[string]bool{},
+		failAt:   -1,
+	}
+}
+
+func otherSide(side string) string {
+	if side == "local" {
+		return "remote"
+	}
+	return "local"
+}
+
+// setupDR create client that LocalDevice and RemoteDevice are served by fake
+func setupDR(t *testing.T, fake *fakeReplication) (*Client, func()) {
+	client, localMux, _, teardown := setup()
+	fake.register(localMux, "local")
+
+	remoteMux := http.NewServeMux()
+	apiHandler := http.NewServeMux()
+	apiHandler.Handle(baseURLTestPath+"/", http.StripPrefix(baseURLTestPath, remoteMux))
+	remoteServer := httptest.NewServer(apiHandler)
+	if err := client.RemoteDevice.setBaseURL(remoteServer.URL, DefaultDeviceID); err != nil {
+		log.Fatalf("failed to set baseURL in remote devive: %s", err)
+	}
+	fake.register(remoteMux, "remote")
+
+	client.ProtectionMode = ProtectionAsyncReplication
+	return client, func() {
+		remoteServer.Close()
+		teardown()
+	}
+}
+
+func (f *fakeReplication) register(mux *http.ServeMux, side string) {
+	handle := func(pattern string, fn func(w http.ResponseWriter, r *http.Request)) {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if f.down[side] {
+				f.t.Errorf("request to down device (%s): %s %s", side, r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fn(w, r)
+		})
+	}
+
+	handle("/REPLICATIONPAIR/", func(w http.ResponseWriter, r *http.Request) {
+		action := strings.TrimPrefix(r.URL.Path, "/REPLICATIONPAIR/")
+		if r.Method == "GET" && action == testDRPairID {
+			f.writePair(w, side)
+			return
+		}
+		testMethod(f.t, r, "PUT")
+		f.mutate(w, side, action, func() error {
+			switch action {
+			case "split":
+				f.state = ReplicationStateSplit
+			case "sync":
+				// sync is completed immediately
+				f.state = ReplicationStateNormal
+				f.syncCount++
+			case "switch":
+				if f.state != ReplicationStateSplit {
+					return errors.New("pair is not split")
+				}
+				f.primary = otherSide(f.primary)
+			case "SET_SECODARY_WRITE_LOCK":
+				f.secondaryWritable = false
+			case "CANCEL_SECODARY_WRITE_LOCK":
+				if f.state != ReplicationStateSplit && f.state != ReplicationStateInterrupted {
+					return errors.New("pair is not split")
+				}
+				f.secondaryWritable = true
+			default:
+				f.t.Errorf("unexpected action: %s", action)
+			}
+			return nil
+		})
+	})
+	handle(fmt.Sprintf("/lun/%d", fakeReplicationLUNIDs[side]), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(f.t, r, "GET")
+		fmt.Fprintf(w, `{"data": {"ID": "%d", "ISADD2LUNGROUP": "%t", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`,
+			fakeReplicationLUNIDs[side], f.attached[side])
+	})
+	handle("/lungroup/associate", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			if !f.attached[side] {
+				fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data": [{"ID": "4", "NAME": "host001", "DESCRIPTION": "host001", "ISADD2MAPPINGVIEW": "true", "TYPE": 256}], "error": {"code": 0, "description": "0"}}`)
+		case "POST":
+			f.mutate(w, side, "attach", func() error {
+				f.attached[side] = true
+				return nil
+			})
+		case "DELETE":
+			f.mutate(w, side, "detach", func() error {
+				f.attached[side] = false
+				return nil
+			})
+		default:
+			f.t.Errorf("Request method: %v, want GET, POST or DELETE", r.Method)
+		}
+	})
+
+	// host "host001" (ID: 3) with initiator testIQN already exists and is mapped to "portgroup" (ID: 1) in both devices
+	for pattern, body := range map[string]string{
+		"/host":                `[{"ID": "3", "NAME": "host001", "ISADD2HOSTGROUP": "true"}]`,
+		"/hostgroup":           `[{"ID": "2", "NAME": "host001", "ISADD2MAPPINGVIEW": "true"}]`,
+		"/iscsi_initiator":     fmt.Sprintf(`[{"ID": "%s", "PARENTID": "3", "PARENTTYPE": 21, "TYPE": 222}]`, testIQN),
+		"/lungroup":            `[{"ID": "4", "NAME": "host001", "DESCRIPTION": "host001", "ISADD2MAPPINGVIEW": "true"}]`,
+		"/mappingview":         `[{"ID": "5", "NAME": "host001"}]`,
+		"/portgroup":           `[{"ID": "1", "NAME": "portgroup"}]`,
+		"/portgroup/associate": `[{"ID": "1", "NAME": "portgroup"}]`,
+	} {
+		body := body
+		handle(pattern, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(f.t, r, "GET")
+			fmt.Fprintf(w, `{"data": %s, "error": {"code": 0, "description": "0"}}`, body)
+		})
+	}
+}
+
+func (f *fakeReplication) mutate(w http.ResponseWriter, side, action string, fn func() error) {
+	call := side + ":" + action
+	if len(f.calls) == f.failAt {
+		f.failAt = -1
+		fmt.Fprintf(w, `{"data": {}, "error": {"code": 1077949001, "description": "injected failure: %s"}}`, call)
+		return
+	}
+	if err := fn(); err != nil {
+		fmt.Fprintf(w, `{"data": {}, "error": {"code": 1077949002, "description": "%s: %s"}}`, call, err)
+		return
+	}
+	f.calls = append(f.calls, call)
+	fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+}
+
+func (f *fakeReplication) writePair(w http.ResponseWriter, side string) {
+	access := secondaryAccessReadOnly
+	if f.secondaryWritable {
+		access = secondaryAccessReadWrite
+	}
+	synced := strconv.Itoa(1600000000 + f.syncCount)
+
+	fmt.Fprintf(w, `{"data": {"ID": "%s", "ISPRIMARY": "%t", "LOCALRESID": "%d", "REMOTERESID": "%d", "RUNNINGSTATUS": "%d", "SECRESACCESS": "%s", "STARTTIME": "%s", "ENDTIME": "%s", "TYPE": 263}, "error": {"code": 0, "description": "0"}}`,
+		testDRPairID, f.primary == side, fakeReplicationLUNIDs[side], fakeReplicationLUNIDs[otherSide(side)], f.state, access, synced, synced)
+}
+
+func (f *fakeReplication) countCalls(call string) int {
+	count := 0
+	for _, c := range f.calls {
+		if c == call {
+			count++
+		}
+	}
+	return count
+}
+
+func stepStatuses(report *DRReport) []string {
+	var statuses []string
+	for _, step := range report.Steps {
+		statuses = append(statuses, step.Name+": "+string(step.Status))
+	}
+	return statuses
+}
+
+type drWorkflow func(c *Client, ctx context.Context, pairID string, opt *DROption) (*DRReport, error)
+
+// testDRResume interrupt workflow at each mutating request of fresh run, and check that re-run reach the same state as fresh run.
+func testDRResume(t *testing.T, freshCalls int, prepare func(f *fakeReplication), workflow drWorkflow, check func(t *testing.T, f *fakeReplication)) {
+	t.Helper()
+
+	for i := 0; i < freshCalls; i++ {
+		fake := newFakeReplication(t)
+		prepare(fake)
+		fake.failAt = i
+
+		client, teardown := setupDR(t, fake)
+		report, err := workflow(client, context.Background(), testDRPairID, nil)
+		if err == nil {
+			t.Errorf("interrupted at %d: workflow must return err", i)
+		} else if last := report.Steps[len(report.Steps)-1]; last.Status != DRStepFailed {
+			t.Errorf("interrupted at %d: last step is %+v, want Failed", i, last)
+		}
+
+		report, err = workflow(client, context.Background(), testDRPairID, nil)
+		if err != nil {
+			t.Errorf("resumed after %d: workflow return err: %s\n%s", i, err, report)
+		}
+		check(t, fake)
+		teardown()
+	}
+}
+
+func TestClient_PlannedFailover(t *testing.T) {
+	prepare := func(f *fakeReplication) {
+		f.attached["local"] = true
+	}
+	check := func(t *testing.T, f *fakeReplication) {
+		t.Helper()
+		if f.primary != "remote" || f.state != ReplicationStateNormal || f.secondaryWritable || f.attached["local"] || !f.attached["remote"] {
+			t.Errorf("PlannedFailover result %+v", f)
+		}
+		if got := f.countCalls("local:switch"); got != 1 {
+			t.Errorf("switched %d times, want 1", got)
+		}
+		// final sync must be issued after host is detached
+		detach, sync := -1, -1
+		for i, c := range f.calls {
+			switch c {
+			case "local:detach":
+				detach = i
+			case "local:sync":
+				sync = i
+			}
+		}
+		if sync < detach {
+			t.Errorf("final sync is not issued after detach: %v", f.calls)
+		}
+	}
+
+	fake := newFakeReplication(t)
+	prepare(fake)
+	client, teardown := setupDR(t, fake)
+	report, err := client.PlannedFailover(context.Background(), testDRPairID, nil)
+	teardown()
+	if err != nil {
+		t.Fatalf("PlannedFailover return err: %s", err)
+	}
+
+	wantCalls := []string{"local:detach", "local:sync", "local:split", "local:switch", "remote:sync", "remote:attach"}
+	if !reflect.DeepEqual(fake.calls, wantCalls) {
+		t.Errorf("PlannedFailover requests %v, want %v", fake.calls, wantCalls)
+	}
+	wantSteps := []string{
+		"check RemoteReplicationPair: Done",
+		"record host of source: Done",
+		"detach host from source: Done",
+		"final sync: Done",
+		"split replication: Done",
+		"switch primary: Done",
+		"protect new secondary LUN: Skipped",
+		"start reverse replication: Done",
+		"attach host to target: Done",
+	}
+	if got := stepStatuses(report); !reflect.DeepEqual(got, wantSteps) {
+		t.Errorf("PlannedFailover report %v, want %v", got, wantSteps)
+	}
+	wantHosts := []DRHost{{Hostname: "host001", IQN: testIQN}}
+	if !reflect.DeepEqual(report.Hosts, wantHosts) {
+		t.Errorf("PlannedFailover hosts %+v, want %+v", report.Hosts, wantHosts)
+	}
+	check(t, fake)
+
+	testDRResume(t, len(wantCalls), prepare, (*Client).PlannedFailover, check)
+}
+
+func TestClient_UnplannedFailover(t *testing.T) {
+	prepare := func(f *fakeReplication) {
+		f.state = ReplicationStateInterrupted
+		f.down["local"] = true
+	}
+	check := func(t *testing.T, f *fakeReplication) {
+		t.Helper()
+		if f.primary != "local" || !f.secondaryWritable {
+			t.Errorf("UnplannedFailover result %+v", f)
+		}
+	}
+
+	fake := newFakeReplication(t)
+	prepare(fake)
+	client, teardown := setupDR(t, fake)
+	report, err := client.UnplannedFailover(context.Background(), testDRPairID, nil)
+	if err != nil {
+		t.Fatalf("UnplannedFailover return err: %s", err)
+	}
+
+	wantCalls := []string{"remote:CANCEL_SECODARY_WRITE_LOCK"}
+	if !reflect.DeepEqual(fake.calls, wantCalls) {
+		t.Errorf("UnplannedFailover requests %v, want %v", fake.calls, wantCalls)
+	}
+	wantSteps := []string{
+		"check RemoteReplicationPair: Done",
+		"split replication: Skipped",
+		"enable write to secondary LUN: Done",
+		"attach host to RemoteDevice: Skipped",
+	}
+	if got := stepStatuses(report); !reflect.DeepEqual(got, wantSteps) {
+		t.Errorf("UnplannedFailover report %v, want %v", got, wantSteps)
+	}
+	check(t, fake)
+
+	// resume after completed
+	report, err = client.UnplannedFailover(context.Background(), testDRPairID, nil)
+	teardown()
+	if err != nil {
+		t.Fatalf("UnplannedFailover return err: %s", err)
+	}
+	if got := stepStatuses(report); got[2] != "enable write to secondary LUN: Skipped" || len(fake.calls) != 1 {
+		t.Errorf("resumed UnplannedFailover report %v, requests %v", got, fake.calls)
+	}
+
+	testDRResume(t, len(wantCalls), prepare, (*Client).UnplannedFailover, check)
+}
+
+func TestClient_UnplannedFailover_Healthy(t *testing.T) {
+	fake := newFakeReplication(t)
+	fake.attached["local"] = true
+	client, teardown := setupDR(t, fake)
+	defer teardown()
+
+	report, err := client.UnplannedFailover(context.Background(), testDRPairID, nil)
+	if err == nil {
+		t.Fatalf("UnplannedFailover must refuse healthy RemoteReplicationPair")
+	}
+	if len(fake.calls) != 0 || len(report.Steps) != 1 || report.Steps[0].Status != DRStepFailed {
+		t.Errorf("UnplannedFailover report %v, requests %v", stepStatuses(report), fake.calls)
+	}
+
+	// operator stopped host in LocalDevice
+	_, err = client.UnplannedFailover(context.Background(), testDRPairID, &DROption{Force: true})
+	if err != nil {
+		t.Fatalf("UnplannedFailover return err: %s", err)
+	}
+	wantCalls := []string{"remote:split", "remote:CANCEL_SECODARY_WRITE_LOCK"}
+	if !reflect.DeepEqual(fake.calls, wantCalls) {
+		t.Errorf("UnplannedFailover requests %v, want %v", fake.calls, wantCalls)
+	}
+}
+
+func TestClient_Failback(t *testing.T) {
+	// after UnplannedFailover and LocalDevice is recovered
+	prepare := func(f *fakeReplication) {
+		f.state = ReplicationStateSplit
+		f.secondaryWritable = true
+		f.attached["local"] = true
+		f.attached["remote"] = true
+	}
+	check := func(t *testing.T, f *fakeReplication) {
+		t.Helper()
+		if f.primary != "local" || f.state != ReplicationStateNormal || f.secondaryWritable || !f.attached["local"] || f.attached["remote"] {
+			t.Errorf("Failback result %+v", f)
+		}
+		if got := f.countCalls("remote:switch"); got != 2 {
+			t.Errorf("switched %d times, want 2: %v", got, f.calls)
+		}
+	}
+
+	fake := newFakeReplication(t)
+	prepare(fake)
+	client, teardown := setupDR(t, fake)
+	report, err := client.Failback(context.Background(), testDRPairID, nil)
+	teardown()
+	if err != nil {
+		t.Fatalf("Failback return err: %s", err)
+	}
+
+	wantCalls := []string{
+		"local:detach",
+		"remote:switch",
+		"remote:SET_SECODARY_WRITE_LOCK",
+		"remote:detach",
+		"remote:sync",
+		"remote:split",
+		"remote:switch",
+		"local:sync",
+		"local:attach",
+	}
+	if !reflect.DeepEqual(fake.calls, wantCalls) {
+		t.Errorf("Failback requests %v, want %v", fake.calls, wantCalls)
+	}
+	wantSteps := []string{
+		"detach host from LocalDevice: Done",
+		"promote RemoteDevice to primary: Done",
+		"protect LocalDevice LUN: Done",
+		"check RemoteReplicationPair: Done",
+		"record host of source: Done",
+		"detach host from source: Done",
+		"final sync: Done",
+		"split replication: Done",
+		"switch primary: Done",
+		"protect new secondary LUN: Skipped",
+		"start reverse replication: Done",
+		"attach host to target: Done",
+	}
+	if got := stepStatuses(report); !reflect.DeepEqual(got, wantSteps) {
+		t.Errorf("Failback report %v, want %v", got, wantSteps)
+	}
+	check(t, fake)
+
+	testDRResume(t, len(wantCalls), prepare, (*Client).Failback, check)
+}
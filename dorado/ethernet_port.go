@@ -3,6 +3,7 @@ package dorado
 import (
 	"context"
 	"fmt"
+	"net"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -84,7 +85,7 @@ func (d *Device) GetAssociatedEthernetPort(ctx context.Context, query *SearchQue
 }
 
 // GetPortalIPAddresses get iSCSI portal IP addresses that associated port group.
-// return only IPv4 address.
+// return only IPv4 address. use GetPortals if you need IPv6 address or logical port.
 func (d *Device) GetPortalIPAddresses(ctx context.Context, portgroupID int) ([]string, error) {
 	query := &SearchQuery{
 		AssociateObjID:   strconv.Itoa(portgroupID),
@@ -123,3 +124,209 @@ func (c *Client) GetPortalIPAddresses(ctx context.Context, localPortgroupID, rem
 	ips := append(localIPs, remoteIPs...)
 	return ips, nil
 }
+
+// AddressFamily is preference of IP address family for portal addresses
+type AddressFamily int
+
+// AddressFamily const
+const (
+	AddressFamilyIPv4       AddressFamily = iota // only IPv4
+	AddressFamilyIPv6                            // only IPv6
+	AddressFamilyPreferIPv4                      // IPv4, or IPv6 if port has not IPv4 address
+	AddressFamilyPreferIPv6                      // IPv6, or IPv4 if port has not IPv6 address
+	AddressFamilyBoth                            // IPv4 and IPv6
+)
+
+// selectAddresses select addresses by AddressFamily
+func (af AddressFamily) selectAddresses(ipv4, ipv6 string) []string {
+	var addresses []string
+	switch af {
+	case AddressFamilyIPv4:
+		addresses = []string{ipv4}
+	case AddressFamilyIPv6:
+		addresses = []string{ipv6}
+	case AddressFamilyPreferIPv4:
+		addresses = []string{ipv4}
+		if ipv4 == "" {
+			addresses = []string{ipv6}
+		}
+	case AddressFamilyPreferIPv6:
+		addresses = []string{ipv6}
+		if ipv6 == "" {
+			addresses = []string{ipv4}
+		}
+	case AddressFamilyBoth:
+		addresses = []string{ipv4, ipv6}
+	}
+
+	var selected []string
+	for _, address := range addresses {
+		if address != "" {
+			selected = append(selected, address)
+		}
+	}
+	return selected
+}
+
+// Port values
+const (
+	DefaultISCSITCPPort = 3260
+
+	// InvalidBondID is BONDID of ethernet port that not in bond port
+	InvalidBondID = "18446744073709551615"
+)
+
+// iSCSITCPPort return TCP port number of iSCSI service
+func (e *EthernetPort) iSCSITCPPort() int {
+	port, err := strconv.Atoi(e.ISCSITCPPORT)
+	if err != nil || port <= 0 {
+		return DefaultISCSITCPPort
+	}
+
+	return port
+}
+
+// GetPortals get iSCSI portals (host:port) that associated port group.
+// use addresses of logical ports that running on ports of port group (include bond port and VLAN),
+// or use addresses of ethernet ports if logical port is not found.
+func (d *Device) GetPortals(ctx context.Context, portgroupID int, family AddressFamily) ([]string, error) {
+	portals, err := d.getPortGroupPortals(ctx, portgroupID, family, LogicalPortProtocolISCSI, func(e EthernetPort) int { return e.iSCSITCPPort() })
+	if err != nil {
+		return nil, err
+	}
+
+	if len(portals) == 0 {
+		return nil, errors.New("portal ip addresses is not found")
+	}
+
+	return portals, nil
+}
+
+// getPortGroupPortals get portals (host:port) of logical ports or ethernet ports in port group.
+// protocol is bit of SUPPORTPROTOCOL that logical port must serve, tcpPort return TCP port number of ethernet port.
+func (d *Device) getPortGroupPortals(ctx context.Context, portgroupID int, family AddressFamily, protocol int, tcpPort func(EthernetPort) int) ([]string, error) {
+	query := &SearchQuery{
+		AssociateObjID:   strconv.Itoa(portgroupID),
+		AssociateObjType: strconv.Itoa(TypePortGroup),
+	}
+
+	ethernetports, err := d.GetAssociatedEthernetPort(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to associated ethernet port: %w", err)
+	}
+
+	var homePorts []logicalPortHome
+	for _, ethernetport := range ethernetports {
+		homePorts = appendHomePort(homePorts, logicalPortHome{ID: ethernetport.ID, Type: TypeEthernetPort, TCPPort: tcpPort(ethernetport)})
+		if ethernetport.BONDID != "" && ethernetport.BONDID != InvalidBondID {
+			homePorts = appendHomePort(homePorts, logicalPortHome{ID: ethernetport.BONDID, Type: TypeBondPort, TCPPort: tcpPort(ethernetport)})
+		}
+	}
+
+	portals, err := d.getLogicalPortals(ctx, homePorts, family, protocol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portals of logical port: %w", err)
+	}
+
+	if len(portals) == 0 {
+		for _, ethernetport := range ethernetports {
+			for _, ip := range family.selectAddresses(ethernetport.IPV4ADDR, ethernetport.IPV6ADDR) {
+				portals = appendPortal(portals, net.JoinHostPort(ip, strconv.Itoa(tcpPort(ethernetport))))
+			}
+		}
+	}
+
+	return portals, nil
+}
+
+// logicalPortHome is port that logical port can be homed (ethernet port, bond port or VLAN)
+type logicalPortHome struct {
+	ID      string
+	Type    int
+	TCPPort int
+}
+
+func appendHomePort(homePorts []logicalPortHome, homePort logicalPortHome) []logicalPortHome {
+	for _, h := range homePorts {
+		if h.ID == homePort.ID && h.Type == homePort.Type {
+			return homePorts
+		}
+	}
+
+	return append(homePorts, homePort)
+}
+
+// getLogicalPortals get portals of logical ports that homed on homePorts or VLANs of homePorts.
+// only service ports that serve protocol and link up are used.
+func (d *Device) getLogicalPortals(ctx context.Context, homePorts []logicalPortHome, family AddressFamily, protocol int) ([]string, error) {
+	vlans, err := d.GetVLANs(ctx, nil)
+	switch {
+	case err == nil:
+	case err == ErrVLANNotFound, errors.Is(err, ErrNotSupported):
+		vlans = nil
+	default:
+		return nil, fmt.Errorf("failed to get VLANs: %w", err)
+	}
+	for _, vlan := range vlans {
+		for _, h := range homePorts {
+			if h.Type != TypeVLAN && h.ID == vlan.PORTID {
+				homePorts = appendHomePort(homePorts, logicalPortHome{ID: vlan.ID, Type: TypeVLAN, TCPPort: h.TCPPort})
+				break
+			}
+		}
+	}
+
+	var portals []string
+	for _, h := range homePorts {
+		logicalPorts, err := d.GetLogicalPorts(ctx, &SearchQuery{Filter: ToFilter("HOMEPORTID", h.ID)})
+		if err != nil {
+			if err == ErrLogicalPortNotFound {
+				continue
+			}
+			if errors.Is(err, ErrNotSupported) {
+				// old device (ex: Dorado V3) is not support logical port
+				d.Logger.Printf("logical port is not supported, use address of ethernet port: %v", err)
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get logical ports: %w", err)
+		}
+
+		for _, lp := range logicalPorts {
+			if lp.HOMEPORTTYPE != h.Type || !lp.IsService(protocol) {
+				continue
+			}
+
+			for _, ip := range family.selectAddresses(lp.IPV4ADDR, lp.IPV6ADDR) {
+				portals = appendPortal(portals, net.JoinHostPort(ip, strconv.Itoa(h.TCPPort)))
+			}
+		}
+	}
+
+	return portals, nil
+}
+
+func appendPortal(portals []string, portal string) []string {
+	for _, p := range portals {
+		if p == portal {
+			return portals
+		}
+	}
+
+	return append(portals, portal)
+}
+
+// GetPortals is dorado.Client version of dorado.Device.GetPortals.
+func (c *Client) GetPortals(ctx context.Context, localPortgroupID, remotePortgroupID int, family AddressFamily) ([]string, error) {
+	localPortals, err := c.LocalDevice.GetPortals(ctx, localPortgroupID, family)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local portals: %w", err)
+	}
+
+	remotePortals, err := c.RemoteDevice.GetPortals(ctx, remotePortgroupID, family)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote portals: %w", err)
+	}
+
+	portals := append(localPortals, remotePortals...)
+	return portals, nil
+}
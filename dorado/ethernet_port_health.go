@@ -0,0 +1,213 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// EthernetPortProblem is problem of ethernet port that detected by EthernetPortHealth
+type EthernetPortProblem string
+
+// EthernetPortProblem const
+const (
+	ProblemLinkDown           EthernetPortProblem = "link is down"
+	ProblemSpeedBelowMax      EthernetPortProblem = "speed is below max speed"
+	ProblemCRCErrorIncreasing EthernetPortProblem = "CRC errors is increasing"
+)
+
+// EthernetPortCounters is error counters of ethernet port
+type EthernetPortCounters struct {
+	ErrorPackets      uint64
+	LostPackets       uint64
+	OverflowedPackets uint64
+	CrcErrors         uint64
+	FrameErrors       uint64
+	FrameLengthErrors uint64
+}
+
+// sub return difference from previous counters.
+// return current value if counter was reset (ex: port restarted).
+func (c EthernetPortCounters) sub(previous EthernetPortCounters) EthernetPortCounters {
+	delta := func(current, previous uint64) uint64 {
+		if current < previous {
+			return current
+		}
+		return current - previous
+	}
+
+	return EthernetPortCounters{
+		ErrorPackets:      delta(c.ErrorPackets, previous.ErrorPackets),
+		LostPackets:       delta(c.LostPackets, previous.LostPackets),
+		OverflowedPackets: delta(c.OverflowedPackets, previous.OverflowedPackets),
+		CrcErrors:         delta(c.CrcErrors, previous.CrcErrors),
+		FrameErrors:       delta(c.FrameErrors, previous.FrameErrors),
+		FrameLengthErrors: delta(c.FrameLengthErrors, previous.FrameLengthErrors),
+	}
+}
+
+// EthernetPortHealth is parsed health information of ethernet port
+type EthernetPortHealth struct {
+	ID          string
+	Name        string
+	Location    string
+	LinkUp      bool
+	LightStatus string
+	Speed       int // Mbps, -1 is unknown
+	MaxSpeed    int // Mbps, -1 is unknown
+
+	Counters EthernetPortCounters
+	Delta    *EthernetPortCounters // difference from previous poll, nil in first poll
+
+	Problems []EthernetPortProblem
+}
+
+// EthernetPortHealthReport is result of EthernetPortHealth
+type EthernetPortHealthReport struct {
+	Time  time.Time
+	Ports []EthernetPortHealth
+}
+
+// IsHealthy return true if all ports have no problem.
+func (r *EthernetPortHealthReport) IsHealthy() bool {
+	for _, p := range r.Ports {
+		if len(p.Problems) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// counters return counters of ports for next evaluation
+func (r *EthernetPortHealthReport) counters() map[string]EthernetPortCounters {
+	counters := map[string]EthernetPortCounters{}
+	for _, p := range r.Ports {
+		counters[p.ID] = p.Counters
+	}
+
+	return counters
+}
+
+// EthernetPortHealth check health of ethernet ports in port group.
+// Device keeps counters of previous call, and Delta is difference from previous call.
+func (d *Device) EthernetPortHealth(ctx context.Context, portgroupName string) (*EthernetPortHealthReport, error) {
+	portgroups, err := d.GetPortGroups(ctx, NewSearchQueryName(portgroupName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portgroup: %w", err)
+	}
+	if len(portgroups) != 1 {
+		return nil, fmt.Errorf("found multiple portgroup in same PortGroup name (name: %s)", portgroupName)
+	}
+
+	query := &SearchQuery{
+		AssociateObjID:   strconv.Itoa(portgroups[0].ID),
+		AssociateObjType: strconv.Itoa(TypePortGroup),
+	}
+	ethernetports, err := d.GetAssociatedEthernetPort(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get associated ethernet port: %w", err)
+	}
+
+	d.healthMu.Lock()
+	defer d.healthMu.Unlock()
+
+	report := evaluateEthernetPortHealth(ethernetports, d.ethernetPortCounters, time.Now())
+	// merge by port ID, keep counters of ports in other port groups
+	if d.ethernetPortCounters == nil {
+		d.ethernetPortCounters = map[string]EthernetPortCounters{}
+	}
+	for id, counters := range report.counters() {
+		d.ethernetPortCounters[id] = counters
+	}
+
+	return report, nil
+}
+
+// EthernetPortHealth is dorado.Client version of dorado.Device.EthernetPortHealth.
+// return report of local device and remote device.
+// portgroupName is port group to check (ex: returned by PortGroupNameFunc), use PortGroupName if empty.
+func (c *Client) EthernetPortHealth(ctx context.Context, portgroupName string) ([]EthernetPortHealthReport, error) {
+	if portgroupName == "" {
+		portgroupName = c.PortGroupName
+	}
+
+	localReport, err := c.LocalDevice.EthernetPortHealth(ctx, portgroupName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ethernet port health in Local Device: %w", err)
+	}
+	remoteReport, err := c.RemoteDevice.EthernetPortHealth(ctx, portgroupName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ethernet port health in Remote Device: %w", err)
+	}
+
+	return []EthernetPortHealthReport{*localReport, *remoteReport}, nil
+}
+
+// evaluateEthernetPortHealth parse ethernet ports and detect problems.
+// previous is counters of previous poll (key: ID of ethernet port), nil is first poll.
+func evaluateEthernetPortHealth(ethernetports []EthernetPort, previous map[string]EthernetPortCounters, now time.Time) *EthernetPortHealthReport {
+	report := &EthernetPortHealthReport{
+		Time: now,
+	}
+
+	for _, e := range ethernetports {
+		h := EthernetPortHealth{
+			ID:          e.ID,
+			Name:        e.NAME,
+			Location:    e.LOCATION,
+			LinkUp:      e.RUNNINGSTATUS == strconv.Itoa(StatusLinkUp),
+			LightStatus: e.LightStatus,
+			Speed:       parseSpeed(e.SPEED),
+			MaxSpeed:    parseSpeed(e.MaxSpeed),
+			Counters: EthernetPortCounters{
+				ErrorPackets:      parseCounter(e.ERRORPACKETS),
+				LostPackets:       parseCounter(e.LOSTPACKETS),
+				OverflowedPackets: parseCounter(e.OVERFLOWEDPACKETS),
+				CrcErrors:         parseCounter(e.CrcErrors),
+				FrameErrors:       parseCounter(e.FrameErrors),
+				FrameLengthErrors: parseCounter(e.FrameLengthErrors),
+			},
+		}
+
+		if p, ok := previous[e.ID]; ok {
+			delta := h.Counters.sub(p)
+			h.Delta = &delta
+		}
+
+		if !h.LinkUp {
+			h.Problems = append(h.Problems, ProblemLinkDown)
+		}
+		if h.LinkUp && h.Speed > 0 && h.MaxSpeed > 0 && h.Speed < h.MaxSpeed {
+			h.Problems = append(h.Problems, ProblemSpeedBelowMax)
+		}
+		if h.Delta != nil && h.Delta.CrcErrors > 0 {
+			h.Problems = append(h.Problems, ProblemCRCErrorIncreasing)
+		}
+
+		report.Ports = append(report.Ports, h)
+	}
+
+	return report
+}
+
+// parseSpeed parse speed (Mbps). return -1 if unknown.
+func parseSpeed(s string) int {
+	speed, err := strconv.Atoi(s)
+	if err != nil || speed < 0 {
+		return -1
+	}
+
+	return speed
+}
+
+// parseCounter parse counter value. return 0 if invalid value.
+func parseCounter(s string) uint64 {
+	c, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return c
+}
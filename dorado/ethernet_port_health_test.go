@@ -0,0 +1,126 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvaluateEthernetPortHealth(t *testing.T) {
+	now := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+	ports := []EthernetPort{
+		{ID: "1", RUNNINGSTATUS: "10", SPEED: "10000", MaxSpeed: "10000", CrcErrors: "5", ERRORPACKETS: "2"},
+		{ID: "2", RUNNINGSTATUS: "10", SPEED: "1000", MaxSpeed: "10000", CrcErrors: "3"},
+		{ID: "3", RUNNINGSTATUS: "11", SPEED: "-1", MaxSpeed: "10000", CrcErrors: ""},
+	}
+
+	first := evaluateEthernetPortHealth(ports, nil, now)
+	if first.Ports[0].Delta != nil {
+		t.Errorf("Delta must be nil in first poll, but %+v", first.Ports[0].Delta)
+	}
+	if first.IsHealthy() {
+		t.Errorf("IsHealthy must return false")
+	}
+
+	ports[0].CrcErrors = "8"
+	ports[1].CrcErrors = "1" // counter is reset
+	second := evaluateEthernetPortHealth(ports, first.counters(), now.Add(1*time.Minute))
+
+	wantDelta := &EthernetPortCounters{CrcErrors: 3}
+	if !reflect.DeepEqual(second.Ports[0].Delta, wantDelta) {
+		t.Errorf("Delta is %+v, want %+v", second.Ports[0].Delta, wantDelta)
+	}
+
+	wantProblems := [][]EthernetPortProblem{
+		{ProblemCRCErrorIncreasing},
+		{ProblemSpeedBelowMax, ProblemCRCErrorIncreasing},
+		{ProblemLinkDown},
+	}
+	for i, p := range second.Ports {
+		if !reflect.DeepEqual(p.Problems, wantProblems[i]) {
+			t.Errorf("Problems of port %s is %+v, want %+v", p.ID, p.Problems, wantProblems[i])
+		}
+	}
+	if second.Ports[2].Speed != -1 {
+		t.Errorf("Speed of link down port is %d, want -1", second.Ports[2].Speed)
+	}
+}
+
+func TestDevice_EthernetPortHealth_MultiplePortGroups(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/portgroup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("filter") {
+		case "NAME::pg1":
+			fmt.Fprint(w, `{"data": [{"ID": "1", "NAME": "pg1"}], "error": {"code": 0, "description": "0"}}`)
+		default:
+			fmt.Fprint(w, `{"data": [{"ID": "2", "NAME": "pg2"}], "error": {"code": 0, "description": "0"}}`)
+		}
+	})
+	mux.HandleFunc("/eth_port/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("ASSOCIATEOBJID") {
+		case "1":
+			fmt.Fprint(w, `{"data": [{"ID": "131328", "RUNNINGSTATUS": "10", "crcErrors": "5"}], "error": {"code": 0, "description": "0"}}`)
+		default:
+			fmt.Fprint(w, `{"data": [{"ID": "131329", "RUNNINGSTATUS": "10", "crcErrors": "0"}], "error": {"code": 0, "description": "0"}}`)
+		}
+	})
+
+	for _, name := range []string{"pg1", "pg2"} {
+		if _, err := client.LocalDevice.EthernetPortHealth(context.Background(), name); err != nil {
+			t.Fatalf("EthernetPortHealth(%s) return err: %s", name, err)
+		}
+	}
+
+	// counters of pg1 are kept after pg2 is checked
+	report, err := client.LocalDevice.EthernetPortHealth(context.Background(), "pg1")
+	if err != nil {
+		t.Fatalf("EthernetPortHealth return err: %s", err)
+	}
+	if report.Ports[0].Delta == nil {
+		t.Errorf("Delta must not be nil in second poll of same port group")
+	}
+}
+
+func TestClient_EthernetPortHealth(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var got []string
+	mux.HandleFunc("/portgroup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		filter := r.URL.Query().Get("filter")
+		got = append(got, filter)
+		fmt.Fprintf(w, `{"data": [{"ID": "1", "NAME": "%s"}], "error": {"code": 0, "description": "0"}}`, strings.TrimPrefix(filter, "NAME::"))
+	})
+	mux.HandleFunc("/eth_port/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "131328", "RUNNINGSTATUS": "10"}], "error": {"code": 0, "description": "0"}}`)
+	})
+
+	// port group that selected by PortGroupNameFunc
+	reports, err := client.EthernetPortHealth(context.Background(), "pg1")
+	if err != nil {
+		t.Fatalf("EthernetPortHealth return err: %s", err)
+	}
+	if len(reports) != 2 {
+		t.Errorf("EthernetPortHealth return %d reports, want 2", len(reports))
+	}
+
+	// empty is PortGroupName
+	if _, err := client.EthernetPortHealth(context.Background(), ""); err != nil {
+		t.Fatalf("EthernetPortHealth return err: %s", err)
+	}
+
+	want := []string{"NAME::pg1", "NAME::pg1", "NAME::portgroup", "NAME::portgroup"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EthernetPortHealth request port groups %v, want %v", got, want)
+	}
+}
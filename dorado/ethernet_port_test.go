@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -129,3 +130,107 @@ func TestDevice_GetAssociatedEthernetPort(t *testing.T) {
 		t.Errorf("GetAssociatedEthernetPort return %+v, want %+v", ether, want)
 	}
 }
+
+func TestDevice_GetPortals(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/eth_port/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `
+{
+  "data": [
+    {"BONDID": "18446744073709551615", "ID": "131328", "IPV4ADDR": "", "ISCSITCPPORT": "0", "TYPE": 213},
+    {"BONDID": "1", "ID": "131329", "IPV4ADDR": "", "ISCSITCPPORT": "3261", "TYPE": 213}
+  ],
+  "error": {"code": 0, "description": "0"}
+}`)
+	})
+	// key: HOMEPORTID
+	lifs := map[string]string{
+		"131328": `
+    {"HOMEPORTID": "131328", "HOMEPORTTYPE": "213", "ID": "0", "IPV4ADDR": "192.0.2.10", "IPV6ADDR": "2001:db8::10", "ROLE": "2", "RUNNINGSTATUS": "10", "SUPPORTPROTOCOL": "64", "TYPE": 279},
+    {"HOMEPORTID": "131328", "HOMEPORTTYPE": "213", "ID": "3", "IPV4ADDR": "192.0.2.12", "IPV6ADDR": "", "ROLE": "1", "RUNNINGSTATUS": "10", "SUPPORTPROTOCOL": "64", "TYPE": 279},
+    {"HOMEPORTID": "131328", "HOMEPORTTYPE": "213", "ID": "4", "IPV4ADDR": "192.0.2.13", "IPV6ADDR": "", "ROLE": "2", "RUNNINGSTATUS": "11", "SUPPORTPROTOCOL": "64", "TYPE": 279}`,
+		"131329": `
+    {"CURRENTPORTID": "999", "CURRENTPORTTYPE": "213", "HOMEPORTID": "131329", "HOMEPORTTYPE": "213", "ID": "5", "IPV4ADDR": "192.0.2.14", "IPV6ADDR": "", "ROLE": "2", "RUNNINGSTATUS": "10", "SUPPORTPROTOCOL": "1", "TYPE": 279}`,
+		"10": `
+    {"HOMEPORTID": "10", "HOMEPORTTYPE": "280", "ID": "1", "IPV4ADDR": "192.0.2.11", "IPV6ADDR": "", "ROLE": "3", "RUNNINGSTATUS": "10", "SUPPORTPROTOCOL": "64", "TYPE": 279}`,
+		"999": `
+    {"HOMEPORTID": "999", "HOMEPORTTYPE": "213", "ID": "2", "IPV4ADDR": "192.0.2.99", "IPV6ADDR": "", "ROLE": "2", "RUNNINGSTATUS": "10", "SUPPORTPROTOCOL": "64", "TYPE": 279}`,
+	}
+	mux.HandleFunc("/lif", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		homePortID := strings.TrimPrefix(r.URL.Query().Get("filter"), "HOMEPORTID::")
+		if homePortID == "999" {
+			t.Errorf("logical port of other port group must not be requested")
+		}
+		fmt.Fprintf(w, `{"data": [%s], "error": {"code": 0, "description": "0"}}`, lifs[homePortID])
+	})
+	mux.HandleFunc("/vlan", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `
+{
+  "data": [
+    {"ID": "10", "PORTID": "1", "PORTTYPE": "7", "TAG": "100", "TYPE": 280}
+  ],
+  "error": {"code": 0, "description": "0"}
+}`)
+	})
+
+	tests := []struct {
+		family AddressFamily
+		want   []string
+	}{
+		{family: AddressFamilyIPv4, want: []string{"192.0.2.10:3260", "192.0.2.11:3261"}},
+		{family: AddressFamilyIPv6, want: []string{"[2001:db8::10]:3260"}},
+		{family: AddressFamilyPreferIPv6, want: []string{"[2001:db8::10]:3260", "192.0.2.11:3261"}},
+		{family: AddressFamilyBoth, want: []string{"192.0.2.10:3260", "[2001:db8::10]:3260", "192.0.2.11:3261"}},
+	}
+
+	for _, test := range tests {
+		portals, err := client.LocalDevice.GetPortals(context.Background(), 1, test.family)
+		if err != nil {
+			t.Errorf("GetPortals return err: %s", err)
+		}
+
+		if !reflect.DeepEqual(portals, test.want) {
+			t.Errorf("GetPortals(family: %d) return %+v, want %+v", test.family, portals, test.want)
+		}
+	}
+}
+
+func TestDevice_GetPortals_LogicalPortError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/eth_port/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"BONDID": "18446744073709551615", "ID": "131328", "IPV4ADDR": "192.0.2.20", "ISCSITCPPORT": "0", "TYPE": 213}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/vlan", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+	})
+	var lifErrorCode int
+	mux.HandleFunc("/lif", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"data": [], "error": {"code": %d, "description": "error"}}`, lifErrorCode)
+	})
+
+	// logical port is not supported: use address of ethernet port
+	lifErrorCode = ErrorCodeNotSupported
+	portals, err := client.LocalDevice.GetPortals(context.Background(), 1, AddressFamilyIPv4)
+	if err != nil {
+		t.Fatalf("GetPortals return err: %s", err)
+	}
+	if want := []string{"192.0.2.20:3260"}; !reflect.DeepEqual(portals, want) {
+		t.Errorf("GetPortals return %+v, want %+v", portals, want)
+	}
+
+	// other errors are returned
+	lifErrorCode = 1077949001
+	if _, err := client.LocalDevice.GetPortals(context.Background(), 1, AddressFamilyIPv4); err == nil {
+		t.Errorf("GetPortals must return err if failed to get logical ports")
+	}
+}
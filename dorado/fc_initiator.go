@@ -0,0 +1,233 @@
+package dorado
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FCInitiator is Fibre Channel initiator (HBA port of host)
+type FCInitiator struct {
+	HEALTHSTATUS    string `json:"HEALTHSTATUS"`
+	ID              string `json:"ID"` // = WWPN
+	ISFREE          bool   `json:"ISFREE,string"`
+	MULTIPATHTYPE   string `json:"MULTIPATHTYPE"`
+	OPERATIONSYSTEM string `json:"OPERATIONSYSTEM"`
+	RUNNINGSTATUS   string `json:"RUNNINGSTATUS"`
+	TYPE            int    `json:"TYPE"`
+	FAILOVERMODE    string `json:"FAILOVERMODE"`
+	SPECIALMODETYPE string `json:"SPECIALMODETYPE"`
+	PATHTYPE        string `json:"PATHTYPE"`
+	PARENTID        string `json:"PARENTID,omitempty"`
+	PARENTNAME      string `json:"PARENTNAME,omitempty"`
+	PARENTTYPE      int    `json:"PARENTTYPE,omitempty"`
+}
+
+// NormalizeWWPN convert WWPN to format of Dorado (lower case, without separator).
+// ex: 21:00:00:24:FF:4C:6E:2A -> 21000024ff4c6e2a
+func NormalizeWWPN(wwpn string) (string, error) {
+	w := strings.ToLower(wwpn)
+	w = strings.TrimPrefix(w, "0x")
+	w = strings.ReplaceAll(w, ":", "")
+	w = strings.ReplaceAll(w, "-", "")
+
+	if len(w) != 16 {
+		return "", fmt.Errorf("invalid WWPN length (WWPN: %s)", wwpn)
+	}
+	if _, err := hex.DecodeString(w); err != nil {
+		return "", fmt.Errorf("invalid WWPN (WWPN: %s): %w", wwpn, err)
+	}
+
+	return w, nil
+}
+
+// GetFCInitiators get FC initiators by query
+func (d *Device) GetFCInitiators(ctx context.Context, query *SearchQuery) ([]FCInitiator, error) {
+	spath := "/fc_initiator"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var initiators []FCInitiator
+	if err = d.requestWithRetry(req, &initiators, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(initiators) == 0 {
+		return nil, ErrFCInitiatorNotFound
+	}
+
+	return initiators, nil
+}
+
+// GetFCInitiator get FC initiator by WWPN.
+func (d *Device) GetFCInitiator(ctx context.Context, wwpn string) (*FCInitiator, error) {
+	w, err := NormalizeWWPN(wwpn)
+	if err != nil {
+		return nil, err
+	}
+	spath := fmt.Sprintf("/fc_initiator/%s", w)
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	initiator := &FCInitiator{}
+	if err = d.requestWithRetry(req, initiator, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return initiator, nil
+}
+
+// CreateFCInitiator create FC initiator.
+func (d *Device) CreateFCInitiator(ctx context.Context, wwpn string) (*FCInitiator, error) {
+	w, err := NormalizeWWPN(wwpn)
+	if err != nil {
+		return nil, err
+	}
+	spath := "/fc_initiator"
+	param := struct {
+		TYPE string `json:"TYPE"`
+		ID   string `json:"ID"`
+	}{
+		TYPE: strconv.Itoa(TypeFCInitiator),
+		ID:   w,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	initiator := &FCInitiator{}
+	if err = d.requestWithRetry(req, initiator, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return initiator, nil
+}
+
+// DeleteFCInitiator delete FC initiator.
+func (d *Device) DeleteFCInitiator(ctx context.Context, wwpn string) error {
+	w, err := NormalizeWWPN(wwpn)
+	if err != nil {
+		return err
+	}
+	spath := fmt.Sprintf("/fc_initiator/%s", w)
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// UpdateFCInitiatorParam is parameter for UpdateFCInitiator
+type UpdateFCInitiatorParam struct {
+	TYPE       string `json:"TYPE"`
+	ID         string `json:"ID"`
+	PARENTTYPE string `json:"PARENTTYPE,omitempty"`
+	PARENTID   string `json:"PARENTID,omitempty"`
+}
+
+// UpdateFCInitiator update FC initiator information.
+func (d *Device) UpdateFCInitiator(ctx context.Context, wwpn string, initiatorParam UpdateFCInitiatorParam) (*FCInitiator, error) {
+	w, err := NormalizeWWPN(wwpn)
+	if err != nil {
+		return nil, err
+	}
+	spath := fmt.Sprintf("/fc_initiator/%s", w)
+
+	jb, err := json.Marshal(initiatorParam)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	initiator := &FCInitiator{}
+	if err = d.requestWithRetry(req, initiator, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return initiator, nil
+}
+
+// RemoveFCInitiatorFromHost remove FC initiator from host.
+func (d *Device) RemoveFCInitiatorFromHost(ctx context.Context, wwpn string) error {
+	w, err := NormalizeWWPN(wwpn)
+	if err != nil {
+		return err
+	}
+	spath := "/fc_initiator/remove_fc_from_host"
+	param := struct {
+		TYPE string `json:"TYPE"`
+		ID   string `json:"ID"`
+	}{
+		TYPE: strconv.Itoa(TypeFCInitiator),
+		ID:   w,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// GetFCInitiatorForce get FC initiator and create FC initiator if not exists.
+func (d *Device) GetFCInitiatorForce(ctx context.Context, wwpn string) (*FCInitiator, error) {
+	w, err := NormalizeWWPN(wwpn)
+	if err != nil {
+		return nil, err
+	}
+
+	initiators, err := d.GetFCInitiators(ctx, NewSearchQueryID(w))
+	if err != nil {
+		if err == ErrFCInitiatorNotFound {
+			return d.CreateFCInitiator(ctx, w)
+		}
+
+		return nil, fmt.Errorf("failed to get FC initiators: %w", err)
+	}
+
+	if len(initiators) != 1 {
+		return nil, errors.New("found multiple FC initiators in same WWPN")
+	}
+
+	return &initiators[0], nil
+}
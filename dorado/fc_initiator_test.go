@@ -0,0 +1,100 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDevice_GetFCInitiators(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/fc_initiator", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w,
+			`
+{
+  "data": [
+    {
+      "HEALTHSTATUS": "1",
+      "ID": "21000024ff4c6e2a",
+      "ISFREE": "false",
+      "MULTIPATHTYPE": "1",
+      "OPERATIONSYSTEM": "0",
+      "PARENTID": "3",
+      "PARENTNAME": "Host001",
+      "PARENTTYPE": 21,
+      "RUNNINGSTATUS": "27",
+      "TYPE": 223,
+      "FAILOVERMODE": "3",
+      "SPECIALMODETYPE": "2",
+      "PATHTYPE": "1"
+    }
+  ],
+  "error": {
+    "code": 0,
+    "description": "0"
+  }
+}`)
+	})
+
+	initiators, err := client.LocalDevice.GetFCInitiators(context.Background(), nil)
+	if err != nil {
+		t.Errorf("GetFCInitiators return err: %s", err)
+	}
+
+	want := []FCInitiator{
+		{
+			HEALTHSTATUS:    "1",
+			ID:              "21000024ff4c6e2a",
+			ISFREE:          false,
+			MULTIPATHTYPE:   "1",
+			OPERATIONSYSTEM: "0",
+			RUNNINGSTATUS:   "27",
+			TYPE:            TypeFCInitiator,
+			FAILOVERMODE:    "3",
+			SPECIALMODETYPE: "2",
+			PATHTYPE:        "1",
+			PARENTID:        "3",
+			PARENTNAME:      "Host001",
+			PARENTTYPE:      TypeHost,
+		},
+	}
+
+	if !reflect.DeepEqual(initiators, want) {
+		t.Errorf("GetFCInitiators return %+v, want %+v", initiators, want)
+	}
+}
+
+func TestNormalizeWWPN(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "21000024ff4c6e2a", want: "21000024ff4c6e2a"},
+		{input: "21:00:00:24:FF:4C:6E:2A", want: "21000024ff4c6e2a"},
+		{input: "0x21000024FF4C6E2A", want: "21000024ff4c6e2a"},
+		{input: "21:00:00:24:ff:4c:6e", wantErr: true},
+		{input: "21000024ff4c6e2z", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := NormalizeWWPN(test.input)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("NormalizeWWPN(%s) must return err", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NormalizeWWPN(%s) return err: %s", test.input, err)
+		}
+		if got != test.want {
+			t.Errorf("NormalizeWWPN(%s) return %s, want %s", test.input, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// FCPort is Fibre Channel port of controller
+type FCPort struct {
+	CONFSPEED        string `json:"CONFSPEED"`
+	FCCONFMODE       string `json:"FCCONFMODE"`
+	FCRUNMODE        string `json:"FCRUNMODE"`
+	HEALTHSTATUS     string `json:"HEALTHSTATUS"`
+	ID               string `json:"ID"`
+	INIORTGT         string `json:"INIORTGT"`
+	LOCATION         string `json:"LOCATION"`
+	LOGICTYPE        string `json:"LOGICTYPE"`
+	MAXSPEED         string `json:"MAXSPEED"`
+	NAME             string `json:"NAME"`
+	OWNINGCONTROLLER string `json:"OWNINGCONTROLLER"`
+	PARENTID         string `json:"PARENTID"`
+	PARENTTYPE       int    `json:"PARENTTYPE"`
+	RUNNINGSTATUS    string `json:"RUNNINGSTATUS"`
+	RUNSPEED         string `json:"RUNSPEED"`
+	SFPSTATUS        string `json:"SFPSTATUS"`
+	TYPE             int    `json:"TYPE"`
+	WWN              string `json:"WWN"`
+}
+
+// GetFCPorts get FC ports by query
+func (d *Device) GetFCPorts(ctx context.Context, query *SearchQuery) ([]FCPort, error) {
+	spath := "/fc_port"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var fcports []FCPort
+	if err = d.requestWithRetry(req, &fcports, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(fcports) == 0 {
+		return nil, ErrFCPortNotFound
+	}
+
+	return fcports, nil
+}
+
+// GetAssociatedFCPorts get FC ports associated ASSOCIATEOBJID (maybe port group).
+// you must set ASSOCIATEOBJID and ASSOCIATEOBJTYPE. we recommend use dorado.GetTargetWWPNs().
+func (d *Device) GetAssociatedFCPorts(ctx context.Context, query *SearchQuery) ([]FCPort, error) {
+	spath := "/fc_port/associate"
+
+	if query == nil || query.AssociateObjType == "" || query.AssociateObjID == "" {
+		return nil, errors.New("you must set associated parameter")
+	}
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var fcports []FCPort
+	if err = d.requestWithRetry(req, &fcports, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(fcports) == 0 {
+		return nil, ErrFCPortNotFound
+	}
+
+	return fcports, nil
+}
+
+// GetTargetWWPNs get WWPNs of FC ports that associated port group.
+// return only link up ports.
+func (d *Device) GetTargetWWPNs(ctx context.Context, portgroupID int) ([]string, error) {
+	query := &SearchQuery{
+		AssociateObjID:   strconv.Itoa(portgroupID),
+		AssociateObjType: strconv.Itoa(TypePortGroup),
+	}
+
+	fcports, err := d.GetAssociatedFCPorts(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get associated FC port: %w", err)
+	}
+
+	var wwpns []string
+	for _, fcport := range fcports {
+		if fcport.RUNNINGSTATUS != strconv.Itoa(StatusLinkUp) {
+			continue
+		}
+
+		wwpns = append(wwpns, fcport.WWN)
+	}
+
+	if len(wwpns) == 0 {
+		return nil, errors.New("target WWPNs is not found")
+	}
+
+	return wwpns, nil
+}
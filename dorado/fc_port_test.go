@@ -0,0 +1,79 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDevice_GetTargetWWPNs(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/fc_port/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w,
+			`
+{
+  "data": [
+    {
+      "CONFSPEED": "0",
+      "FCCONFMODE": "3",
+      "FCRUNMODE": "0",
+      "HEALTHSTATUS": "1",
+      "ID": "33619968",
+      "INIORTGT": "3",
+      "LOCATION": "CTE0.A.IOM0.P0",
+      "LOGICTYPE": "0",
+      "MAXSPEED": "16000",
+      "NAME": "P0",
+      "OWNINGCONTROLLER": "0A",
+      "PARENTID": "0A.0",
+      "PARENTTYPE": 209,
+      "RUNNINGSTATUS": "10",
+      "RUNSPEED": "16000",
+      "SFPSTATUS": "1",
+      "TYPE": 212,
+      "WWN": "2000a400e255e226"
+    },
+    {
+      "CONFSPEED": "0",
+      "FCCONFMODE": "3",
+      "FCRUNMODE": "0",
+      "HEALTHSTATUS": "1",
+      "ID": "33619969",
+      "INIORTGT": "3",
+      "LOCATION": "CTE0.A.IOM0.P1",
+      "LOGICTYPE": "0",
+      "MAXSPEED": "16000",
+      "NAME": "P1",
+      "OWNINGCONTROLLER": "0A",
+      "PARENTID": "0A.0",
+      "PARENTTYPE": 209,
+      "RUNNINGSTATUS": "11",
+      "RUNSPEED": "-1",
+      "SFPSTATUS": "1",
+      "TYPE": 212,
+      "WWN": "2001a400e255e226"
+    }
+  ],
+  "error": {
+    "code": 0,
+    "description": "0"
+  }
+}`)
+	})
+
+	wwpns, err := client.LocalDevice.GetTargetWWPNs(context.Background(), 1)
+	if err != nil {
+		t.Errorf("GetTargetWWPNs return err: %s", err)
+	}
+
+	want := []string{"2000a400e255e226"}
+
+	if !reflect.DeepEqual(wwpns, want) {
+		t.Errorf("GetTargetWWPNs return %+v, want %+v", wwpns, want)
+	}
+}
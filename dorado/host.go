@@ -28,6 +28,115 @@ type Host struct {
 	PARENTTYPE      int    `json:"PARENTTYPE"`
 	RUNNINGSTATUS   string `json:"RUNNINGSTATUS"`
 	TYPE            int    `json:"TYPE"`
+	AccessMode      string `json:"accessMode,omitempty"`
+}
+
+// OSType is type of OPERATIONSYSTEM in host
+type OSType int
+
+// OSType const
+const (
+	OSTypeLinux OSType = iota
+	OSTypeWindows
+	OSTypeSolaris
+	OSTypeHPUX
+	OSTypeAIX
+	OSTypeXenServer
+	OSTypeMacOS
+	OSTypeESXi
+	OSTypeLinuxVIS
+	OSTypeWindowsServer2012
+	OSTypeOracleVM
+	OSTypeOpenVMS
+)
+
+// String is function compatible for fmt.Stringer
+func (o OSType) String() string {
+	return strconv.Itoa(int(o))
+}
+
+// HostAccessMode is type of accessMode in host
+type HostAccessMode int
+
+// HostAccessMode const
+const (
+	HostAccessModeBalanced HostAccessMode = iota
+	HostAccessModeAsymmetric
+)
+
+// String is function compatible for fmt.Stringer
+func (m HostAccessMode) String() string {
+	return strconv.Itoa(int(m))
+}
+
+// HostParam is parameter for CreateHostWithParam.
+// empty value is not set.
+type HostParam struct {
+	OPERATIONSYSTEM string `json:"OPERATIONSYSTEM,omitempty"`
+	IP              string `json:"IP,omitempty"`
+	LOCATION        string `json:"LOCATION,omitempty"`
+	DESCRIPTION     string `json:"DESCRIPTION,omitempty"`
+	ACCESSMODE      string `json:"accessMode,omitempty"`
+}
+
+// NewHostParam create HostParam that set OS type.
+func NewHostParam(osType OSType) *HostParam {
+	return &HostParam{
+		OPERATIONSYSTEM: osType.String(),
+	}
+}
+
+// SetAccessMode set host access mode.
+func (p *HostParam) SetAccessMode(mode HostAccessMode) *HostParam {
+	p.ACCESSMODE = mode.String()
+	return p
+}
+
+// HostUpdate is parameter for UpdateHost.
+// nil field is not changed, and empty string clear the value.
+type HostUpdate struct {
+	OPERATIONSYSTEM *string `json:"OPERATIONSYSTEM,omitempty"`
+	IP              *string `json:"IP,omitempty"`
+	LOCATION        *string `json:"LOCATION,omitempty"`
+	DESCRIPTION     *string `json:"DESCRIPTION,omitempty"`
+	ACCESSMODE      *string `json:"accessMode,omitempty"`
+}
+
+// NewHostUpdate create HostUpdate that change nothing.
+func NewHostUpdate() *HostUpdate {
+	return &HostUpdate{}
+}
+
+// SetOSType set OS type.
+func (u *HostUpdate) SetOSType(osType OSType) *HostUpdate {
+	s := osType.String()
+	u.OPERATIONSYSTEM = &s
+	return u
+}
+
+// SetIP set IP address, empty string clear it.
+func (u *HostUpdate) SetIP(ip string) *HostUpdate {
+	u.IP = &ip
+	return u
+}
+
+// SetLocation set location, empty string clear it.
+func (u *HostUpdate) SetLocation(location string) *HostUpdate {
+	u.LOCATION = &location
+	return u
+}
+
+// SetDescription set description, empty string clear it.
+func (u *HostUpdate) SetDescription(description string) *HostUpdate {
+	u.DESCRIPTION = &description
+	return u
+}
+
+// SetAccessMode set host access mode.
+func (u *HostUpdate) SetAccessMode(mode HostAccessMode) *HostUpdate {
+	s := mode.String()
+	u.ACCESSMODE = &s
+	return u
 }
 
 func encodeHostName(hostname string) string {
@@ -81,17 +190,32 @@ func (d *Device) GetHost(ctx context.Context, hostID int) (*Host, error) {
 
 // CreateHost create host object.
 func (d *Device) CreateHost(ctx context.Context, hostname string) (*Host, error) {
+	return d.CreateHostWithParam(ctx, hostname, nil)
+}
+
+// CreateHostWithParam create host object with parameter.
+// OS type is Linux and DESCRIPTION is hostname if not set.
+func (d *Device) CreateHostWithParam(ctx context.Context, hostname string, hostParam *HostParam) (*Host, error) {
 	spath := "/host"
+	p := HostParam{}
+	if hostParam != nil {
+		p = *hostParam
+	}
+	if p.OPERATIONSYSTEM == "" {
+		p.OPERATIONSYSTEM = OSTypeLinux.String()
+	}
+	if p.DESCRIPTION == "" {
+		p.DESCRIPTION = hostname
+	}
+
 	param := struct {
-		NAME            string `json:"NAME"`
-		TYPE            string `json:"TYPE"`
-		OPERATIONSYSTEM string `json:"OPERATIONSYSTEM"`
-		DESCRIPTION     string `json:"DESCRIPTION"`
+		NAME string `json:"NAME"`
+		TYPE string `json:"TYPE"`
+		HostParam
 	}{
-		NAME:            encodeHostName(hostname),
-		TYPE:            strconv.Itoa(TypeHost),
-		OPERATIONSYSTEM: "0",
-		DESCRIPTION:     hostname,
+		NAME:      encodeHostName(hostname),
+		TYPE:      strconv.Itoa(TypeHost),
+		HostParam: p,
 	}
 	jb, err := json.Marshal(param)
 	if err != nil {
@@ -110,6 +234,55 @@ func (d *Device) CreateHost(ctx context.Context, hostname string) (*Host, error)
 	return host, nil
 }
 
+// UpdateHost update host object.
+func (d *Device) UpdateHost(ctx context.Context, hostID int, hostUpdate HostUpdate) (*Host, error) {
+	spath := fmt.Sprintf("/host/%d", hostID)
+	param := struct {
+		ID   string `json:"ID"`
+		TYPE string `json:"TYPE"`
+		HostUpdate
+	}{
+		ID:         strconv.Itoa(hostID),
+		TYPE:       strconv.Itoa(TypeHost),
+		HostUpdate: hostUpdate,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	host := &Host{}
+	if err = d.requestWithRetry(req, host, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return host, nil
+}
+
+// UpdateHost update host object that named hostname in local device and remote device.
+func (c *Client) UpdateHost(ctx context.Context, hostname string, hostUpdate HostUpdate) error {
+	for _, d := range []*Device{c.LocalDevice, c.RemoteDevice} {
+		hosts, err := d.GetHosts(ctx, NewSearchQueryHostname(hostname))
+		if err != nil {
+			return fmt.Errorf("failed to get host: %w", err)
+		}
+		if len(hosts) != 1 {
+			return fmt.Errorf("found multiple hosts in same hostname (hostname: %s)", hostname)
+		}
+
+		if _, err := d.UpdateHost(ctx, hosts[0].ID, hostUpdate); err != nil {
+			return fmt.Errorf("failed to update host: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // DeleteHost delete host object.
 func (d *Device) DeleteHost(ctx context.Context, hostID int) error {
 	spath := fmt.Sprintf("/host/%d", hostID)
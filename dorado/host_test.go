@@ -107,3 +107,58 @@ func TestDevice_GetHosts(t *testing.T) {
 		t.Errorf("GetHosts return %+v, want %+v", hosts, want)
 	}
 }
+
+func TestDevice_UpdateHost(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/host/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+
+		testBody(t, r, map[string]interface{}{
+			"ID":              "1",
+			"TYPE":            "21",
+			"OPERATIONSYSTEM": "7",
+			"IP":              "192.0.2.1",
+			"DESCRIPTION":     "",
+			"accessMode":      "1",
+		})
+
+		fmt.Fprintf(w,
+			`
+{
+  "data": {
+    "ID": "1",
+    "IP": "192.0.2.1",
+    "NAME": "Host001",
+    "OPERATIONSYSTEM": "7",
+    "TYPE": 21,
+    "accessMode": "1"
+  },
+  "error": {
+    "code": 0,
+    "description": "0"
+  }
+}`)
+	})
+
+	// empty DESCRIPTION is sent to clear, LOCATION is not changed
+	param := NewHostUpdate().SetOSType(OSTypeESXi).SetAccessMode(HostAccessModeAsymmetric).SetIP("192.0.2.1").SetDescription("")
+	host, err := client.LocalDevice.UpdateHost(context.Background(), 1, *param)
+	if err != nil {
+		t.Errorf("UpdateHost return err: %s", err)
+	}
+
+	want := &Host{
+		ID:              1,
+		IP:              "192.0.2.1",
+		NAME:            "Host001",
+		OPERATIONSYSTEM: "7",
+		TYPE:            TypeHost,
+		AccessMode:      "1",
+	}
+
+	if !reflect.DeepEqual(host, want) {
+		t.Errorf("UpdateHost return %+v, want %+v", host, want)
+	}
+}
@@ -42,6 +42,8 @@ func (e ErrorResp) Error() error {
 	case ErrorCodeUnAuthorized, ErrorCodeUserIsOffline:
 		// please retry
 		return ErrUnAuthorized
+	case ErrorCodeNotSupported:
+		return fmt.Errorf("%w: %s (code: %d) Suggestion: %s", ErrNotSupported, e.Description, e.Code, e.Suggestion)
 	}
 
 	return fmt.Errorf("Dorado Internal Error: %s (code: %d) Suggestion: %s", e.Description, e.Code, e.Suggestion)
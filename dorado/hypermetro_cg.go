@@ -0,0 +1,357 @@
+package dorado
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// HyperMetroCG is HyperMetro consistency group.
+// HyperMetroPairs in a group are suspended and synchronized at the same time, so data of LUNs are consistent.
+type HyperMetroCG struct {
+	DESCRIPTION         string `json:"DESCRIPTION"`
+	DOMAINID            string `json:"DOMAINID"`
+	DOMAINNAME          string `json:"DOMAINNAME"`
+	HEALTHSTATUS        string `json:"HEALTHSTATUS"`
+	ID                  string `json:"ID"`
+	ISPRIMARY           string `json:"ISPRIMARY"`
+	NAME                string `json:"NAME"`
+	PRIORITYSTATIONTYPE string `json:"PRIORITYSTATIONTYPE"`
+	RECOVERYPOLICY      string `json:"RECOVERYPOLICY"`
+	RUNNINGSTATUS       string `json:"RUNNINGSTATUS"`
+	SPEED               string `json:"SPEED"`
+	SYNCDIRECTION       string `json:"SYNCDIRECTION"`
+	TYPE                int    `json:"TYPE"`
+}
+
+// State return RUNNINGSTATUS of HyperMetro consistency group
+func (cg *HyperMetroCG) State() HyperMetroPairState {
+	state, err := strconv.Atoi(cg.RUNNINGSTATUS)
+	if err != nil {
+		return 0
+	}
+	return HyperMetroPairState(state)
+}
+
+// GetHyperMetroCGs get HyperMetro consistency groups by query
+func (c *Client) GetHyperMetroCGs(ctx context.Context, query *SearchQuery) ([]HyperMetroCG, error) {
+	spath := "/HyperMetro_ConsistentGroup"
+
+	req, err := c.LocalDevice.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var cgs []HyperMetroCG
+	if err = c.LocalDevice.requestWithRetry(req, &cgs, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(cgs) == 0 {
+		return nil, ErrHyperMetroCGNotFound
+	}
+
+	return cgs, nil
+}
+
+// GetHyperMetroCG get HyperMetro consistency group by id
+func (c *Client) GetHyperMetroCG(ctx context.Context, hyperMetroCGID string) (*HyperMetroCG, error) {
+	spath := fmt.Sprintf("/HyperMetro_ConsistentGroup/%s", hyperMetroCGID)
+
+	req, err := c.LocalDevice.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	cg := &HyperMetroCG{}
+	if err = c.LocalDevice.requestWithRetry(req, cg, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return cg, nil
+}
+
+// CreateHyperMetroCG create HyperMetro consistency group.
+// Speed and RecoveryPolicy of opt are used, IsFirstSync is ignored.
+func (c *Client) CreateHyperMetroCG(ctx context.Context, u uuid.UUID, hyperMetroDomainID string, opt *HyperMetroPairOption) (*HyperMetroCG, error) {
+	opt = opt.withDefault()
+
+	spath := "/HyperMetro_ConsistentGroup"
+	param := struct {
+		NAME                string `json:"NAME"`
+		DESCRIPTION         string `json:"DESCRIPTION"`
+		DOMAINID            string `json:"DOMAINID"`
+		SPEED               int    `json:"SPEED"`
+		RECOVERYPOLICY      string `json:"RECOVERYPOLICY"`
+		PRIORITYSTATIONTYPE string `json:"PRIORITYSTATIONTYPE"`
+	}{
+		NAME:                EncodeLunName(u),
+		DESCRIPTION:         u.String(),
+		DOMAINID:            hyperMetroDomainID,
+		SPEED:               int(opt.Speed),
+		RECOVERYPOLICY:      string(opt.RecoveryPolicy),
+		PRIORITYSTATIONTYPE: "0", // preferred site is local device
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := c.LocalDevice.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	cg := &HyperMetroCG{}
+	if err = c.LocalDevice.requestWithRetry(req, cg, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return cg, nil
+}
+
+// DeleteHyperMetroCG delete HyperMetro consistency group.
+// group is suspended and HyperMetroPairs in group are removed before delete (HyperMetroPairs are not deleted).
+func (c *Client) DeleteHyperMetroCG(ctx context.Context, hyperMetroCGID string) error {
+	hmps, err := c.GetHyperMetroCGPairs(ctx, hyperMetroCGID)
+	if err != nil && err != ErrHyperMetroPairNotFound {
+		return fmt.Errorf("failed to get HyperMetroPairs in group: %w", err)
+	}
+	if len(hmps) != 0 {
+		// HyperMetroPair can not be removed from running group
+		if _, err := c.suspendHyperMetroCGIfRunning(ctx, hyperMetroCGID); err != nil {
+			return err
+		}
+	}
+	for _, hmp := range hmps {
+		if err := c.RemoveHyperMetroPairFromCG(ctx, hyperMetroCGID, hmp.ID); err != nil {
+			return fmt.Errorf("failed to remove HyperMetroPair from group (ID: %s): %w", hmp.ID, err)
+		}
+	}
+
+	spath := fmt.Sprintf("/HyperMetro_ConsistentGroup/%s", hyperMetroCGID)
+
+	req, err := c.LocalDevice.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = c.LocalDevice.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// AddHyperMetroPairToCG add HyperMetroPair to HyperMetro consistency group
+func (c *Client) AddHyperMetroPairToCG(ctx context.Context, hyperMetroCGID, hyperMetroPairID string) error {
+	spath := "/hyperMetro/associate/pair"
+	param := AssociateParam{
+		ID:               hyperMetroCGID,
+		ASSOCIATEOBJID:   hyperMetroPairID,
+		ASSOCIATEOBJTYPE: TypeHyperMetroPair,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := c.LocalDevice.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = c.LocalDevice.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// RemoveHyperMetroPairFromCG remove HyperMetroPair from HyperMetro consistency group
+func (c *Client) RemoveHyperMetroPairFromCG(ctx context.Context, hyperMetroCGID, hyperMetroPairID string) error {
+	spath := "/hyperMetro/associate/pair"
+	param := &AssociateParam{
+		ID:               hyperMetroCGID,
+		ASSOCIATEOBJID:   hyperMetroPairID,
+		ASSOCIATEOBJTYPE: TypeHyperMetroPair,
+	}
+
+	req, err := c.LocalDevice.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddAssociateParam(req, param)
+
+	var i interface{} // this endpoint return N/A
+	if err = c.LocalDevice.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// GetHyperMetroCGPairs get HyperMetroPairs in HyperMetro consistency group
+func (c *Client) GetHyperMetroCGPairs(ctx context.Context, hyperMetroCGID string) ([]HyperMetroPair, error) {
+	return c.GetHyperMetroPairs(ctx, &SearchQuery{
+		Filter: ToFilter("CGID", hyperMetroCGID),
+		Range:  "[0-4095]",
+	})
+}
+
+// SuspendHyperMetroCG suspend HyperMetro sync of all HyperMetroPairs in group.
+func (c *Client) SuspendHyperMetroCG(ctx context.Context, hyperMetroCGID string) error {
+	return c.putHyperMetroAction(ctx, "/HyperMetro_ConsistentGroup/stop", hyperMetroCGID, TypeHyperMetroCG)
+}
+
+// SyncHyperMetroCG start to sync all HyperMetroPairs in group.
+func (c *Client) SyncHyperMetroCG(ctx context.Context, hyperMetroCGID string) error {
+	return c.putHyperMetroAction(ctx, "/HyperMetro_ConsistentGroup/sync", hyperMetroCGID, TypeHyperMetroCG)
+}
+
+// suspendHyperMetroPairOrCG suspend HyperMetroPair, or HyperMetro consistency group if HyperMetroPair is in group.
+// HyperMetroPair in group can not be suspended alone.
+func (c *Client) suspendHyperMetroPairOrCG(ctx context.Context, hmp *HyperMetroPair) error {
+	if hmp.ISINCG == "true" {
+		return c.SuspendHyperMetroCG(ctx, hmp.CGID)
+	}
+	return c.SuspendHyperMetroPair(ctx, hmp.ID)
+}
+
+// syncHyperMetroPairOrCG start to sync HyperMetroPair, or HyperMetro consistency group if HyperMetroPair is in group.
+func (c *Client) syncHyperMetroPairOrCG(ctx context.Context, hmp *HyperMetroPair) error {
+	if hmp.ISINCG == "true" {
+		return c.SyncHyperMetroCG(ctx, hmp.CGID)
+	}
+	return c.SyncHyperMetroPair(ctx, hmp.ID)
+}
+
+// suspendHyperMetroCGIfRunning suspend HyperMetro consistency group if it is normal or synchronizing.
+// return true if group is suspended by this function.
+func (c *Client) suspendHyperMetroCGIfRunning(ctx context.Context, hyperMetroCGID string) (bool, error) {
+	cg, err := c.GetHyperMetroCG(ctx, hyperMetroCGID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get HyperMetro consistency group: %w", err)
+	}
+
+	switch cg.State() {
+	case HyperMetroPairStateNormal, HyperMetroPairStateSynchronizing:
+		if err := c.SuspendHyperMetroCG(ctx, hyperMetroCGID); err != nil {
+			return false, fmt.Errorf("failed to suspend HyperMetro consistency group: %w", err)
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// modifyHyperMetroCG suspend running HyperMetro consistency group, call modify (ex: add or remove HyperMetroPairs),
+// and re-sync group. members of running group can not be changed.
+// group is re-synced even if modify is failed, and not re-synced if group has no HyperMetroPair.
+func (c *Client) modifyHyperMetroCG(ctx context.Context, hyperMetroCGID string, modify func() error) error {
+	suspended, err := c.suspendHyperMetroCGIfRunning(ctx, hyperMetroCGID)
+	if err != nil {
+		return err
+	}
+
+	modifyErr := modify()
+
+	if suspended {
+		_, err := c.GetHyperMetroCGPairs(ctx, hyperMetroCGID)
+		switch {
+		case err == ErrHyperMetroPairNotFound:
+		case err != nil:
+			c.LocalDevice.Logger.Printf("failed to get HyperMetroPairs in group: %v\n", err)
+		default:
+			if err := c.SyncHyperMetroCG(ctx, hyperMetroCGID); err != nil {
+				if modifyErr != nil {
+					c.LocalDevice.Logger.Printf("failed to re-sync HyperMetro consistency group: %v\n", err)
+				} else {
+					modifyErr = fmt.Errorf("failed to re-sync HyperMetro consistency group: %w", err)
+				}
+			}
+		}
+	}
+
+	return modifyErr
+}
+
+// SwitchHyperMetroCGPriority swap preferred site of all HyperMetroPairs in group.
+// group must be suspended.
+func (c *Client) SwitchHyperMetroCGPriority(ctx context.Context, hyperMetroCGID string) error {
+	return c.putHyperMetroAction(ctx, "/HyperMetro_ConsistentGroup/switch", hyperMetroCGID, TypeHyperMetroCG)
+}
+
+// CreateVolumesInCG create blank HyperMetroPairs and add them to HyperMetro consistency group.
+// group is suspended while adding, and re-synced after added. created volumes are deleted if failed.
+func (c *Client) CreateVolumesInCG(ctx context.Context, names []uuid.UUID, capacityGB int, storagePoolName, hyperMetroDomainID, hyperMetroCGID string) ([]HyperMetroPair, error) {
+	if len(names) == 0 {
+		return nil, errors.New("names is empty")
+	}
+
+	cg, err := c.GetHyperMetroCG(ctx, hyperMetroCGID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HyperMetro consistency group: %w", err)
+	}
+	if cg.DOMAINID != hyperMetroDomainID {
+		return nil, fmt.Errorf("HyperMetro consistency group is in other domain (DOMAINID: %s)", cg.DOMAINID)
+	}
+
+	var hmps []HyperMetroPair
+	defer func() {
+		if err != nil {
+			for _, hmp := range hmps {
+				if err := c.DeleteVolume(ctx, hmp.ID); err != nil {
+					c.LocalDevice.Logger.Printf("failed to delete volume: %v\n", err)
+				}
+			}
+		}
+	}()
+
+	for _, name := range names {
+		var hmp *HyperMetroPair
+		hmp, err = c.CreateVolumeRaw(ctx, name, capacityGB, storagePoolName, hyperMetroDomainID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create volume (name: %s): %w", name, err)
+		}
+		hmps = append(hmps, *hmp)
+	}
+
+	err = c.modifyHyperMetroCG(ctx, cg.ID, func() error {
+		for _, hmp := range hmps {
+			// HyperMetroPair must be suspended to add to suspended group
+			if hmp.RUNNINGSTATUS != strconv.Itoa(StatusPause) {
+				if err := c.SuspendHyperMetroPair(ctx, hmp.ID); err != nil {
+					return fmt.Errorf("failed to suspend HyperMetroPair (ID: %s): %w", hmp.ID, err)
+				}
+			}
+			if err := c.AddHyperMetroPairToCG(ctx, cg.ID, hmp.ID); err != nil {
+				return fmt.Errorf("failed to add HyperMetroPair to HyperMetro consistency group (ID: %s): %w", hmp.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// re-read HyperMetroPairs to get CGID and ISINCG after added
+	for i := range hmps {
+		var hmp *HyperMetroPair
+		hmp, err = c.GetHyperMetroPair(ctx, hmps[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get HyperMetroPair (ID: %s): %w", hmps[i].ID, err)
+		}
+		hmps[i] = *hmp
+	}
+
+	return hmps, nil
+}
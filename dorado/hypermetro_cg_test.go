@@ -0,0 +1,205 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestClient_CreateHyperMetroCG(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	u := uuid.FromStringOrNil("77bea474-b5fb-4d5d-a3b8-0d4fd3a4b0c1")
+
+	mux.HandleFunc("/HyperMetro_ConsistentGroup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		testBody(t, r, map[string]interface{}{
+			"NAME":                EncodeLunName(u),
+			"DESCRIPTION":         u.String(),
+			"DOMAINID":            "2a4c2d1100eaf02c",
+			"SPEED":               float64(SpeedMedium),
+			"RECOVERYPOLICY":      "1",
+			"PRIORITYSTATIONTYPE": "0",
+		})
+
+		fmt.Fprint(w, `{"data": {"ID": "5", "DOMAINID": "2a4c2d1100eaf02c", "RUNNINGSTATUS": "1", "TYPE": 15364}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	// zero fields of opt are filled by default values
+	for _, opt := range []*HyperMetroPairOption{nil, {IsFirstSync: true}} {
+		cg, err := client.CreateHyperMetroCG(context.Background(), u, "2a4c2d1100eaf02c", opt)
+		if err != nil {
+			t.Fatalf("CreateHyperMetroCG return err: %s", err)
+		}
+		if cg.ID != "5" || cg.State() != HyperMetroPairStateNormal {
+			t.Errorf("CreateHyperMetroCG return %+v", cg)
+		}
+	}
+}
+
+func TestClient_RemoveHyperMetroPairFromCG(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/hyperMetro/associate/pair", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+
+		got := r.URL.Query()
+		if got.Get("ID") != "5" || got.Get("ASSOCIATEOBJID") != "2a4c2d1100eaf02c0000000000000001" || got.Get("ASSOCIATEOBJTYPE") != "15361" {
+			t.Errorf("RemoveHyperMetroPairFromCG query %v", got)
+		}
+
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	if err := client.RemoveHyperMetroPairFromCG(context.Background(), "5", "2a4c2d1100eaf02c0000000000000001"); err != nil {
+		t.Fatalf("RemoveHyperMetroPairFromCG return err: %s", err)
+	}
+}
+
+func TestClient_ExtendVolume_InCG(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var got []string
+	record := func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		got = append(got, r.URL.Path)
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	}
+	mux.HandleFunc("/HyperMetroPair/2a4c2d1100eaf02c0000000000000001", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "2a4c2d1100eaf02c0000000000000001", "LOCALOBJID": "517", "REMOTEOBJID": "514", "ISINCG": "true", "CGID": "5", "RUNNINGSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/HyperMetroPair/disable_hcpair", record)
+	mux.HandleFunc("/HyperMetroPair/synchronize_hcpair", record)
+	mux.HandleFunc("/HyperMetro_ConsistentGroup/stop", record)
+	mux.HandleFunc("/HyperMetro_ConsistentGroup/sync", record)
+	mux.HandleFunc("/lun/expand", record)
+
+	if err := client.ExtendVolume(context.Background(), "2a4c2d1100eaf02c0000000000000001", 20); err != nil {
+		t.Fatalf("ExtendVolume return err: %s", err)
+	}
+
+	want := []string{
+		"/HyperMetro_ConsistentGroup/stop",
+		"/lun/expand",
+		"/lun/expand",
+		"/HyperMetro_ConsistentGroup/sync",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtendVolume request %v, want %v", got, want)
+	}
+}
+
+func TestClient_CreateVolumesInCG(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var got []string
+	record := func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	}
+	mux.HandleFunc("/HyperMetro_ConsistentGroup/5", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "5", "DOMAINID": "1", "RUNNINGSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/storagepool", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "0", "NAME": "pool"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	lunID := 10
+	mux.HandleFunc("/lun", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		lunID++
+		fmt.Fprintf(w, `{"data": {"ID": "%d"}, "error": {"code": 0, "description": "0"}}`, lunID)
+	})
+	pairID := 0
+	mux.HandleFunc("/HyperMetroPair", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			pairID++
+			fmt.Fprintf(w, `{"data": {"ID": "%d", "RUNNINGSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`, pairID)
+		case "GET":
+			fmt.Fprint(w, `{"data": [{"ID": "1", "CGID": "5", "ISINCG": "true"}, {"ID": "2", "CGID": "5", "ISINCG": "true"}], "error": {"code": 0, "description": "0"}}`)
+		default:
+			t.Errorf("Request method: %v, want GET or POST", r.Method)
+		}
+	})
+	for _, id := range []string{"1", "2"} {
+		body := fmt.Sprintf(`{"data": {"ID": "%s", "CGID": "5", "ISINCG": "true", "RUNNINGSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`, id)
+		mux.HandleFunc("/HyperMetroPair/"+id, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			fmt.Fprint(w, body)
+		})
+	}
+	mux.HandleFunc("/HyperMetro_ConsistentGroup/stop", record)
+	mux.HandleFunc("/HyperMetro_ConsistentGroup/sync", record)
+	mux.HandleFunc("/HyperMetroPair/disable_hcpair", record)
+	mux.HandleFunc("/hyperMetro/associate/pair", record)
+
+	names := []uuid.UUID{uuid.NewV4(), uuid.NewV4()}
+	hmps, err := client.CreateVolumesInCG(context.Background(), names, 1, "pool", "1", "5")
+	if err != nil {
+		t.Fatalf("CreateVolumesInCG return err: %s", err)
+	}
+	if len(hmps) != 2 || hmps[0].CGID != "5" || hmps[1].CGID != "5" {
+		t.Errorf("CreateVolumesInCG return %+v", hmps)
+	}
+
+	want := []string{
+		"PUT /HyperMetro_ConsistentGroup/stop",
+		"PUT /HyperMetroPair/disable_hcpair",
+		"POST /hyperMetro/associate/pair",
+		"PUT /HyperMetroPair/disable_hcpair",
+		"POST /hyperMetro/associate/pair",
+		"PUT /HyperMetro_ConsistentGroup/sync",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateVolumesInCG request %v, want %v", got, want)
+	}
+}
+
+func TestClient_DeleteHyperMetroCG(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var got []string
+	record := func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	}
+	mux.HandleFunc("/HyperMetroPair", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "1", "CGID": "5", "ISINCG": "true"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/HyperMetro_ConsistentGroup/5", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"data": {"ID": "5", "DOMAINID": "1", "RUNNINGSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`)
+			return
+		}
+		record(w, r)
+	})
+	mux.HandleFunc("/HyperMetro_ConsistentGroup/stop", record)
+	mux.HandleFunc("/hyperMetro/associate/pair", record)
+
+	if err := client.DeleteHyperMetroCG(context.Background(), "5"); err != nil {
+		t.Fatalf("DeleteHyperMetroCG return err: %s", err)
+	}
+
+	want := []string{
+		"PUT /HyperMetro_ConsistentGroup/stop",
+		"DELETE /hyperMetro/associate/pair",
+		"DELETE /HyperMetro_ConsistentGroup/5",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeleteHyperMetroCG request %v, want %v", got, want)
+	}
+}
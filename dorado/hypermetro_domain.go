@@ -1,13 +1,13 @@
 package dorado
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
-// NOTE(whywaita): implement only GET.
-// HyperMetroDomain is a few under our usage.
-
 // HyperMetroDomain is domain of HyperMetro
 type HyperMetroDomain struct {
 	CPSID          string `json:"CPSID"`
@@ -51,3 +51,241 @@ func (d *Device) GetHyperMetroDomains(ctx context.Context, query *SearchQuery) (
 
 	return hyperMetroDomains, nil
 }
+
+// HyperMetroDomainRemoteDevice is element of REMOTEDEVICES in HyperMetroDomain
+type HyperMetroDomainRemoteDevice struct {
+	DevID   string `json:"devId"`   // = RemoteArray.ID
+	DevESN  string `json:"devESN"`  // = RemoteArray.SN
+	DevName string `json:"devName"` // = RemoteArray.NAME
+}
+
+// RemoteDevices parse REMOTEDEVICES of HyperMetroDomain
+func (hmd *HyperMetroDomain) RemoteDevices() ([]HyperMetroDomainRemoteDevice, error) {
+	var remoteDevices []HyperMetroDomainRemoteDevice
+	if hmd.REMOTEDEVICES == "" {
+		return nil, nil
+	}
+	if err := json.Unmarshal([]byte(hmd.REMOTEDEVICES), &remoteDevices); err != nil {
+		return nil, fmt.Errorf("failed to parse REMOTEDEVICES: %w", err)
+	}
+
+	return remoteDevices, nil
+}
+
+// HasQuorumServer return true if HyperMetroDomain is arbitrated by quorum server.
+// if false, HyperMetroDomain is arbitrated by static priority (preferred site).
+func (hmd *HyperMetroDomain) HasQuorumServer() bool {
+	return hmd.CPSID != "" || hmd.STANDBYCPSID != ""
+}
+
+// GetHyperMetroDomain get HyperMetroDomain object by id.
+func (c *Client) GetHyperMetroDomain(ctx context.Context, hyperMetroDomainID string) (*HyperMetroDomain, error) {
+	return c.LocalDevice.GetHyperMetroDomain(ctx, hyperMetroDomainID)
+}
+
+// GetHyperMetroDomain get HyperMetroDomain object by id in device.
+func (d *Device) GetHyperMetroDomain(ctx context.Context, hyperMetroDomainID string) (*HyperMetroDomain, error) {
+	spath := fmt.Sprintf("/HyperMetroDomain/%s", hyperMetroDomainID)
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	hyperMetroDomain := &HyperMetroDomain{}
+	if err = d.requestWithRetry(req, hyperMetroDomain, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return hyperMetroDomain, nil
+}
+
+// HyperMetroDomainParam is parameter of CreateHyperMetroDomain and UpdateHyperMetroDomain.
+// empty fields are not sent, use SetQuorumServer or SetStandbyQuorumServer to clear quorum servers.
+type HyperMetroDomainParam struct {
+	NAME          string `json:"NAME,omitempty"`
+	DESCRIPTION   string `json:"DESCRIPTION,omitempty"`
+	REMOTEDEVICES string `json:"REMOTEDEVICES,omitempty"` // only for create
+	DOMAINTYPE    string `json:"DOMAINTYPE,omitempty"`    // only for create, "1" is SAN
+	CPSID         string `json:"CPSID,omitempty"`         // quorum server ID
+	STANDBYCPSID  string `json:"STANDBYCPSID,omitempty"`  // standby quorum server ID
+}
+
+// CreateHyperMetroDomain create HyperMetroDomain between local device and remote device.
+// remote device must be registered as remote array in local device.
+// quorumServerID and standbyQuorumServerID are IDs in local device, empty is static priority mode.
+func (c *Client) CreateHyperMetroDomain(ctx context.Context, name, quorumServerID, standbyQuorumServerID string) (*HyperMetroDomain, error) {
+	remoteArray, err := c.LocalDevice.DiscoverRemoteArray(ctx, c.RemoteDevice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover remote array of remote device: %w", err)
+	}
+
+	remoteDevices, err := json.Marshal([]HyperMetroDomainRemoteDevice{
+		{
+			DevID:   remoteArray.ID,
+			DevESN:  remoteArray.SN,
+			DevName: remoteArray.NAME,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal REMOTEDEVICES: %w", err)
+	}
+
+	param := HyperMetroDomainParam{
+		NAME:          name,
+		REMOTEDEVICES: string(remoteDevices),
+		DOMAINTYPE:    "1",
+		CPSID:         quorumServerID,
+		STANDBYCPSID:  standbyQuorumServerID,
+	}
+
+	return c.LocalDevice.CreateHyperMetroDomain(ctx, param)
+}
+
+// CreateHyperMetroDomain create HyperMetroDomain in device.
+func (d *Device) CreateHyperMetroDomain(ctx context.Context, param HyperMetroDomainParam) (*HyperMetroDomain, error) {
+	spath := "/HyperMetroDomain"
+
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	hyperMetroDomain := &HyperMetroDomain{}
+	if err = d.requestWithRetry(req, hyperMetroDomain, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return hyperMetroDomain, nil
+}
+
+// UpdateHyperMetroDomain modify name, description or quorum servers of HyperMetroDomain.
+func (c *Client) UpdateHyperMetroDomain(ctx context.Context, hyperMetroDomainID string, param HyperMetroDomainParam) error {
+	return c.LocalDevice.UpdateHyperMetroDomain(ctx, hyperMetroDomainID, param)
+}
+
+// UpdateHyperMetroDomain modify HyperMetroDomain in device.
+func (d *Device) UpdateHyperMetroDomain(ctx context.Context, hyperMetroDomainID string, param HyperMetroDomainParam) error {
+	return d.putHyperMetroDomain(ctx, hyperMetroDomainID, param)
+}
+
+func (d *Device) putHyperMetroDomain(ctx context.Context, hyperMetroDomainID string, body interface{}) error {
+	spath := fmt.Sprintf("/HyperMetroDomain/%s", hyperMetroDomainID)
+
+	jb, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// SetQuorumServer change quorum server of HyperMetroDomain.
+// empty quorumServerID remove quorum server (static priority mode if standby quorum server is also empty).
+func (c *Client) SetQuorumServer(ctx context.Context, hyperMetroDomainID, quorumServerID string) error {
+	// send CPSID explicitly even if empty
+	return c.LocalDevice.putHyperMetroDomain(ctx, hyperMetroDomainID, map[string]string{"CPSID": quorumServerID})
+}
+
+// SetStandbyQuorumServer change standby quorum server of HyperMetroDomain.
+// empty standbyQuorumServerID remove standby quorum server.
+func (c *Client) SetStandbyQuorumServer(ctx context.Context, hyperMetroDomainID, standbyQuorumServerID string) error {
+	// send STANDBYCPSID explicitly even if empty
+	return c.LocalDevice.putHyperMetroDomain(ctx, hyperMetroDomainID, map[string]string{"STANDBYCPSID": standbyQuorumServerID})
+}
+
+// DeleteHyperMetroDomain delete HyperMetroDomain.
+// all HyperMetroPairs and consistency groups in domain must be deleted before call this method.
+func (c *Client) DeleteHyperMetroDomain(ctx context.Context, hyperMetroDomainID string) error {
+	return c.LocalDevice.DeleteHyperMetroDomain(ctx, hyperMetroDomainID)
+}
+
+// DeleteHyperMetroDomain delete HyperMetroDomain in device.
+func (d *Device) DeleteHyperMetroDomain(ctx context.Context, hyperMetroDomainID string) error {
+	spath := fmt.Sprintf("/HyperMetroDomain/%s", hyperMetroDomainID)
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// HyperMetroDomainHealth is result of CheckHyperMetroDomainHealth
+type HyperMetroDomainHealth struct {
+	Domain HyperMetroDomain
+	// CanArbitrate is true if both devices can reach same quorum server.
+	// if false, preferred site wins when link between devices is down.
+	CanArbitrate bool
+	// QuorumServerName is name of quorum server that used for arbitration now.
+	QuorumServerName string
+	// Reasons is why arbitration is not possible or degraded
+	Reasons []string
+}
+
+// CheckHyperMetroDomainHealth check that HyperMetroDomain can be arbitrated by quorum server now.
+// quorum server is found by name in each device, because ID of quorum server is different in each device.
+func (c *Client) CheckHyperMetroDomainHealth(ctx context.Context, hyperMetroDomainID string) (*HyperMetroDomainHealth, error) {
+	hmd, err := c.GetHyperMetroDomain(ctx, hyperMetroDomainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HyperMetroDomain: %w", err)
+	}
+
+	health := &HyperMetroDomainHealth{Domain: *hmd}
+	if hmd.RUNNINGSTATUS != strconv.Itoa(StatusNormal) {
+		health.Reasons = append(health.Reasons, fmt.Sprintf("HyperMetroDomain is not normal (RUNNINGSTATUS: %s)", hmd.RUNNINGSTATUS))
+	}
+	if !hmd.HasQuorumServer() {
+		health.Reasons = append(health.Reasons, "HyperMetroDomain has no quorum server (static priority mode)")
+		return health, nil
+	}
+
+	for _, name := range []string{hmd.CPSNAME, hmd.STANDBYCPSNAME} {
+		if name == "" {
+			continue
+		}
+
+		localUp, err := c.LocalDevice.quorumServerIsReachable(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check quorum server in local device (name: %s): %w", name, err)
+		}
+		remoteUp, err := c.RemoteDevice.quorumServerIsReachable(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check quorum server in remote device (name: %s): %w", name, err)
+		}
+
+		if !localUp {
+			health.Reasons = append(health.Reasons, fmt.Sprintf("local device can not reach quorum server (name: %s)", name))
+		}
+		if !remoteUp {
+			health.Reasons = append(health.Reasons, fmt.Sprintf("remote device can not reach quorum server (name: %s)", name))
+		}
+		if localUp && remoteUp && !health.CanArbitrate {
+			health.CanArbitrate = true
+			health.QuorumServerName = name
+		}
+	}
+
+	return health, nil
+}
@@ -64,3 +64,103 @@ func TestDevice_GetHyperMetroDomains(t *testing.T) {
 		t.Errorf("GetHyperMetroDomains return %+v, want %+v", hmds, want)
 	}
 }
+
+func TestClient_CheckHyperMetroDomainHealth(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/HyperMetroDomain/8038bc14bd750100", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"CPSID": "0", "CPSNAME": "qs01", "STANDBYCPSID": "1", "STANDBYCPSNAME": "qs02", "ID": "8038bc14bd750100", "RUNNINGSTATUS": "1", "TYPE": 15362}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/QuorumServer", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("filter") {
+		case "NAME::qs01":
+			fmt.Fprint(w, `{"data": [{"ID": "0", "NAME": "qs01"}], "error": {"code": 0, "description": "0"}}`)
+		case "NAME::qs02":
+			fmt.Fprint(w, `{"data": [{"ID": "1", "NAME": "qs02"}], "error": {"code": 0, "description": "0"}}`)
+		}
+	})
+	mux.HandleFunc("/QuorumServerLink", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("filter") {
+		case "PARENTID::0":
+			fmt.Fprint(w, `{"data": [{"ID": "0", "PARENTID": "0", "LINKSTATUS": "11"}], "error": {"code": 0, "description": "0"}}`)
+		case "PARENTID::1":
+			fmt.Fprint(w, `{"data": [{"ID": "1", "PARENTID": "1", "LINKSTATUS": "11"}, {"ID": "2", "PARENTID": "1", "LINKSTATUS": "10"}], "error": {"code": 0, "description": "0"}}`)
+		}
+	})
+
+	health, err := client.CheckHyperMetroDomainHealth(context.Background(), "8038bc14bd750100")
+	if err != nil {
+		t.Fatalf("CheckHyperMetroDomainHealth return err: %s", err)
+	}
+
+	if !health.CanArbitrate || health.QuorumServerName != "qs02" {
+		t.Errorf("CheckHyperMetroDomainHealth return CanArbitrate: %t, QuorumServerName: %s, want true, qs02", health.CanArbitrate, health.QuorumServerName)
+	}
+	want := []string{
+		"local device can not reach quorum server (name: qs01)",
+		"remote device can not reach quorum server (name: qs01)",
+	}
+	if !reflect.DeepEqual(health.Reasons, want) {
+		t.Errorf("CheckHyperMetroDomainHealth return Reasons %v, want %v", health.Reasons, want)
+	}
+}
+
+func TestClient_SetStandbyQuorumServer(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var got []map[string]interface{}
+	mux.HandleFunc("/HyperMetroDomain/2a4c2d1100eaf02c", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+
+		got = append(got, testDecodeBody(t, r))
+
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	for _, id := range []string{"1", ""} {
+		if err := client.SetStandbyQuorumServer(context.Background(), "2a4c2d1100eaf02c", id); err != nil {
+			t.Fatalf("SetStandbyQuorumServer return err: %s", err)
+		}
+	}
+
+	want := []map[string]interface{}{
+		{"STANDBYCPSID": "1"},
+		{"STANDBYCPSID": ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SetStandbyQuorumServer request %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_SetQuorumServer(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var got []map[string]interface{}
+	mux.HandleFunc("/HyperMetroDomain/2a4c2d1100eaf02c", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+
+		got = append(got, testDecodeBody(t, r))
+
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	for _, id := range []string{"1", ""} {
+		if err := client.SetQuorumServer(context.Background(), "2a4c2d1100eaf02c", id); err != nil {
+			t.Fatalf("SetQuorumServer return err: %s", err)
+		}
+	}
+
+	want := []map[string]interface{}{
+		{"CPSID": "1"},
+		{"CPSID": ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SetQuorumServer request %+v, want %+v", got, want)
+	}
+}
@@ -19,6 +19,108 @@ type HyperMetroPairParam struct {
 	ISFIRSTSYNC     bool   `json:"ISFIRSTSYNC"`
 }
 
+// HyperMetroRecoveryPolicy is RECONVERYPOLICY of HyperMetroPair
+type HyperMetroRecoveryPolicy string
+
+// HyperMetroRecoveryPolicy const
+const (
+	// RecoveryPolicyAuto start to sync automatically when link is recovered
+	RecoveryPolicyAuto HyperMetroRecoveryPolicy = "1"
+	// RecoveryPolicyManual keep suspended when link is recovered, call SyncHyperMetroPair
+	RecoveryPolicyManual HyperMetroRecoveryPolicy = "2"
+)
+
+// HyperMetroPairOption is option of CreateHyperMetroPairWithOption.
+// zero fields are filled by NewHyperMetroPairOption.
+type HyperMetroPairOption struct {
+	Speed          Speed
+	RecoveryPolicy HyperMetroRecoveryPolicy
+	// IsFirstSync copy data from local LUN to remote LUN after created.
+	// false is for blank LUNs or LUNs that already have same data.
+	IsFirstSync bool
+}
+
+// NewHyperMetroPairOption create default HyperMetroPairOption
+func NewHyperMetroPairOption() *HyperMetroPairOption {
+	return &HyperMetroPairOption{
+		Speed:          SpeedMedium,
+		RecoveryPolicy: RecoveryPolicyAuto,
+		IsFirstSync:    false,
+	}
+}
+
+func (o *HyperMetroPairOption) withDefault() *HyperMetroPairOption {
+	opt := NewHyperMetroPairOption()
+	if o == nil {
+		return opt
+	}
+
+	opt.IsFirstSync = o.IsFirstSync
+	if o.Speed != 0 {
+		opt.Speed = o.Speed
+	}
+	if o.RecoveryPolicy != "" {
+		opt.RecoveryPolicy = o.RecoveryPolicy
+	}
+	return opt
+}
+
+func newHyperMetroPairParam(hyperMetroDomainID string, localLunID, remoteLunID int, opt *HyperMetroPairOption) *HyperMetroPairParam {
+	opt = opt.withDefault()
+
+	return &HyperMetroPairParam{
+		RECONVERYPOLICY: string(opt.RecoveryPolicy),
+		DOMAINID:        hyperMetroDomainID,
+		SPEED:           int(opt.Speed),
+		HCRESOURCETYPE:  "1", // LUN
+		REMOTEOBJID:     strconv.Itoa(remoteLunID),
+		LOCALOBJID:      strconv.Itoa(localLunID),
+		ISFIRSTSYNC:     opt.IsFirstSync,
+	}
+}
+
+// HyperMetroPairState is RUNNINGSTATUS of HyperMetroPair
+type HyperMetroPairState int
+
+// HyperMetroPairState const
+const (
+	HyperMetroPairStateNormal           HyperMetroPairState = StatusNormal
+	HyperMetroPairStateSynchronizing    HyperMetroPairState = StatusSynchronizing
+	HyperMetroPairStateInvalid          HyperMetroPairState = StatusInvalid
+	HyperMetroPairStatePause            HyperMetroPairState = StatusPause
+	HyperMetroPairStateForcedStart      HyperMetroPairState = StatusForcedStart
+	HyperMetroPairStateToBeSynchronized HyperMetroPairState = StatusToBeSynchronized
+)
+
+// String is function compatible for fmt.Stringer
+func (s HyperMetroPairState) String() string {
+	switch s {
+	case HyperMetroPairStateNormal:
+		return "Normal"
+	case HyperMetroPairStateSynchronizing:
+		return "Synchronizing"
+	case HyperMetroPairStateInvalid:
+		return "Invalid"
+	case HyperMetroPairStatePause:
+		return "Pause"
+	case HyperMetroPairStateForcedStart:
+		return "ForcedStart"
+	case HyperMetroPairStateToBeSynchronized:
+		return "ToBeSynchronized"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int(s))
+	}
+}
+
+// NeedsSync return true if data of LUNs may be different and SyncHyperMetroPair is needed.
+func (s HyperMetroPairState) NeedsSync() bool {
+	switch s {
+	case HyperMetroPairStatePause, HyperMetroPairStateForcedStart, HyperMetroPairStateToBeSynchronized:
+		return true
+	}
+	return false
+}
+
 // HyperMetroPair is object of LUN (synced by HyperMetro)
 type HyperMetroPair struct {
 	CAPACITYBYTE             string `json:"CAPACITYBYTE"`
@@ -56,6 +158,25 @@ type HyperMetroPair struct {
 	WRITESECONDARYTIMEOUT    string `json:"WRITESECONDARYTIMEOUT"`
 }
 
+// State return RUNNINGSTATUS of HyperMetroPair
+func (hmp *HyperMetroPair) State() HyperMetroPairState {
+	state, err := strconv.Atoi(hmp.RUNNINGSTATUS)
+	if err != nil {
+		return 0
+	}
+	return HyperMetroPairState(state)
+}
+
+// IsLinkUp return true if link between local device and remote device of HyperMetroPair is up
+func (hmp *HyperMetroPair) IsLinkUp() bool {
+	return hmp.LINKSTATUS == "1"
+}
+
+// IsIsolated return true if LUN of HyperMetroPair is isolated by slow I/O of secondary side
+func (hmp *HyperMetroPair) IsIsolated() bool {
+	return hmp.ISISOLATION == "true"
+}
+
 // GetHyperMetroPairs get HyperMetro objects by query
 func (c *Client) GetHyperMetroPairs(ctx context.Context, query *SearchQuery) ([]HyperMetroPair, error) {
 	spath := "/HyperMetroPair"
@@ -83,6 +204,33 @@ func (c *Client) GetHyperMetroPairs(ctx context.Context, query *SearchQuery) ([]
 	return hyperMetroPairs, nil
 }
 
+// hyperMetroPairsPageSize is count of HyperMetroPairs in a page of listAllHyperMetroPairs
+const hyperMetroPairsPageSize = 4095
+
+// listAllHyperMetroPairs get all HyperMetroPairs by paging with explicit range.
+func (c *Client) listAllHyperMetroPairs(ctx context.Context) ([]HyperMetroPair, error) {
+	var all []HyperMetroPair
+	for start := 0; ; start += hyperMetroPairsPageSize {
+		pairs, err := c.GetHyperMetroPairs(ctx, &SearchQuery{
+			Range: fmt.Sprintf("[%d-%d]", start, start+hyperMetroPairsPageSize),
+		})
+		if err != nil && err != ErrHyperMetroPairNotFound {
+			return nil, err
+		}
+		all = append(all, pairs...)
+
+		if len(pairs) < hyperMetroPairsPageSize {
+			break
+		}
+	}
+
+	if len(all) == 0 {
+		return nil, ErrHyperMetroPairNotFound
+	}
+
+	return all, nil
+}
+
 // GetHyperMetroPair get HyperMetro object by id
 func (c *Client) GetHyperMetroPair(ctx context.Context, hyperMetroPairID string) (*HyperMetroPair, error) {
 	spath := fmt.Sprintf("/HyperMetroPair/%s", hyperMetroPairID)
@@ -102,28 +250,30 @@ func (c *Client) GetHyperMetroPair(ctx context.Context, hyperMetroPairID string)
 
 // CreateHyperMetroPair create HyperMetroPair.
 func (c *Client) CreateHyperMetroPair(ctx context.Context, hyperMetroDomainID string, localLunID, remoteLunID int) (*HyperMetroPair, error) {
+	return c.CreateHyperMetroPairWithOption(ctx, hyperMetroDomainID, localLunID, remoteLunID, nil)
+}
+
+// CreateHyperMetroPairWithOption create HyperMetroPair with option.
+func (c *Client) CreateHyperMetroPairWithOption(ctx context.Context, hyperMetroDomainID string, localLunID, remoteLunID int, opt *HyperMetroPairOption) (*HyperMetroPair, error) {
+	return c.LocalDevice.createHyperMetroPair(ctx, newHyperMetroPairParam(hyperMetroDomainID, localLunID, remoteLunID, opt))
+}
+
+// createHyperMetroPair create HyperMetroPair in device.
+// LOCALOBJID is LUN ID in d, and data is synchronized from d if ISFIRSTSYNC is true.
+func (d *Device) createHyperMetroPair(ctx context.Context, param *HyperMetroPairParam) (*HyperMetroPair, error) {
 	spath := "/HyperMetroPair"
-	param := &HyperMetroPairParam{
-		RECONVERYPOLICY: "1",
-		DOMAINID:        hyperMetroDomainID,
-		SPEED:           2,
-		HCRESOURCETYPE:  "1",
-		REMOTEOBJID:     strconv.Itoa(remoteLunID),
-		LOCALOBJID:      strconv.Itoa(localLunID),
-		ISFIRSTSYNC:     false,
-	}
 
 	jb, err := json.Marshal(param)
 	if err != nil {
 		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
 	}
-	req, err := c.LocalDevice.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
 	if err != nil {
 		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
 	}
 
 	hyperMetroPair := &HyperMetroPair{}
-	if err = c.LocalDevice.requestWithRetry(req, hyperMetroPair, DefaultHTTPRetryCount); err != nil {
+	if err = d.requestWithRetry(req, hyperMetroPair, DefaultHTTPRetryCount); err != nil {
 		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
 	}
 
@@ -150,13 +300,34 @@ func (c *Client) DeleteHyperMetroPair(ctx context.Context, hyperMetroPairID stri
 
 // SuspendHyperMetroPair suspend HyperMetro sync.
 func (c *Client) SuspendHyperMetroPair(ctx context.Context, hyperMetroPairID string) error {
-	spath := "/HyperMetroPair/disable_hcpair"
+	return c.putHyperMetroAction(ctx, "/HyperMetroPair/disable_hcpair", hyperMetroPairID, TypeHyperMetroPair)
+}
+
+// SyncHyperMetroPair start to sync HyperMetro.
+func (c *Client) SyncHyperMetroPair(ctx context.Context, hyperMetroPairID string) error {
+	return c.putHyperMetroAction(ctx, "/HyperMetroPair/synchronize_hcpair", hyperMetroPairID, TypeHyperMetroPair)
+}
+
+// SwitchHyperMetroPairPriority swap preferred site of HyperMetroPair.
+// HyperMetroPair must be suspended.
+func (c *Client) SwitchHyperMetroPairPriority(ctx context.Context, hyperMetroPairID string) error {
+	return c.putHyperMetroAction(ctx, "/HyperMetroPair/switch_hcpair", hyperMetroPairID, TypeHyperMetroPair)
+}
+
+// ForceStartHyperMetroPair enable host access to LUN in local device of suspended HyperMetroPair.
+// use it only when remote device is down, data written to remote device may be lost by next sync.
+func (c *Client) ForceStartHyperMetroPair(ctx context.Context, hyperMetroPairID string) error {
+	return c.putHyperMetroAction(ctx, "/HyperMetroPair/forcestart_hcpair", hyperMetroPairID, TypeHyperMetroPair)
+}
+
+// putHyperMetroAction call action endpoint of HyperMetro object (HyperMetroPair or HyperMetro consistency group).
+func (c *Client) putHyperMetroAction(ctx context.Context, spath string, id string, objType int) error {
 	param := struct {
 		ID   string `json:"ID"`
 		TYPE string `json:"TYPE"`
 	}{
-		ID:   hyperMetroPairID,
-		TYPE: strconv.Itoa(TypeHyperMetroPair),
+		ID:   id,
+		TYPE: strconv.Itoa(objType),
 	}
 	jb, err := json.Marshal(param)
 	if err != nil {
@@ -176,15 +347,13 @@ func (c *Client) SuspendHyperMetroPair(ctx context.Context, hyperMetroPairID str
 	return nil
 }
 
-// SyncHyperMetroPair start to sync HyperMetro.
-func (c *Client) SyncHyperMetroPair(ctx context.Context, hyperMetroPairID string) error {
-	spath := "/HyperMetroPair/synchronize_hcpair"
+// SetHyperMetroPairSpeed change sync speed of HyperMetroPair.
+func (c *Client) SetHyperMetroPairSpeed(ctx context.Context, hyperMetroPairID string, speed Speed) error {
+	spath := fmt.Sprintf("/HyperMetroPair/%s", hyperMetroPairID)
 	param := struct {
-		ID   string `json:"ID"`
-		TYPE string `json:"TYPE"`
+		SPEED int `json:"SPEED"`
 	}{
-		ID:   hyperMetroPairID,
-		TYPE: strconv.Itoa(TypeHyperMetroPair),
+		SPEED: int(speed),
 	}
 	jb, err := json.Marshal(param)
 	if err != nil {
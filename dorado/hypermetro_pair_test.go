@@ -107,4 +107,57 @@ func TestClient_GetHyperMetroPairs(t *testing.T) {
 	if !reflect.DeepEqual(hmps, want) {
 		t.Errorf("GetHyperMetroPairs return %+v, want %+v", hmps, want)
 	}
+	if hmps[0].State() != HyperMetroPairStatePause || !hmps[0].State().NeedsSync() {
+		t.Errorf("State return %s, want %s", hmps[0].State(), HyperMetroPairStatePause)
+	}
+}
+
+func TestClient_CreateHyperMetroPairWithOption(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/HyperMetroPair", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		testBody(t, r, map[string]interface{}{
+			"RECONVERYPOLICY": "2",
+			"DOMAINID":        "2a4c2d1100eaf02c",
+			"SPEED":           float64(SpeedHighest),
+			"HCRESOURCETYPE":  "1",
+			"REMOTEOBJID":     "514",
+			"LOCALOBJID":      "517",
+			"ISFIRSTSYNC":     true,
+		})
+
+		fmt.Fprint(w, `{"data": {"ID": "2a4c2d1100eaf02c0000000000000001", "RUNNINGSTATUS": "23", "TYPE": 15361}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	opt := &HyperMetroPairOption{
+		Speed:          SpeedHighest,
+		RecoveryPolicy: RecoveryPolicyManual,
+		IsFirstSync:    true,
+	}
+	hmp, err := client.CreateHyperMetroPairWithOption(context.Background(), "2a4c2d1100eaf02c", 517, 514, opt)
+	if err != nil {
+		t.Fatalf("CreateHyperMetroPairWithOption return err: %s", err)
+	}
+	if hmp.State() != HyperMetroPairStateSynchronizing {
+		t.Errorf("State return %s, want %s", hmp.State(), HyperMetroPairStateSynchronizing)
+	}
+}
+
+func TestNewHyperMetroPairParam_Default(t *testing.T) {
+	got := newHyperMetroPairParam("2a4c2d1100eaf02c", 517, 514, &HyperMetroPairOption{IsFirstSync: true})
+	want := &HyperMetroPairParam{
+		RECONVERYPOLICY: string(RecoveryPolicyAuto),
+		DOMAINID:        "2a4c2d1100eaf02c",
+		SPEED:           int(SpeedMedium),
+		HCRESOURCETYPE:  "1",
+		REMOTEOBJID:     "514",
+		LOCALOBJID:      "517",
+		ISFIRSTSYNC:     true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newHyperMetroPairParam return %+v, want %+v", got, want)
+	}
 }
@@ -0,0 +1,296 @@
+package dorado
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// HyperMetroPairEventType is type of HyperMetroPairEvent
+type HyperMetroPairEventType string
+
+// HyperMetroPairEventType const
+const (
+	// EventStateChanged is emitted when RUNNINGSTATUS of HyperMetroPair is changed
+	EventStateChanged HyperMetroPairEventType = "StateChanged"
+	// EventPairRemoved is emitted when HyperMetroPair is not found
+	EventPairRemoved HyperMetroPairEventType = "PairRemoved"
+	// EventLinkDown is emitted when link of HyperMetroPair become down
+	EventLinkDown HyperMetroPairEventType = "LinkDown"
+	// EventLinkUp is emitted when link of HyperMetroPair is recovered
+	EventLinkUp HyperMetroPairEventType = "LinkUp"
+	// EventIsolated is emitted when HyperMetroPair become isolated
+	EventIsolated HyperMetroPairEventType = "Isolated"
+	// EventResyncStarted is emitted when watcher start to sync HyperMetroPair (or consistency group)
+	EventResyncStarted HyperMetroPairEventType = "ResyncStarted"
+	// EventResyncFailed is emitted when watcher failed to sync HyperMetroPair (or consistency group)
+	EventResyncFailed HyperMetroPairEventType = "ResyncFailed"
+	// EventResyncGaveUp is emitted once when HyperMetroPair is not resynchronized anymore by MaxAttempts
+	EventResyncGaveUp HyperMetroPairEventType = "ResyncGaveUp"
+	// EventWatchError is emitted when watcher failed to get HyperMetroPairs
+	EventWatchError HyperMetroPairEventType = "WatchError"
+)
+
+// HyperMetroPairEvent is event of WatchHyperMetroPairs
+type HyperMetroPairEvent struct {
+	Type          HyperMetroPairEventType
+	Time          time.Time
+	Pair          HyperMetroPair // empty if Type is EventWatchError
+	PreviousState HyperMetroPairState
+	Err           error
+}
+
+// DefaultWatchInterval is interval of WatchHyperMetroPairs if interval is not positive
+const DefaultWatchInterval = 1 * time.Minute
+
+// ResyncPolicy is policy of automated resync in WatchHyperMetroPairs
+type ResyncPolicy struct {
+	// States is states that HyperMetroPair is resynchronized.
+	// ForcedStart is not recommended, data written to other side will be lost.
+	States []HyperMetroPairState
+	// ResyncPausedAfter resync HyperMetroPair that kept Pause for this duration (ex: ExtendVolume failed to resync).
+	// 0 is never resync paused HyperMetroPair, because Pause is also used by operator.
+	ResyncPausedAfter time.Duration
+	// MaxAttempts is max count to try resync until HyperMetroPair become Normal. 0 is unlimited.
+	// EventResyncGaveUp is emitted when attempts are exhausted.
+	MaxAttempts int
+	// Backoff is min interval between attempts of resync
+	Backoff time.Duration
+}
+
+// NewResyncPolicy create default ResyncPolicy (resync ToBeSynchronized only)
+func NewResyncPolicy() *ResyncPolicy {
+	return &ResyncPolicy{
+		States:            []HyperMetroPairState{HyperMetroPairStateToBeSynchronized},
+		ResyncPausedAfter: 0,
+		MaxAttempts:       3,
+		Backoff:           1 * time.Minute,
+	}
+}
+
+func (p *ResyncPolicy) hasState(state HyperMetroPairState) bool {
+	for _, s := range p.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// hyperMetroWatcher keep last observed HyperMetroPairs and decide events and resync.
+type hyperMetroWatcher struct {
+	policy ResyncPolicy
+
+	pairs       map[string]HyperMetroPair
+	pausedSince map[string]time.Time
+	attempts    map[string]int
+	lastAttempt map[string]time.Time
+	gaveUp      map[string]bool
+}
+
+func newHyperMetroWatcher(policy ResyncPolicy) *hyperMetroWatcher {
+	return &hyperMetroWatcher{
+		policy:      policy,
+		pairs:       map[string]HyperMetroPair{},
+		pausedSince: map[string]time.Time{},
+		attempts:    map[string]int{},
+		lastAttempt: map[string]time.Time{},
+		gaveUp:      map[string]bool{},
+	}
+}
+
+// observe compare pairs to last observed, and return events and HyperMetroPairs to resync.
+func (w *hyperMetroWatcher) observe(pairs []HyperMetroPair, now time.Time) ([]HyperMetroPairEvent, []HyperMetroPair) {
+	var events []HyperMetroPairEvent
+	var resync []HyperMetroPair
+
+	seen := map[string]bool{}
+	for _, pair := range pairs {
+		seen[pair.ID] = true
+		prev, known := w.pairs[pair.ID]
+		w.pairs[pair.ID] = pair
+
+		if known && prev.State() != pair.State() {
+			events = append(events, HyperMetroPairEvent{Type: EventStateChanged, Time: now, Pair: pair, PreviousState: prev.State()})
+		}
+		if !pair.IsLinkUp() && (!known || prev.IsLinkUp()) {
+			events = append(events, HyperMetroPairEvent{Type: EventLinkDown, Time: now, Pair: pair, PreviousState: prev.State()})
+		}
+		if known && pair.IsLinkUp() && !prev.IsLinkUp() {
+			events = append(events, HyperMetroPairEvent{Type: EventLinkUp, Time: now, Pair: pair, PreviousState: prev.State()})
+		}
+		if pair.IsIsolated() && (!known || !prev.IsIsolated()) {
+			events = append(events, HyperMetroPairEvent{Type: EventIsolated, Time: now, Pair: pair, PreviousState: prev.State()})
+		}
+
+		if pair.State() == HyperMetroPairStatePause {
+			if _, ok := w.pausedSince[pair.ID]; !ok {
+				w.pausedSince[pair.ID] = now
+			}
+		} else {
+			delete(w.pausedSince, pair.ID)
+		}
+		if pair.State() == HyperMetroPairStateNormal {
+			delete(w.attempts, pair.ID)
+			delete(w.lastAttempt, pair.ID)
+			delete(w.gaveUp, pair.ID)
+		}
+
+		if !w.wantsResync(pair, now) {
+			continue
+		}
+		switch {
+		case w.policy.MaxAttempts > 0 && w.attempts[pair.ID] >= w.policy.MaxAttempts:
+			if !w.gaveUp[pair.ID] {
+				w.gaveUp[pair.ID] = true
+				events = append(events, HyperMetroPairEvent{Type: EventResyncGaveUp, Time: now, Pair: pair, PreviousState: prev.State()})
+			}
+		case w.inBackoff(pair.ID, now):
+		default:
+			resync = append(resync, pair)
+		}
+	}
+
+	var removed []string
+	for id := range w.pairs {
+		if !seen[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	for _, id := range removed {
+		prev := w.pairs[id]
+		events = append(events, HyperMetroPairEvent{Type: EventPairRemoved, Time: now, Pair: prev, PreviousState: prev.State()})
+		delete(w.pairs, id)
+		delete(w.pausedSince, id)
+		delete(w.attempts, id)
+		delete(w.lastAttempt, id)
+		delete(w.gaveUp, id)
+	}
+
+	return events, resync
+}
+
+// wantsResync return true if HyperMetroPair should be resynchronized by policy, regardless of attempts.
+func (w *hyperMetroWatcher) wantsResync(pair HyperMetroPair, now time.Time) bool {
+	if !pair.IsLinkUp() {
+		// sync will fail until link is recovered
+		return false
+	}
+
+	state := pair.State()
+	switch {
+	case w.policy.hasState(state):
+	case state == HyperMetroPairStatePause && w.policy.ResyncPausedAfter > 0:
+		if now.Sub(w.pausedSince[pair.ID]) < w.policy.ResyncPausedAfter {
+			return false
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (w *hyperMetroWatcher) inBackoff(pairID string, now time.Time) bool {
+	last, ok := w.lastAttempt[pairID]
+	return ok && now.Sub(last) < w.policy.Backoff
+}
+
+func (w *hyperMetroWatcher) recordAttempt(pairID string, now time.Time) {
+	w.attempts[pairID]++
+	w.lastAttempt[pairID] = now
+}
+
+// WatchHyperMetroPairs watch HyperMetroPairs every interval with default ResyncPolicy.
+func (c *Client) WatchHyperMetroPairs(ctx context.Context, interval time.Duration) <-chan HyperMetroPairEvent {
+	return c.WatchHyperMetroPairsWithPolicy(ctx, interval, nil)
+}
+
+// WatchHyperMetroPairsWithPolicy watch HyperMetroPairs every interval, emit events and resync by policy.
+// HyperMetroPairs in consistency group are resynchronized by group.
+// DefaultWatchInterval is used if interval is not positive.
+// returned channel is closed when ctx is done, so caller must receive events until closed.
+func (c *Client) WatchHyperMetroPairsWithPolicy(ctx context.Context, interval time.Duration, policy *ResyncPolicy) <-chan HyperMetroPairEvent {
+	if policy == nil {
+		policy = NewResyncPolicy()
+	}
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	events := make(chan HyperMetroPairEvent)
+	w := newHyperMetroWatcher(*policy)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if !c.watchHyperMetroPairsOnce(ctx, w, events) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return events
+}
+
+// watchHyperMetroPairsOnce observe HyperMetroPairs and resync. return false if ctx is done.
+func (c *Client) watchHyperMetroPairsOnce(ctx context.Context, w *hyperMetroWatcher, events chan<- HyperMetroPairEvent) bool {
+	emit := func(e HyperMetroPairEvent) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case events <- e:
+			return true
+		}
+	}
+
+	now := time.Now()
+	pairs, err := c.listAllHyperMetroPairs(ctx)
+	if err != nil && err != ErrHyperMetroPairNotFound {
+		return emit(HyperMetroPairEvent{Type: EventWatchError, Time: now, Err: err})
+	}
+
+	observed, resync := w.observe(pairs, now)
+	for _, e := range observed {
+		if !emit(e) {
+			return false
+		}
+	}
+
+	syncedCG := map[string]bool{}
+	for _, pair := range resync {
+		w.recordAttempt(pair.ID, now)
+
+		if pair.ISINCG == "true" {
+			if syncedCG[pair.CGID] {
+				continue
+			}
+			syncedCG[pair.CGID] = true
+			err = c.SyncHyperMetroCG(ctx, pair.CGID)
+		} else {
+			err = c.SyncHyperMetroPair(ctx, pair.ID)
+		}
+
+		e := HyperMetroPairEvent{Type: EventResyncStarted, Time: now, Pair: pair, PreviousState: pair.State()}
+		if err != nil {
+			e.Type = EventResyncFailed
+			e.Err = err
+		}
+		if !emit(e) {
+			return false
+		}
+	}
+
+	return true
+}
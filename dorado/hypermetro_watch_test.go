@@ -0,0 +1,179 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func eventTypes(events []HyperMetroPairEvent) []HyperMetroPairEventType {
+	var types []HyperMetroPairEventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func pairIDs(pairs []HyperMetroPair) []string {
+	var ids []string
+	for _, p := range pairs {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestHyperMetroWatcher_observe(t *testing.T) {
+	now := time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+	policy := NewResyncPolicy()
+	policy.ResyncPausedAfter = 10 * time.Minute
+	policy.MaxAttempts = 2
+	w := newHyperMetroWatcher(*policy)
+
+	normal := HyperMetroPair{ID: "1", RUNNINGSTATUS: "1", LINKSTATUS: "1", ISISOLATION: "false"}
+	paused := HyperMetroPair{ID: "2", RUNNINGSTATUS: "41", LINKSTATUS: "1", ISISOLATION: "false"}
+
+	// first observation: no events, paused pair is not resynced yet
+	events, resync := w.observe([]HyperMetroPair{normal, paused}, now)
+	if len(events) != 0 || len(resync) != 0 {
+		t.Fatalf("observe return events %v, resync %v, want empty", eventTypes(events), pairIDs(resync))
+	}
+
+	// link down and state changed
+	linkDown := normal
+	linkDown.RUNNINGSTATUS = "100"
+	linkDown.LINKSTATUS = "2"
+	now = now.Add(1 * time.Minute)
+	events, resync = w.observe([]HyperMetroPair{linkDown, paused}, now)
+	if got, want := eventTypes(events), []HyperMetroPairEventType{EventStateChanged, EventLinkDown}; !reflect.DeepEqual(got, want) {
+		t.Errorf("observe return events %v, want %v", got, want)
+	}
+	if len(resync) != 0 {
+		t.Errorf("observe return resync %v, want empty (link is down)", pairIDs(resync))
+	}
+
+	// link up: ToBeSynchronized is resynced
+	toBeSynced := linkDown
+	toBeSynced.LINKSTATUS = "1"
+	now = now.Add(1 * time.Minute)
+	events, resync = w.observe([]HyperMetroPair{toBeSynced, paused}, now)
+	if got, want := eventTypes(events), []HyperMetroPairEventType{EventLinkUp}; !reflect.DeepEqual(got, want) {
+		t.Errorf("observe return events %v, want %v", got, want)
+	}
+	if got, want := pairIDs(resync), []string{"1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("observe return resync %v, want %v", got, want)
+	}
+	w.recordAttempt("1", now)
+
+	// backoff
+	now = now.Add(30 * time.Second)
+	_, resync = w.observe([]HyperMetroPair{toBeSynced, paused}, now)
+	if len(resync) != 0 {
+		t.Errorf("observe return resync %v, want empty (backoff)", pairIDs(resync))
+	}
+
+	// after ResyncPausedAfter, paused pair is resynced
+	now = now.Add(10 * time.Minute)
+	_, resync = w.observe([]HyperMetroPair{toBeSynced, paused}, now)
+	if got, want := pairIDs(resync), []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("observe return resync %v, want %v", got, want)
+	}
+	w.recordAttempt("1", now)
+	w.recordAttempt("2", now)
+
+	// max attempts: ResyncGaveUp is emitted once
+	now = now.Add(10 * time.Minute)
+	events, resync = w.observe([]HyperMetroPair{toBeSynced, paused}, now)
+	if got, want := pairIDs(resync), []string{"2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("observe return resync %v, want %v", got, want)
+	}
+	if got, want := eventTypes(events), []HyperMetroPairEventType{EventResyncGaveUp}; !reflect.DeepEqual(got, want) {
+		t.Errorf("observe return events %v, want %v", got, want)
+	}
+	w.recordAttempt("2", now)
+	now = now.Add(10 * time.Minute)
+	events, _ = w.observe([]HyperMetroPair{toBeSynced, paused}, now)
+	if got, want := eventTypes(events), []HyperMetroPairEventType{EventResyncGaveUp}; !reflect.DeepEqual(got, want) {
+		t.Errorf("observe return events %v, want %v (only pair 2)", got, want)
+	} else if events[0].Pair.ID != "2" {
+		t.Errorf("ResyncGaveUp is emitted for pair %s, want %s", events[0].Pair.ID, "2")
+	}
+
+	// isolated and removed
+	isolated := toBeSynced
+	isolated.ISISOLATION = "true"
+	events, _ = w.observe([]HyperMetroPair{isolated}, now)
+	if got, want := eventTypes(events), []HyperMetroPairEventType{EventIsolated, EventPairRemoved}; !reflect.DeepEqual(got, want) {
+		t.Errorf("observe return events %v, want %v", got, want)
+	}
+}
+
+func TestClient_WatchHyperMetroPairs(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	// first page is full, pairs in consistency group are in second page
+	var firstPage []string
+	for i := 0; i < hyperMetroPairsPageSize; i++ {
+		firstPage = append(firstPage, fmt.Sprintf(`{"ID": "%d", "RUNNINGSTATUS": "1", "LINKSTATUS": "1", "ISISOLATION": "false", "ISINCG": "false"}`, i))
+	}
+	secondPage := []string{
+		`{"ID": "a", "RUNNINGSTATUS": "100", "LINKSTATUS": "1", "ISISOLATION": "false", "ISINCG": "true", "CGID": "7"}`,
+		`{"ID": "b", "RUNNINGSTATUS": "100", "LINKSTATUS": "1", "ISISOLATION": "false", "ISINCG": "true", "CGID": "7"}`,
+	}
+	mux.HandleFunc("/HyperMetroPair", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		var pairs []string
+		switch r.URL.Query().Get("range") {
+		case "[0-4095]":
+			pairs = firstPage
+		case "[4095-8190]":
+			pairs = secondPage
+		default:
+			t.Errorf("range is %s, want [0-4095] or [4095-8190]", r.URL.Query().Get("range"))
+		}
+		fmt.Fprintf(w, `{"data": [%s], "error": {"code": 0, "description": "0"}}`, strings.Join(pairs, ","))
+	})
+	synced := 0
+	mux.HandleFunc("/HyperMetro_ConsistentGroup/sync", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testBody(t, r, map[string]interface{}{"ID": "7", "TYPE": "15364"})
+		synced++
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/HyperMetroPair/synchronize_hcpair", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("HyperMetroPair in consistency group must not be synchronized alone")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := client.WatchHyperMetroPairs(ctx, 1*time.Hour)
+
+	select {
+	case e := <-events:
+		if e.Type != EventResyncStarted || e.Pair.CGID != "7" {
+			t.Errorf("WatchHyperMetroPairs emit %+v, want %s of consistency group", e, EventResyncStarted)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("WatchHyperMetroPairs does not emit event")
+	}
+
+	cancel()
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				if synced != 1 {
+					t.Errorf("consistency group is synchronized %d times, want 1", synced)
+				}
+				return
+			}
+			t.Errorf("WatchHyperMetroPairs emit unexpected event %+v", e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("channel is not closed after ctx is canceled")
+		}
+	}
+}
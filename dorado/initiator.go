@@ -3,8 +3,10 @@ package dorado
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -24,6 +26,8 @@ type Initiator struct {
 	SPECIALMODETYPE string `json:"SPECIALMODETYPE"`
 	TYPE            int    `json:"TYPE"`
 	USECHAP         string `json:"USECHAP"`
+	CHAPNAME        string `json:"CHAPNAME,omitempty"`
+	ISMUTUALCHAP    string `json:"ISMUTUALCHAP,omitempty"`
 	PARENTID        string `json:"PARENTID,omitempty"`
 	PARENTNAME      string `json:"PARENTNAME,omitempty"`
 	PARENTTYPE      int    `json:"PARENTTYPE,omitempty"`
@@ -77,16 +81,24 @@ func (d *Device) GetInitiator(ctx context.Context, iqn string) (*Initiator, erro
 
 // CreateInitiator create initiator.
 func (d *Device) CreateInitiator(ctx context.Context, iqn string) (*Initiator, error) {
+	return d.CreateInitiatorWithCHAP(ctx, iqn, nil)
+}
+
+// CreateInitiatorWithCHAP create initiator that enabled CHAP authentication.
+// CHAP is disabled if chap is nil.
+func (d *Device) CreateInitiatorWithCHAP(ctx context.Context, iqn string, chap *CHAPParam) (*Initiator, error) {
 	spath := "/iscsi_initiator"
-	param := struct {
-		USECHAP string `json:"USECHAP"`
-		TYPE    string `json:"TYPE"`
-		ID      string `json:"ID"`
-	}{
-		USECHAP: "false",
-		TYPE:    strconv.Itoa(TypeInitiator),
-		ID:      iqn,
+	if chap != nil {
+		if err := chap.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid CHAP parameter: %w", err)
+		}
 	}
+
+	param := UpdateInitiatorParam{
+		TYPE: strconv.Itoa(TypeInitiator),
+		ID:   iqn,
+	}
+	param.SetCHAP(chap)
 	jb, err := json.Marshal(param)
 	if err != nil {
 		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
@@ -125,10 +137,41 @@ func (d *Device) DeleteInitiator(ctx context.Context, iqn string) error {
 // UpdateInitiatorParam is parameter for UpdateInitiator
 type UpdateInitiatorParam struct {
 	USECHAP    string `json:"USECHAP"`
-	PARENTTYPE string `json:"PARENTTYPE"`
+	PARENTTYPE string `json:"PARENTTYPE,omitempty"`
 	TYPE       string `json:"TYPE"`
 	ID         string `json:"ID"`
-	PARENTID   string `json:"PARENTID"`
+	PARENTID   string `json:"PARENTID,omitempty"`
+
+	CHAPNAME           string `json:"CHAPNAME,omitempty"`
+	CHAPPASSWORD       string `json:"CHAPPASSWORD,omitempty"`
+	ISMUTUALCHAP       string `json:"ISMUTUALCHAP,omitempty"`
+	MUTUALCHAPNAME     string `json:"MUTUALCHAPNAME,omitempty"`
+	MUTUALCHAPPASSWORD string `json:"MUTUALCHAPPASSWORD,omitempty"`
+}
+
+// SetCHAP set CHAP parameters. disable CHAP if chap is nil.
+func (p *UpdateInitiatorParam) SetCHAP(chap *CHAPParam) {
+	p.CHAPNAME = ""
+	p.CHAPPASSWORD = ""
+	p.ISMUTUALCHAP = ""
+	p.MUTUALCHAPNAME = ""
+	p.MUTUALCHAPPASSWORD = ""
+
+	if chap == nil {
+		p.USECHAP = "false"
+		return
+	}
+
+	p.USECHAP = "true"
+	p.CHAPNAME = chap.Name
+	p.CHAPPASSWORD = chap.Password
+	p.ISMUTUALCHAP = "false"
+	if chap.IsMutual() {
+		// target-side credential, initiator authenticate the target by this.
+		p.ISMUTUALCHAP = "true"
+		p.MUTUALCHAPNAME = chap.MutualName
+		p.MUTUALCHAPPASSWORD = chap.MutualPassword
+	}
 }
 
 // UpdateInitiator update initiator information.
@@ -153,6 +196,40 @@ func (d *Device) UpdateInitiator(ctx context.Context, iqn string, initiatorParam
 	return initiator, nil
 }
 
+// SetInitiatorCHAP enable CHAP authentication of initiator.
+// one-way CHAP if chap.MutualName is empty, mutual CHAP otherwise.
+// CHAP is disabled if chap is nil.
+func (d *Device) SetInitiatorCHAP(ctx context.Context, iqn string, chap *CHAPParam) (*Initiator, error) {
+	if chap != nil {
+		if err := chap.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid CHAP parameter: %w", err)
+		}
+	}
+
+	initiator, err := d.GetInitiator(ctx, iqn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get initiator: %w", err)
+	}
+
+	param := UpdateInitiatorParam{
+		TYPE:       strconv.Itoa(TypeInitiator),
+		ID:         iqn,
+		PARENTID:   initiator.PARENTID,
+		PARENTTYPE: parentTypeString(initiator.PARENTTYPE),
+	}
+	param.SetCHAP(chap)
+
+	return d.UpdateInitiator(ctx, iqn, param)
+}
+
+func parentTypeString(parentType int) string {
+	if parentType == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(parentType)
+}
+
 // GetInitiatorForce get initiator and create initiator if not exists.
 func (d *Device) GetInitiatorForce(ctx context.Context, iqn string) (*Initiator, error) {
 	initiators, err := d.GetInitiators(ctx, NewSearchQueryID(encodeIqn(iqn)))
@@ -170,3 +247,139 @@ func (d *Device) GetInitiatorForce(ctx context.Context, iqn string) (*Initiator,
 
 	return &initiators[0], nil
 }
+
+// CHAP constraints of Dorado
+const (
+	MinCHAPNameLength     = 4
+	MaxCHAPNameLength     = 223
+	MinCHAPPasswordLength = 12
+	MaxCHAPPasswordLength = 16
+)
+
+// CHAPParam is credential of iSCSI CHAP authentication.
+// MutualName and MutualPassword is credential of target side (for mutual CHAP).
+type CHAPParam struct {
+	Name     string
+	Password string
+
+	MutualName     string
+	MutualPassword string
+}
+
+// IsMutual return true if target side credential is set.
+func (c *CHAPParam) IsMutual() bool {
+	return c.MutualName != "" || c.MutualPassword != ""
+}
+
+// Validate check length of CHAP credential.
+func (c *CHAPParam) Validate() error {
+	if err := validateCHAPCredential(c.Name, c.Password); err != nil {
+		return err
+	}
+
+	if c.IsMutual() {
+		if err := validateCHAPCredential(c.MutualName, c.MutualPassword); err != nil {
+			return fmt.Errorf("invalid mutual CHAP credential: %w", err)
+		}
+		if c.Password == c.MutualPassword {
+			return errors.New("mutual CHAP password must be different from CHAP password")
+		}
+	}
+
+	return nil
+}
+
+func validateCHAPCredential(name, password string) error {
+	if len(name) < MinCHAPNameLength || len(name) > MaxCHAPNameLength {
+		return fmt.Errorf("length of CHAP name must be %d - %d", MinCHAPNameLength, MaxCHAPNameLength)
+	}
+	if len(password) < MinCHAPPasswordLength || len(password) > MaxCHAPPasswordLength {
+		return fmt.Errorf("length of CHAP password must be %d - %d", MinCHAPPasswordLength, MaxCHAPPasswordLength)
+	}
+
+	return nil
+}
+
+// NewCHAPParam generate CHAP credential for hostname.
+// set target side credential if isMutual is true.
+func NewCHAPParam(hostname string, isMutual bool) (*CHAPParam, error) {
+	password, err := generateCHAPPassword()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CHAP password: %w", err)
+	}
+
+	chap := &CHAPParam{
+		Name:     encodeCHAPName(hostname),
+		Password: password,
+	}
+
+	if isMutual {
+		mutualPassword, err := generateCHAPPassword()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate mutual CHAP password: %w", err)
+		}
+
+		chap.MutualName = encodeCHAPName("target-" + hostname)
+		chap.MutualPassword = mutualPassword
+	}
+
+	return chap, nil
+}
+
+func encodeCHAPName(hostname string) string {
+	name := encodeHostName(hostname)
+	for len(name) < MinCHAPNameLength {
+		name = name + "_"
+	}
+
+	return name
+}
+
+var (
+	chapPasswordCharsets = []string{
+		"abcdefghijklmnopqrstuvwxyz",
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+		"0123456789",
+		"!@#%^*_+=",
+	}
+)
+
+// generateCHAPPassword generate random password that include all of chapPasswordCharsets.
+func generateCHAPPassword() (string, error) {
+	all := strings.Join(chapPasswordCharsets, "")
+
+	password := make([]byte, MaxCHAPPasswordLength)
+	for i := range password {
+		charset := all
+		if i < len(chapPasswordCharsets) {
+			charset = chapPasswordCharsets[i]
+		}
+
+		c, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		password[i] = c
+	}
+
+	// shuffle to not fix position of each charset
+	for i := len(password) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random number: %w", err)
+		}
+		j := int(n.Int64())
+		password[i], password[j] = password[j], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate random number: %w", err)
+	}
+
+	return charset[n.Int64()], nil
+}
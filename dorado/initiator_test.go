@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -70,3 +71,129 @@ func TestDevice_GetInitiators(t *testing.T) {
 		t.Errorf("GetInitiators return %+v, want %+v", initiators, want)
 	}
 }
+
+func TestGenerateCHAPPassword(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		password, err := generateCHAPPassword()
+		if err != nil {
+			t.Fatalf("generateCHAPPassword return err: %s", err)
+		}
+
+		if len(password) != MaxCHAPPasswordLength {
+			t.Errorf("generateCHAPPassword return length %d, want %d", len(password), MaxCHAPPasswordLength)
+		}
+		for _, charset := range chapPasswordCharsets {
+			if !strings.ContainsAny(password, charset) {
+				t.Errorf("generateCHAPPassword return %s, must include any of %s", password, charset)
+			}
+		}
+	}
+}
+
+func TestNewCHAPParam(t *testing.T) {
+	chap, err := NewCHAPParam("hv", true)
+	if err != nil {
+		t.Fatalf("NewCHAPParam return err: %s", err)
+	}
+
+	if err := chap.Validate(); err != nil {
+		t.Errorf("NewCHAPParam return invalid parameter: %s", err)
+	}
+	if chap.Name != "hv__" {
+		t.Errorf("NewCHAPParam return name %s, want %s", chap.Name, "hv__")
+	}
+	if !chap.IsMutual() {
+		t.Errorf("NewCHAPParam must return mutual CHAP parameter")
+	}
+}
+
+func TestDevice_CreateInitiatorWithCHAP(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/iscsi_initiator", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, map[string]interface{}{
+			"USECHAP":      "true",
+			"TYPE":         "222",
+			"ID":           "iqn.1993-08.org.debian:01:test",
+			"CHAPNAME":     "host001",
+			"CHAPPASSWORD": "Passw0rd!abc",
+			"ISMUTUALCHAP": "false",
+		})
+
+		fmt.Fprint(w, `{"data": {"ID": "iqn.1993-08.org.debian:01:test", "USECHAP": "true", "CHAPNAME": "host001", "ISMUTUALCHAP": "false", "TYPE": 222}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	chap := &CHAPParam{Name: "host001", Password: "Passw0rd!abc"}
+	initiator, err := client.LocalDevice.CreateInitiatorWithCHAP(context.Background(), "iqn.1993-08.org.debian:01:test", chap)
+	if err != nil {
+		t.Fatalf("CreateInitiatorWithCHAP return err: %s", err)
+	}
+	if initiator.USECHAP != "true" || initiator.CHAPNAME != "host001" {
+		t.Errorf("CreateInitiatorWithCHAP return %+v", initiator)
+	}
+}
+
+func TestDevice_SetInitiatorCHAP(t *testing.T) {
+	tests := []struct {
+		name    string
+		chap    *CHAPParam
+		wantPut map[string]interface{}
+	}{
+		{
+			name: "mutual CHAP",
+			chap: &CHAPParam{
+				Name:           "host001",
+				Password:       "Passw0rd!abc",
+				MutualName:     "target-host001",
+				MutualPassword: "Passw0rd!xyz",
+			},
+			wantPut: map[string]interface{}{
+				"USECHAP":            "true",
+				"PARENTTYPE":         "21",
+				"TYPE":               "222",
+				"ID":                 "iqn.1993-08.org.debian:01:test",
+				"PARENTID":           "1",
+				"CHAPNAME":           "host001",
+				"CHAPPASSWORD":       "Passw0rd!abc",
+				"ISMUTUALCHAP":       "true",
+				"MUTUALCHAPNAME":     "target-host001",
+				"MUTUALCHAPPASSWORD": "Passw0rd!xyz",
+			},
+		},
+		{
+			name: "disable CHAP",
+			chap: nil,
+			wantPut: map[string]interface{}{
+				"USECHAP":    "false",
+				"PARENTTYPE": "21",
+				"TYPE":       "222",
+				"ID":         "iqn.1993-08.org.debian:01:test",
+				"PARENTID":   "1",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			mux.HandleFunc("/iscsi_initiator/iqn.1993-08.org.debian:01:test", func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+				case "PUT":
+					testBody(t, r, test.wantPut)
+				default:
+					t.Errorf("Request method: %v, want GET or PUT", r.Method)
+				}
+				fmt.Fprint(w, `{"data": {"ID": "iqn.1993-08.org.debian:01:test", "PARENTID": "1", "PARENTTYPE": 21, "TYPE": 222}, "error": {"code": 0, "description": "0"}}`)
+			})
+
+			if _, err := client.LocalDevice.SetInitiatorCHAP(context.Background(), "iqn.1993-08.org.debian:01:test", test.chap); err != nil {
+				t.Fatalf("SetInitiatorCHAP return err: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package dorado
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LineageNodeType is type of object in lineage tree
+type LineageNodeType string
+
+// LineageNodeType const
+const (
+	LineageNodeLUN      LineageNodeType = "LUN"
+	LineageNodeSnapshot LineageNodeType = "snapshot"
+)
+
+// LineageNode is node of lineage tree.
+// children of LUN are snapshots and clone LUNs,
+// children of snapshot are cascaded snapshots, clone LUNs from snapshot and target LUNs of LUN copy from snapshot.
+// a LUN can appear in some subtrees (ex: target LUN of LUN copies from some snapshots).
+type LineageNode struct {
+	Type     LineageNodeType
+	ID       int
+	Name     string
+	Children []LineageNode
+}
+
+// HasDependents return true if node has children.
+// delete a node that has dependents will break children.
+func (n *LineageNode) HasDependents() bool {
+	return len(n.Children) != 0
+}
+
+// Dependents return all descendants of node.
+func (n *LineageNode) Dependents() []LineageNode {
+	var dependents []LineageNode
+	for _, child := range n.Children {
+		dependents = append(dependents, child)
+		dependents = append(dependents, child.Dependents()...)
+	}
+
+	return dependents
+}
+
+// String is function compatible for fmt.Stringer
+func (n LineageNode) String() string {
+	return fmt.Sprintf("%s(ID: %d, NAME: %s)", n.Type, n.ID, n.Name)
+}
+
+// parseIDList parse ID list (ex: CLONEIDS, SNAPSHOTIDS).
+// Dorado return JSON array in string (ex: "[\"1\",\"2\"]") or comma separated values.
+func parseIDList(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "[]" {
+		return nil, nil
+	}
+
+	var values []string
+	if strings.HasPrefix(s, "[") {
+		if err := json.Unmarshal([]byte(s), &values); err != nil {
+			return nil, fmt.Errorf("failed to parse ID list (%s): %w", s, err)
+		}
+	} else {
+		values = strings.Split(s, ",")
+	}
+
+	var ids []int
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ID (%s): %w", v, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// GetLUNLineage get lineage tree that root is LUN.
+// LUN -> snapshots (-> cascaded snapshots and LUN copy targets) and clone LUNs (-> their snapshots and clones).
+func (d *Device) GetLUNLineage(ctx context.Context, lunID int) (*LineageNode, error) {
+	return d.getLUNLineage(ctx, lunID, map[int]bool{})
+}
+
+// getLUNLineage get lineage tree of LUN.
+// path is LUNs from root to this LUN, it is used to detect circular reference.
+func (d *Device) getLUNLineage(ctx context.Context, lunID int, path map[int]bool) (*LineageNode, error) {
+	if path[lunID] {
+		return nil, fmt.Errorf("found circular reference of LUN (ID: %d)", lunID)
+	}
+	path[lunID] = true
+	defer delete(path, lunID)
+
+	lun, err := d.GetLUN(ctx, lunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LUN (ID: %d): %w", lunID, err)
+	}
+
+	node := &LineageNode{
+		Type: LineageNodeLUN,
+		ID:   lun.ID,
+		Name: lun.NAME,
+	}
+
+	snapshots, err := d.ListSnapshotsForLUN(ctx, lun.ID)
+	if err != nil && err != ErrSnapshotNotFound {
+		return nil, fmt.Errorf("failed to get snapshots of LUN (ID: %d): %w", lun.ID, err)
+	}
+	for _, snapshot := range snapshots {
+		child, err := d.getSnapshotLineage(ctx, snapshot, path)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, *child)
+	}
+
+	cloneIDs, err := parseIDList(lun.CLONEIDS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CLONEIDS of LUN (ID: %d): %w", lun.ID, err)
+	}
+	for _, cloneID := range cloneIDs {
+		child, err := d.getLUNLineage(ctx, cloneID, path)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, *child)
+	}
+
+	return node, nil
+}
+
+func (d *Device) getSnapshotLineage(ctx context.Context, snapshot Snapshot, path map[int]bool) (*LineageNode, error) {
+	node := &LineageNode{
+		Type: LineageNodeSnapshot,
+		ID:   snapshot.ID,
+		Name: snapshot.NAME,
+	}
+
+	cascaded, err := d.listChildSnapshots(ctx, snapshot.ID, TypeSnapshot)
+	if err != nil && err != ErrSnapshotNotFound {
+		return nil, fmt.Errorf("failed to get cascaded snapshots of snapshot (ID: %d): %w", snapshot.ID, err)
+	}
+	for _, c := range cascaded {
+		child, err := d.getSnapshotLineage(ctx, c, path)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, *child)
+	}
+
+	clones, err := d.listSnapshotClones(ctx, snapshot.ID)
+	if err != nil && err != ErrLunNotFound {
+		return nil, fmt.Errorf("failed to get clone LUNs of snapshot (ID: %d): %w", snapshot.ID, err)
+	}
+	for _, clone := range clones {
+		child, err := d.getLUNLineage(ctx, clone.ID, path)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, *child)
+	}
+
+	lunCopyIDs, err := parseIDList(snapshot.HYPERCOPYIDS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HYPERCOPYIDS of snapshot (ID: %d): %w", snapshot.ID, err)
+	}
+	for _, lunCopyID := range lunCopyIDs {
+		lunCopy, err := d.GetLUNCopy(ctx, lunCopyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get LUN copy (ID: %d): %w", lunCopyID, err)
+		}
+		targetIDs, err := parseLUNCopyLocalLUNIDs(lunCopy.TARGETLUN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TARGETLUN of LUN copy (ID: %d): %w", lunCopyID, err)
+		}
+		for _, targetID := range targetIDs {
+			child, err := d.getLUNLineage(ctx, targetID, path)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, *child)
+		}
+	}
+
+	return node, nil
+}
+
+// listSnapshotClones get clone LUNs that created from snapshot.
+// LUN that finished split is not a clone of snapshot.
+func (d *Device) listSnapshotClones(ctx context.Context, snapshotID int) ([]LUN, error) {
+	query := &SearchQuery{
+		Filter: ToFilter("CLONESOURCEID", strconv.Itoa(snapshotID)),
+	}
+	luns, err := d.GetLUNs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// CLONESOURCEID is same value in LUN and snapshot
+	var clones []LUN
+	for _, lun := range luns {
+		if lun.ISCLONE && lun.CLONESOURCETYPE == strconv.Itoa(TypeSnapshot) {
+			clones = append(clones, lun)
+		}
+	}
+
+	if len(clones) == 0 {
+		return nil, ErrLunNotFound
+	}
+
+	return clones, nil
+}
+
+// CheckLUNDeletable return ErrHasDependents if LUN has snapshots or clone LUNs.
+func (d *Device) CheckLUNDeletable(ctx context.Context, lunID int) error {
+	lineage, err := d.GetLUNLineage(ctx, lunID)
+	if err != nil {
+		return fmt.Errorf("failed to get lineage of LUN: %w", err)
+	}
+
+	if lineage.HasDependents() {
+		return fmt.Errorf("%w: %v", ErrHasDependents, lineage.Dependents())
+	}
+
+	return nil
+}
+
+// CheckSnapshotDeletable return ErrHasDependents if snapshot has cascaded snapshots, clone LUNs or LUN copies.
+func (d *Device) CheckSnapshotDeletable(ctx context.Context, snapshotID int) error {
+	snapshot, err := d.GetSnapshot(ctx, snapshotID)
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	lineage, err := d.getSnapshotLineage(ctx, *snapshot, map[int]bool{})
+	if err != nil {
+		return fmt.Errorf("failed to get lineage of snapshot: %w", err)
+	}
+
+	if lineage.HasDependents() {
+		return fmt.Errorf("%w: %v", ErrHasDependents, lineage.Dependents())
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package dorado
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseIDList(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []int
+	}{
+		{input: "", want: nil},
+		{input: "[]", want: nil},
+		{input: `["12","15"]`, want: []int{12, 15}},
+		{input: "12,15", want: []int{12, 15}},
+	}
+
+	for _, test := range tests {
+		got, err := parseIDList(test.input)
+		if err != nil {
+			t.Fatalf("parseIDList(%s) return err: %s", test.input, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseIDList(%s) return %v, want %v", test.input, got, test.want)
+		}
+	}
+
+	if _, err := parseIDList("[invalid"); err == nil {
+		t.Errorf("parseIDList must return err")
+	}
+}
+
+func TestDevice_GetLUNLineage(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/lun/7", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "7", "NAME": "source", "CLONEIDS": "[\"9\"]"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/9", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "9", "NAME": "clone", "CLONEIDS": ""}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/21", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "21", "NAME": "copy", "CLONEIDS": ""}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/25", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "25", "NAME": "snapclone", "CLONEIDS": "", "CLONESOURCEID": "15", "CLONESOURCETYPE": "27", "ISCLONE": "true"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("filter") {
+		case "CLONESOURCEID::15":
+			fmt.Fprint(w, `{"data": [{"ID": "25", "NAME": "snapclone", "CLONEIDS": "", "CLONESOURCEID": "15", "CLONESOURCETYPE": "27", "ISCLONE": "true"}], "error": {"code": 0, "description": "0"}}`)
+		case "CLONESOURCEID::12":
+			// clone of LUN (ID: 12) and split clone are not a child of snapshot (ID: 12)
+			fmt.Fprint(w, `{"data": [{"ID": "30", "NAME": "lunclone", "CLONESOURCEID": "12", "CLONESOURCETYPE": "11", "ISCLONE": "true"}, {"ID": "31", "NAME": "split", "CLONESOURCEID": "12", "CLONESOURCETYPE": "27", "ISCLONE": "false"}], "error": {"code": 0, "description": "0"}}`)
+		default:
+			fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+		}
+	})
+	mux.HandleFunc("/luncopy/3", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		// target LUN in remote device is not a child
+		fmt.Fprint(w, `{"data": {"ID": "3", "SOURCELUN": "INVALID;12;INVALID;INVALID;INVALID", "TARGETLUN": "INVALID;21;INVALID;INVALID;INVALID,0;30;2100e0cc7b000000;6a400e210055e22650d557a000000015;INVALID"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("filter") {
+		case "PARENTID::7":
+			fmt.Fprint(w, `{"data": [{"ID": "12", "NAME": "snap", "PARENTID": "7", "PARENTTYPE": 11, "HYPERCOPYIDS": "[\"3\"]"}], "error": {"code": 0, "description": "0"}}`)
+		case "PARENTID::12":
+			// LUN (ID: 21) is target of LUN copy from both snapshot (ID: 12) and snapshot (ID: 15)
+			fmt.Fprint(w, `{"data": [{"ID": "15", "NAME": "cascaded", "PARENTID": "12", "PARENTTYPE": 27, "HYPERCOPYIDS": "[\"3\"]"}], "error": {"code": 0, "description": "0"}}`)
+		case "PARENTID::9":
+			// snapshot of snapshot (ID: 9) is not a child of LUN (ID: 9)
+			fmt.Fprint(w, `{"data": [{"ID": "20", "NAME": "other", "PARENTID": "9", "PARENTTYPE": 27}], "error": {"code": 0, "description": "0"}}`)
+		default:
+			fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+		}
+	})
+
+	lineage, err := client.LocalDevice.GetLUNLineage(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetLUNLineage return err: %s", err)
+	}
+
+	want := &LineageNode{
+		Type: LineageNodeLUN,
+		ID:   7,
+		Name: "source",
+		Children: []LineageNode{
+			{
+				Type: LineageNodeSnapshot,
+				ID:   12,
+				Name: "snap",
+				Children: []LineageNode{
+					{
+						Type: LineageNodeSnapshot,
+						ID:   15,
+						Name: "cascaded",
+						Children: []LineageNode{
+							{Type: LineageNodeLUN, ID: 25, Name: "snapclone"},
+							{Type: LineageNodeLUN, ID: 21, Name: "copy"},
+						},
+					},
+					{Type: LineageNodeLUN, ID: 21, Name: "copy"},
+				},
+			},
+			{Type: LineageNodeLUN, ID: 9, Name: "clone"},
+		},
+	}
+	if !reflect.DeepEqual(lineage, want) {
+		t.Errorf("GetLUNLineage return %+v, want %+v", lineage, want)
+	}
+	if len(lineage.Dependents()) != 6 {
+		t.Errorf("Dependents return %d nodes, want %d", len(lineage.Dependents()), 6)
+	}
+
+	if err := client.LocalDevice.CheckLUNDeletable(context.Background(), 7); !errors.Is(err, ErrHasDependents) {
+		t.Errorf("CheckLUNDeletable return %v, want %v", err, ErrHasDependents)
+	}
+	if err := client.LocalDevice.CheckLUNDeletable(context.Background(), 9); err != nil {
+		t.Errorf("CheckLUNDeletable return err: %s", err)
+	}
+}
+
+func TestDevice_CheckSnapshotDeletable_Clone(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/snapshot/15", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "15", "NAME": "snap", "PARENTID": "7", "PARENTTYPE": 11}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("filter") != "CLONESOURCEID::15" {
+			t.Errorf("filter is %s, want CLONESOURCEID::15", r.URL.Query().Get("filter"))
+		}
+		fmt.Fprint(w, `{"data": [{"ID": "25", "NAME": "snapclone", "CLONESOURCEID": "15", "CLONESOURCETYPE": "27", "ISCLONE": "true"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/25", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "25", "NAME": "snapclone", "CLONEIDS": "", "CLONESOURCEID": "15", "CLONESOURCETYPE": "27", "ISCLONE": "true"}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	if err := client.LocalDevice.CheckSnapshotDeletable(context.Background(), 15); !errors.Is(err, ErrHasDependents) {
+		t.Errorf("CheckSnapshotDeletable return %v, want %v", err, ErrHasDependents)
+	}
+}
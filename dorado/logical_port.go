@@ -0,0 +1,113 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// LogicalPort is logical interface (LIF) that serves iSCSI (or other protocols) on a port.
+// a logical port is homed on ethernet port, bond port or VLAN, and may fail over to other port.
+type LogicalPort struct {
+	ADDRESSFAMILY       string `json:"ADDRESSFAMILY"`
+	CURRENTCONTROLLERID string `json:"CURRENTCONTROLLERID"`
+	CURRENTPORTID       string `json:"CURRENTPORTID"`
+	CURRENTPORTNAME     string `json:"CURRENTPORTNAME"`
+	CURRENTPORTTYPE     int    `json:"CURRENTPORTTYPE,string"`
+	HOMECONTROLLERID    string `json:"HOMECONTROLLERID"`
+	HOMEPORTID          string `json:"HOMEPORTID"`
+	HOMEPORTNAME        string `json:"HOMEPORTNAME"`
+	HOMEPORTTYPE        int    `json:"HOMEPORTTYPE,string"`
+	ID                  string `json:"ID"`
+	IPV4ADDR            string `json:"IPV4ADDR"`
+	IPV4GATEWAY         string `json:"IPV4GATEWAY"`
+	IPV4MASK            string `json:"IPV4MASK"`
+	IPV6ADDR            string `json:"IPV6ADDR"`
+	IPV6GATEWAY         string `json:"IPV6GATEWAY"`
+	IPV6MASK            string `json:"IPV6MASK"`
+	NAME                string `json:"NAME"`
+	OPERATIONALSTATUS   string `json:"OPERATIONALSTATUS"`
+	ROLE                string `json:"ROLE"`
+	RUNNINGSTATUS       string `json:"RUNNINGSTATUS"`
+	SUPPORTPROTOCOL     string `json:"SUPPORTPROTOCOL"`
+	TYPE                int    `json:"TYPE"`
+}
+
+// ROLE values of logical port
+const (
+	LogicalPortRoleManagement        = "1"
+	LogicalPortRoleService           = "2"
+	LogicalPortRoleManagementService = "3"
+)
+
+// LogicalPortProtocolISCSI is bit of iSCSI in SUPPORTPROTOCOL of logical port
+const LogicalPortProtocolISCSI = 64
+
+// IsService return true if logical port is service port that serve protocol and link up.
+// protocol is bit of SUPPORTPROTOCOL (ex: LogicalPortProtocolISCSI), 0 means any protocol.
+// empty ROLE or SUPPORTPROTOCOL (ex: old firmware) is regarded as service port of protocol.
+func (lp *LogicalPort) IsService(protocol int) bool {
+	switch lp.ROLE {
+	case "", LogicalPortRoleService, LogicalPortRoleManagementService:
+	default:
+		return false
+	}
+
+	if protocol != 0 && lp.SUPPORTPROTOCOL != "" {
+		supported, err := strconv.Atoi(lp.SUPPORTPROTOCOL)
+		if err != nil || supported&protocol == 0 {
+			return false
+		}
+	}
+
+	return lp.RUNNINGSTATUS == strconv.Itoa(StatusLinkUp)
+}
+
+// GetLogicalPorts get logical ports by query
+func (d *Device) GetLogicalPorts(ctx context.Context, query *SearchQuery) ([]LogicalPort, error) {
+	spath := "/lif"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var logicalPorts []LogicalPort
+	if err = d.requestWithRetry(req, &logicalPorts, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(logicalPorts) == 0 {
+		return nil, ErrLogicalPortNotFound
+	}
+
+	return logicalPorts, nil
+}
+
+// GetLogicalPort get logical port by id
+func (d *Device) GetLogicalPort(ctx context.Context, logicalPortID string) (*LogicalPort, error) {
+	spath := fmt.Sprintf("/lif/%s", logicalPortID)
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	logicalPort := &LogicalPort{}
+	if err = d.requestWithRetry(req, logicalPort, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return logicalPort, nil
+}
+
+// PortID return ID and type of port that logical port is running now.
+// return home port if current port is unknown.
+func (lp *LogicalPort) PortID() (string, int) {
+	if lp.CURRENTPORTID != "" {
+		return lp.CURRENTPORTID, lp.CURRENTPORTTYPE
+	}
+
+	return lp.HOMEPORTID, lp.HOMEPORTTYPE
+}
@@ -0,0 +1,85 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDevice_GetLogicalPorts(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/lif", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w,
+			`
+{
+  "data": [
+    {
+      "ADDRESSFAMILY": "0",
+      "CURRENTCONTROLLERID": "0A",
+      "CURRENTPORTID": "131328",
+      "CURRENTPORTNAME": "CTE0.A.IOM1.P0",
+      "CURRENTPORTTYPE": "213",
+      "HOMECONTROLLERID": "0A",
+      "HOMEPORTID": "131328",
+      "HOMEPORTNAME": "CTE0.A.IOM1.P0",
+      "HOMEPORTTYPE": "213",
+      "ID": "0",
+      "IPV4ADDR": "192.0.2.10",
+      "IPV4GATEWAY": "",
+      "IPV4MASK": "255.255.255.0",
+      "IPV6ADDR": "",
+      "IPV6GATEWAY": "",
+      "IPV6MASK": "0",
+      "NAME": "iscsi_lif_0",
+      "OPERATIONALSTATUS": "true",
+      "ROLE": "2",
+      "RUNNINGSTATUS": "10",
+      "SUPPORTPROTOCOL": "64",
+      "TYPE": 279
+    }
+  ],
+  "error": {
+    "code": 0,
+    "description": "0"
+  }
+}`)
+	})
+
+	logicalPorts, err := client.LocalDevice.GetLogicalPorts(context.Background(), nil)
+	if err != nil {
+		t.Errorf("GetLogicalPorts return err: %s", err)
+	}
+
+	want := []LogicalPort{
+		{
+			ADDRESSFAMILY:       "0",
+			CURRENTCONTROLLERID: "0A",
+			CURRENTPORTID:       "131328",
+			CURRENTPORTNAME:     "CTE0.A.IOM1.P0",
+			CURRENTPORTTYPE:     TypeEthernetPort,
+			HOMECONTROLLERID:    "0A",
+			HOMEPORTID:          "131328",
+			HOMEPORTNAME:        "CTE0.A.IOM1.P0",
+			HOMEPORTTYPE:        TypeEthernetPort,
+			ID:                  "0",
+			IPV4ADDR:            "192.0.2.10",
+			IPV4MASK:            "255.255.255.0",
+			IPV6MASK:            "0",
+			NAME:                "iscsi_lif_0",
+			OPERATIONALSTATUS:   "true",
+			ROLE:                "2",
+			RUNNINGSTATUS:       "10",
+			SUPPORTPROTOCOL:     "64",
+			TYPE:                TypeLogicalPort,
+		},
+	}
+
+	if !reflect.DeepEqual(logicalPorts, want) {
+		t.Errorf("GetLogicalPorts return %+v, want %+v", logicalPorts, want)
+	}
+}
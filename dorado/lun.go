@@ -25,6 +25,8 @@ type LUN struct {
 	CAPACITY                    int    `json:"CAPACITY,string"`
 	CAPACITYALARMLEVEL          string `json:"CAPACITYALARMLEVEL"`
 	CLONEIDS                    string `json:"CLONEIDS"`
+	CLONESOURCEID               string `json:"CLONESOURCEID"`
+	CLONESOURCETYPE             string `json:"CLONESOURCETYPE"`
 	COMPRESSION                 string `json:"COMPRESSION"`
 	COMPRESSIONSAVEDCAPACITY    string `json:"COMPRESSIONSAVEDCAPACITY"`
 	COMPRESSIONSAVEDRATIO       string `json:"COMPRESSIONSAVEDRATIO"`
@@ -64,6 +66,8 @@ type LUN struct {
 	SECTORSIZE                  string `json:"SECTORSIZE"`
 	SNAPSHOTIDS                 string `json:"SNAPSHOTIDS"`
 	SNAPSHOTSCHEDULEID          string `json:"SNAPSHOTSCHEDULEID"`
+	SPLITPROGRESS               string `json:"SPLITPROGRESS"`
+	SPLITSTATUS                 string `json:"SPLITSTATUS"`
 	SUBTYPE                     string `json:"SUBTYPE"`
 	THINCAPACITYUSAGE           string `json:"THINCAPACITYUSAGE"`
 	TOTALSAVEDCAPACITY          string `json:"TOTALSAVEDCAPACITY"`
@@ -103,9 +107,10 @@ type ParamCreateLUN struct {
 
 // ParamCreateCloneLUN is parameter for CreateCloneLUN
 type ParamCreateCloneLUN struct {
-	NAME          string `json:"NAME"`
-	CLONESOURCEID int    `json:"CLONESOURCEID"`
-	ISCLONE       bool   `json:"ISCLONE"`
+	NAME            string `json:"NAME"`
+	CLONESOURCEID   int    `json:"CLONESOURCEID"`
+	CLONESOURCETYPE int    `json:"CLONESOURCETYPE,omitempty"` // default is LUN, TypeSnapshot for snapshot
+	ISCLONE         bool   `json:"ISCLONE"`
 }
 
 // PrefixVolumeDescription is prefix of volume Description
@@ -165,15 +170,10 @@ func (d *Device) GetLUN(ctx context.Context, lunID int) (*LUN, error) {
 
 // CreateLUN create lun object
 func (d *Device) CreateLUN(ctx context.Context, u uuid.UUID, capacityGB int, storagePoolName string) (*LUN, error) {
-	storagePools, err := d.GetStoragePools(ctx, NewSearchQueryName(storagePoolName))
+	storagePoolID, err := d.getStoragePoolID(ctx, storagePoolName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get storagepool: %w", err)
-	}
-
-	if len(storagePools) != 1 {
-		return nil, errors.New("found multiple storagepool in same name")
+		return nil, err
 	}
-	storagePoolID := storagePools[0].ID
 
 	p := ParamCreateLUN{
 		NAME:               EncodeLunName(u),
@@ -192,6 +192,19 @@ func (d *Device) CreateLUN(ctx context.Context, u uuid.UUID, capacityGB int, sto
 	return d.createLUN(ctx, p)
 }
 
+func (d *Device) getStoragePoolID(ctx context.Context, storagePoolName string) (int, error) {
+	storagePools, err := d.GetStoragePools(ctx, NewSearchQueryName(storagePoolName))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get storagepool: %w", err)
+	}
+
+	if len(storagePools) != 1 {
+		return 0, errors.New("found multiple storagepool in same name")
+	}
+
+	return storagePools[0].ID, nil
+}
+
 func (d *Device) createLUN(ctx context.Context, param interface{}) (*LUN, error) {
 	spath := "/lun"
 
@@ -219,15 +232,19 @@ func (d *Device) CreateLUNWithWait(ctx context.Context, u uuid.UUID, capacityGB
 		return nil, fmt.Errorf("failed to create LUN: %w", err)
 	}
 
+	return d.waitLUNIsReady(ctx, lun.ID)
+}
+
+func (d *Device) waitLUNIsReady(ctx context.Context, lunID int) (*LUN, error) {
 	// wait 10 seconds
 	for i := 0; i < 10; i++ {
-		isReady, err := d.lunIsReady(ctx, lun.ID)
+		isReady, err := d.lunIsReady(ctx, lunID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to wait that LUN is ready: %w", err)
 		}
 
 		if isReady == true {
-			return d.GetLUN(ctx, lun.ID)
+			return d.GetLUN(ctx, lunID)
 		}
 
 		time.Sleep(1 * time.Second)
@@ -267,6 +284,34 @@ func (d *Device) DeleteLUN(ctx context.Context, lunID int) error {
 	return nil
 }
 
+// UpdateLUNParam is parameter for UpdateLUN
+type UpdateLUNParam struct {
+	NAME        string `json:"NAME,omitempty"`
+	DESCRIPTION string `json:"DESCRIPTION,omitempty"`
+}
+
+// UpdateLUN update name or description of LUN
+func (d *Device) UpdateLUN(ctx context.Context, lunID int, param UpdateLUNParam) (*LUN, error) {
+	spath := fmt.Sprintf("/lun/%d", lunID)
+
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	lun := &LUN{}
+	if err = d.requestWithRetry(req, lun, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return lun, nil
+}
+
 // ExpandLUN expand lun capacity
 func (d *Device) ExpandLUN(ctx context.Context, lunID int, newLunSizeGb int) error {
 	spath := "/lun/expand"
@@ -368,34 +413,24 @@ func (d *Device) CreateCloneLUN(ctx context.Context, lunID int, lunName uuid.UUI
 	return lun, nil
 }
 
-// SplitCloneLUN start to split LUN Clone
-func (d *Device) SplitCloneLUN(ctx context.Context, cloneLUNID int) error {
-	spath := "/lunclone_split_switch"
-	param := struct {
-		ID          int  `json:"ID"`
-		SPLITACTION int  `json:"SPLITACTION"`
-		ISCLONE     bool `json:"ISCLONE"`
-		SPLITSPEED  int  `json:"SPLITSPEED"`
-	}{
-		ID:          cloneLUNID,
-		SPLITACTION: 1,
-		ISCLONE:     true,
-		SPLITSPEED:  4,
-	}
-	jb, err := json.Marshal(param)
-	if err != nil {
-		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+// CreateCloneLUNFromSnapshot create clone LUN from snapshot.
+func (d *Device) CreateCloneLUNFromSnapshot(ctx context.Context, snapshotID int, lunName uuid.UUID) (*LUN, error) {
+	param := ParamCreateCloneLUN{
+		CLONESOURCEID:   snapshotID,
+		CLONESOURCETYPE: TypeSnapshot,
+		ISCLONE:         true,
+		NAME:            EncodeLunName(lunName),
 	}
 
-	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	lun, err := d.createLUN(ctx, param)
 	if err != nil {
-		return fmt.Errorf(ErrCreateRequest+": %w", err)
+		return nil, fmt.Errorf("failed to create LUN: %w", err)
 	}
 
-	var i interface{} // this endpoint return N/A
-	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
-		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
-	}
+	return lun, nil
+}
 
-	return nil
+// SplitCloneLUN start to split LUN Clone
+func (d *Device) SplitCloneLUN(ctx context.Context, cloneLUNID int) error {
+	return d.SplitCloneLUNWithSpeed(ctx, cloneLUNID, SpeedHighest)
 }
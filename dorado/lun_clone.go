@@ -0,0 +1,247 @@
+package dorado
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// CloneSplitMode is timing of split clone LUN
+type CloneSplitMode int
+
+// CloneSplitMode const
+const (
+	// SplitNow split clone LUN and wait to done
+	SplitNow CloneSplitMode = iota
+	// SplitBackground start to split clone LUN and not wait
+	SplitBackground
+	// SplitLater keep linked clone LUN, call SplitCloneLUNWithSpeed later (ex: off-hours)
+	SplitLater
+)
+
+// CloneOption is option of LUN Clone
+type CloneOption struct {
+	SplitSpeed Speed
+	SplitMode  CloneSplitMode
+}
+
+// NewCloneOption create default CloneOption (split now with highest speed)
+func NewCloneOption() *CloneOption {
+	return &CloneOption{
+		SplitSpeed: SpeedHighest,
+		SplitMode:  SplitNow,
+	}
+}
+
+// SPLITACTION values of lunclone_split_switch
+const (
+	splitActionStart = 1
+	splitActionPause = 2
+	splitActionStop  = 3
+)
+
+// SPLITSTATUS values of clone LUN
+const (
+	SplitStatusNotStarted = "1"
+	SplitStatusSplitting  = "2"
+	SplitStatusQueuing    = "3"
+	SplitStatusAbnormal   = "4"
+)
+
+// CloneSplitProgress is progress of split clone LUN
+type CloneSplitProgress struct {
+	IsDone   bool   // true if LUN is not a clone LUN (split is completed)
+	Status   string // SPLITSTATUS
+	Progress int    // percent, -1 is unknown
+}
+
+// CreateLUNFromSourceByLUNCloneWithOption create lun from source lun by LUN Clone with option.
+// clone LUN is returned without waiting split if SplitMode is not SplitNow.
+// if split is not finished in time, clone LUN is kept and returned with ErrTimeoutWait.
+func (d *Device) CreateLUNFromSourceByLUNCloneWithOption(ctx context.Context, sourceLUNID int, name uuid.UUID, capacityGB int, opt *CloneOption) (*LUN, error) {
+	return d.createLUNByLUNClone(ctx, func() (*LUN, error) {
+		return d.CreateCloneLUN(ctx, sourceLUNID, name)
+	}, capacityGB, opt)
+}
+
+// CreateLUNFromSnapshotByLUNCloneWithOption create lun from snapshot by LUN Clone with option.
+// clone LUN is created in storage pool of snapshot. snapshot must be activated.
+func (d *Device) CreateLUNFromSnapshotByLUNCloneWithOption(ctx context.Context, snapshotID int, name uuid.UUID, capacityGB int, opt *CloneOption) (*LUN, error) {
+	return d.createLUNByLUNClone(ctx, func() (*LUN, error) {
+		return d.CreateCloneLUNFromSnapshot(ctx, snapshotID, name)
+	}, capacityGB, opt)
+}
+
+func (d *Device) createLUNByLUNClone(ctx context.Context, createCloneLUN func() (*LUN, error), capacityGB int, opt *CloneOption) (*LUN, error) {
+	if opt == nil {
+		opt = NewCloneOption()
+	}
+
+	cloneLUN, err := createCloneLUN()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clone LUN: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if err := d.DeleteLUN(ctx, cloneLUN.ID); err != nil {
+				d.Logger.Printf("failed to delete LUN: %v", err)
+			}
+		}
+	}()
+
+	if cloneLUN.CAPACITY < capacityGB*CapacityUnit {
+		if err = d.ExpandLUN(ctx, cloneLUN.ID, capacityGB); err != nil {
+			return nil, fmt.Errorf("failed to expand LUN: %w", err)
+		}
+	}
+
+	if opt.SplitMode == SplitLater {
+		return d.GetLUN(ctx, cloneLUN.ID)
+	}
+
+	if err = d.SplitCloneLUNWithSpeed(ctx, cloneLUN.ID, opt.SplitSpeed); err != nil {
+		return nil, fmt.Errorf("failed to split clone LUN: %w", err)
+	}
+
+	if opt.SplitMode == SplitBackground {
+		return d.GetLUN(ctx, cloneLUN.ID)
+	}
+
+	for i := 0; i < DefaultCopyTimeoutSecond; i++ {
+		var isReady bool
+		isReady, err = d.lunIsReady(ctx, cloneLUN.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wait that LUN is ready: %w", err)
+		}
+
+		if isReady == true {
+			return d.GetLUN(ctx, cloneLUN.ID)
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+
+	// clone LUN is splitting yet, so keep it and return it with error.
+	lun, getErr := d.GetLUN(ctx, cloneLUN.ID)
+	if getErr != nil {
+		lun = cloneLUN
+	}
+	return lun, fmt.Errorf("%w: clone LUN (ID: %d) is splitting yet, check by GetCloneSplitProgress", ErrTimeoutWait, cloneLUN.ID)
+}
+
+// deleteCloneLUN stop to split clone LUN if it is splitting yet, and delete it. other LUN is just deleted.
+func (d *Device) deleteCloneLUN(ctx context.Context, lun *LUN) error {
+	if lun.SPLITSTATUS == SplitStatusSplitting {
+		if err := d.StopCloneSplit(ctx, lun.ID); err != nil {
+			return fmt.Errorf("failed to stop to split clone LUN (ID: %d): %w", lun.ID, err)
+		}
+	}
+
+	return d.DeleteLUN(ctx, lun.ID)
+}
+
+// SplitCloneLUNWithSpeed start to split LUN Clone with speed
+func (d *Device) SplitCloneLUNWithSpeed(ctx context.Context, cloneLUNID int, speed Speed) error {
+	return d.switchCloneSplit(ctx, cloneLUNID, splitActionStart, speed)
+}
+
+// PauseCloneSplit pause to split LUN Clone, resume by SplitCloneLUNWithSpeed
+func (d *Device) PauseCloneSplit(ctx context.Context, cloneLUNID int) error {
+	return d.switchCloneSplit(ctx, cloneLUNID, splitActionPause, 0)
+}
+
+// StopCloneSplit stop to split LUN Clone, clone LUN is kept as linked clone
+func (d *Device) StopCloneSplit(ctx context.Context, cloneLUNID int) error {
+	return d.switchCloneSplit(ctx, cloneLUNID, splitActionStop, 0)
+}
+
+func (d *Device) switchCloneSplit(ctx context.Context, cloneLUNID int, action int, speed Speed) error {
+	spath := "/lunclone_split_switch"
+	param := struct {
+		ID          int  `json:"ID"`
+		SPLITACTION int  `json:"SPLITACTION"`
+		ISCLONE     bool `json:"ISCLONE"`
+		SPLITSPEED  int  `json:"SPLITSPEED,omitempty"`
+	}{
+		ID:          cloneLUNID,
+		SPLITACTION: action,
+		ISCLONE:     true,
+		SPLITSPEED:  int(speed),
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// GetCloneSplitProgress get progress of split clone LUN
+func (d *Device) GetCloneSplitProgress(ctx context.Context, cloneLUNID int) (*CloneSplitProgress, error) {
+	lun, err := d.GetLUN(ctx, cloneLUNID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LUN: %w", err)
+	}
+
+	if lun.ISCLONE == false {
+		return &CloneSplitProgress{
+			IsDone:   true,
+			Status:   lun.SPLITSTATUS,
+			Progress: 100,
+		}, nil
+	}
+
+	progress, err := strconv.Atoi(lun.SPLITPROGRESS)
+	if err != nil || progress < 0 {
+		progress = -1
+	}
+
+	return &CloneSplitProgress{
+		IsDone:   false,
+		Status:   lun.SPLITSTATUS,
+		Progress: progress,
+	}, nil
+}
+
+// ListClones get clone LUNs that source is sourceLUNID
+func (d *Device) ListClones(ctx context.Context, sourceLUNID int) ([]LUN, error) {
+	source, err := d.GetLUN(ctx, sourceLUNID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source LUN: %w", err)
+	}
+
+	cloneIDs, err := parseIDList(source.CLONEIDS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CLONEIDS: %w", err)
+	}
+
+	var clones []LUN
+	for _, cloneID := range cloneIDs {
+		clone, err := d.GetLUN(ctx, cloneID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get clone LUN (ID: %d): %w", cloneID, err)
+		}
+		clones = append(clones, *clone)
+	}
+
+	if len(clones) == 0 {
+		return nil, ErrLunNotFound
+	}
+
+	return clones, nil
+}
@@ -0,0 +1,140 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestDevice_SplitCloneLUNWithSpeed(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var got []map[string]interface{}
+	mux.HandleFunc("/lunclone_split_switch", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+
+		got = append(got, testDecodeBody(t, r))
+
+		fmt.Fprintf(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	if err := client.LocalDevice.SplitCloneLUNWithSpeed(context.Background(), 9, SpeedLow); err != nil {
+		t.Fatalf("SplitCloneLUNWithSpeed return err: %s", err)
+	}
+	if err := client.LocalDevice.StopCloneSplit(context.Background(), 9); err != nil {
+		t.Fatalf("StopCloneSplit return err: %s", err)
+	}
+
+	want := []map[string]interface{}{
+		{"ID": float64(9), "SPLITACTION": float64(1), "ISCLONE": true, "SPLITSPEED": float64(SpeedLow)},
+		{"ID": float64(9), "SPLITACTION": float64(3), "ISCLONE": true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lunclone_split_switch request %+v, want %+v", got, want)
+	}
+}
+
+func TestDevice_ListClones(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/lun/7", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "7", "NAME": "golden", "CLONEIDS": "[\"9\"]"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/9", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "9", "NAME": "clone", "ISCLONE": "true", "SPLITSTATUS": "2", "SPLITPROGRESS": "40"}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	clones, err := client.LocalDevice.ListClones(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("ListClones return err: %s", err)
+	}
+	want := []LUN{
+		{ID: 9, NAME: "clone", ISCLONE: true, SPLITSTATUS: SplitStatusSplitting, SPLITPROGRESS: "40"},
+	}
+	if !reflect.DeepEqual(clones, want) {
+		t.Errorf("ListClones return %+v, want %+v", clones, want)
+	}
+
+	progress, err := client.LocalDevice.GetCloneSplitProgress(context.Background(), 9)
+	if err != nil {
+		t.Fatalf("GetCloneSplitProgress return err: %s", err)
+	}
+	wantProgress := &CloneSplitProgress{IsDone: false, Status: SplitStatusSplitting, Progress: 40}
+	if !reflect.DeepEqual(progress, wantProgress) {
+		t.Errorf("GetCloneSplitProgress return %+v, want %+v", progress, wantProgress)
+	}
+}
+
+func TestDevice_CreateLUNFromSourceByLUNCloneWithOption_ReadyError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var deleted bool
+	mux.HandleFunc("/lun", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"data": {"ID": "9", "NAME": "clone", "CAPACITY": "2097152", "ISCLONE": "true"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lunclone_split_switch", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/9", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 1077949002, "description": "internal error"}}`)
+		case "DELETE":
+			deleted = true
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+		default:
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+	})
+
+	_, err := client.LocalDevice.CreateLUNFromSourceByLUNCloneWithOption(context.Background(), 7, uuid.NewV4(), 1, nil)
+	if err == nil {
+		t.Fatalf("CreateLUNFromSourceByLUNCloneWithOption must return err")
+	}
+	if !deleted {
+		t.Errorf("clone LUN must be deleted when failed to check that LUN is ready")
+	}
+}
+
+func TestDevice_CreateLUNFromSnapshotByLUNCloneWithOption(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	name := uuid.FromStringOrNil("77bea474-b5fb-4d5d-a3b8-0d4fd3a4b0c1")
+	mux.HandleFunc("/lun", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		testBody(t, r, map[string]interface{}{
+			"NAME":            EncodeLunName(name),
+			"CLONESOURCEID":   float64(30),
+			"CLONESOURCETYPE": float64(TypeSnapshot),
+			"ISCLONE":         true,
+		})
+
+		fmt.Fprint(w, `{"data": {"ID": "9", "NAME": "clone", "CAPACITY": "2097152", "ISCLONE": "true"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/9", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "9", "NAME": "clone", "CAPACITY": "2097152", "ISCLONE": "true"}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	opt := &CloneOption{SplitMode: SplitLater}
+	lun, err := client.LocalDevice.CreateLUNFromSnapshotByLUNCloneWithOption(context.Background(), 30, name, 1, opt)
+	if err != nil {
+		t.Fatalf("CreateLUNFromSnapshotByLUNCloneWithOption return err: %s", err)
+	}
+	if lun.ID != 9 || !lun.ISCLONE {
+		t.Errorf("CreateLUNFromSnapshotByLUNCloneWithOption return %+v", lun)
+	}
+}
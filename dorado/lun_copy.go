@@ -6,7 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // LunCopy is copy object for lun
@@ -32,6 +35,95 @@ type LunCopy struct {
 	TYPE                  int    `json:"TYPE"`
 }
 
+// LunCopyType is type of LUN copy
+type LunCopyType int
+
+// LunCopyType const
+const (
+	LunCopyFull        LunCopyType = 1
+	LunCopyIncremental LunCopyType = 2
+)
+
+// LunCopyStatus is RUNNINGSTATUS of LUN copy
+type LunCopyStatus int
+
+// LunCopyStatus const
+const (
+	LunCopyStatusQueuing  LunCopyStatus = 36
+	LunCopyStatusCopying  LunCopyStatus = 37
+	LunCopyStatusStopped  LunCopyStatus = 38
+	LunCopyStatusPaused   LunCopyStatus = 39
+	LunCopyStatusComplete LunCopyStatus = StatusLunCopyReady
+)
+
+// String is function compatible for fmt.Stringer
+func (s LunCopyStatus) String() string {
+	switch s {
+	case LunCopyStatusQueuing:
+		return "queuing"
+	case LunCopyStatusCopying:
+		return "copying"
+	case LunCopyStatusStopped:
+		return "stopped"
+	case LunCopyStatusPaused:
+		return "paused"
+	case LunCopyStatusComplete:
+		return "complete"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(s))
+	}
+}
+
+// Status return typed RUNNINGSTATUS. return 0 if unknown value.
+func (lc *LunCopy) Status() LunCopyStatus {
+	s, err := strconv.Atoi(lc.RUNNINGSTATUS)
+	if err != nil {
+		return 0
+	}
+
+	return LunCopyStatus(s)
+}
+
+// LunCopyOptions is options of CreateLUNCopyWithOptions
+type LunCopyOptions struct {
+	Name        string // default: LUNCopy_<source>_<first target>
+	Description string
+	Speed       Speed       // default: SpeedHighest
+	Type        LunCopyType // default: LunCopyFull
+}
+
+// NewLunCopyOptions create default LunCopyOptions (full copy with highest speed)
+func NewLunCopyOptions() *LunCopyOptions {
+	return &LunCopyOptions{
+		Speed: SpeedHighest,
+		Type:  LunCopyFull,
+	}
+}
+
+// lunCopyLUNDescriptor return LUN descriptor of SOURCELUN or TARGETLUN in local device.
+func lunCopyLUNDescriptor(lunID int) string {
+	return fmt.Sprintf("INVALID;%d;INVALID;INVALID;INVALID", lunID)
+}
+
+// parseLUNCopyLocalLUNIDs parse LUN IDs in local device from SOURCELUN or TARGETLUN.
+// LUN descriptors in remote device (first field is remote array ID) are skipped.
+func parseLUNCopyLocalLUNIDs(descriptors string) ([]int, error) {
+	var ids []int
+	for _, descriptor := range strings.Split(descriptors, ",") {
+		fields := strings.Split(strings.TrimSpace(descriptor), ";")
+		if len(fields) < 2 || fields[0] != "INVALID" {
+			continue
+		}
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse LUN ID of LUN descriptor (%s): %w", descriptor, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
 // GetLUNCopys get lun copy objects by query
 func (d *Device) GetLUNCopys(ctx context.Context, query *SearchQuery) ([]LunCopy, error) {
 	spath := "/luncopy"
@@ -73,17 +165,56 @@ func (d *Device) GetLUNCopy(ctx context.Context, lunCopyID int) (*LunCopy, error
 
 // CreateLUNCopy create lun copy definition of source to target lun
 func (d *Device) CreateLUNCopy(ctx context.Context, sourceLUNID, targetLUNID int) (*LunCopy, error) {
+	return d.CreateLUNCopyWithOptions(ctx, sourceLUNID, []int{targetLUNID}, nil)
+}
+
+// CreateLUNCopyWithOptions create lun copy definition of source to multiple target luns
+func (d *Device) CreateLUNCopyWithOptions(ctx context.Context, sourceLUNID int, targetLUNIDs []int, opts *LunCopyOptions) (*LunCopy, error) {
+	if len(targetLUNIDs) == 0 {
+		return nil, errors.New("target LUN IDs is empty")
+	}
+
+	var targets []string
+	for _, targetLUNID := range targetLUNIDs {
+		targets = append(targets, lunCopyLUNDescriptor(targetLUNID))
+	}
+
+	return d.createLUNCopy(ctx, lunCopyLUNDescriptor(sourceLUNID), targets, fmt.Sprintf("LUNCopy_%d_%d", sourceLUNID, targetLUNIDs[0]), opts)
+}
+
+// createLUNCopy create lun copy definition by LUN descriptors
+func (d *Device) createLUNCopy(ctx context.Context, source string, targets []string, defaultName string, opts *LunCopyOptions) (*LunCopy, error) {
+	if opts == nil {
+		opts = NewLunCopyOptions()
+	}
+	name := opts.Name
+	if name == "" {
+		name = defaultName
+	}
+	speed := opts.Speed
+	if speed == 0 {
+		speed = SpeedHighest
+	}
+	copyType := opts.Type
+	if copyType == 0 {
+		copyType = LunCopyFull
+	}
+
 	spath := "/luncopy"
 	param := struct {
-		NAME      string `json:"NAME"`
-		SOURCELUN string `json:"SOURCELUN"`
-		TARGETLUN string `json:"TARGETLUN"`
-		COPYSPEED int    `json:"COPYSPEED"`
+		NAME        string `json:"NAME"`
+		DESCRIPTION string `json:"DESCRIPTION,omitempty"`
+		SOURCELUN   string `json:"SOURCELUN"`
+		TARGETLUN   string `json:"TARGETLUN"`
+		COPYSPEED   int    `json:"COPYSPEED"`
+		LUNCOPYTYPE int    `json:"LUNCOPYTYPE,omitempty"`
 	}{
-		NAME:      fmt.Sprintf("LUNCopy_%d_%d", sourceLUNID, targetLUNID),
-		SOURCELUN: fmt.Sprintf("INVALID;%d;INVALID;INVALID;INVALID", sourceLUNID),
-		TARGETLUN: fmt.Sprintf("INVALID;%d;INVALID;INVALID;INVALID", targetLUNID),
-		COPYSPEED: 4,
+		NAME:        name,
+		DESCRIPTION: opts.Description,
+		SOURCELUN:   source,
+		TARGETLUN:   strings.Join(targets, ","),
+		COPYSPEED:   int(speed),
+		LUNCOPYTYPE: int(copyType),
 	}
 	jb, err := json.Marshal(param)
 	if err != nil {
@@ -122,7 +253,25 @@ func (d *Device) DeleteLUNCopy(ctx context.Context, luncopyID int) error {
 
 // StartLUNCopy start to copy lun
 func (d *Device) StartLUNCopy(ctx context.Context, luncopyID int) error {
-	spath := "/luncopy/start"
+	return d.putLUNCopyAction(ctx, "/luncopy/start", luncopyID)
+}
+
+// StopLUNCopy stop to copy lun
+func (d *Device) StopLUNCopy(ctx context.Context, luncopyID int) error {
+	return d.putLUNCopyAction(ctx, "/luncopy/stop", luncopyID)
+}
+
+// PauseLUNCopy pause to copy lun
+func (d *Device) PauseLUNCopy(ctx context.Context, luncopyID int) error {
+	return d.putLUNCopyAction(ctx, "/luncopy/pause", luncopyID)
+}
+
+// ResumeLUNCopy resume to copy lun that paused
+func (d *Device) ResumeLUNCopy(ctx context.Context, luncopyID int) error {
+	return d.putLUNCopyAction(ctx, "/luncopy/resume", luncopyID)
+}
+
+func (d *Device) putLUNCopyAction(ctx context.Context, spath string, luncopyID int) error {
 	param := struct {
 		TYPE string `json:"TYPE"`
 		ID   string `json:"ID"`
@@ -148,7 +297,8 @@ func (d *Device) StartLUNCopy(ctx context.Context, luncopyID int) error {
 	return nil
 }
 
-// StartLUNCopyWithWait start luncopy and wait to copy
+// StartLUNCopyWithWait start luncopy and wait to copy.
+// return ErrLunCopyPaused if luncopy is paused while waiting.
 func (d *Device) StartLUNCopyWithWait(ctx context.Context, luncopyID int, timeoutCount int) error {
 	if timeoutCount == 0 {
 		timeoutCount = DefaultCopyTimeoutSecond
@@ -186,8 +336,14 @@ func (d *Device) luncopyIsDone(ctx context.Context, luncopyID int) (bool, error)
 		return false, fmt.Errorf("luncopy health status is bad (HEALTHSTATUS: %s)", luncopy.HEALTHSTATUS)
 	}
 
-	if luncopy.RUNNINGSTATUS == strconv.Itoa(StatusLunCopyReady) {
+	switch luncopy.Status() {
+	case LunCopyStatusComplete:
 		return true, nil
+	case LunCopyStatusStopped:
+		return false, errors.New("luncopy is stopped")
+	case LunCopyStatusPaused:
+		// paused copy is not progressed until ResumeLUNCopy
+		return false, ErrLunCopyPaused
 	}
 
 	return false, nil
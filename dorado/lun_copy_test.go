@@ -2,6 +2,7 @@ package dorado
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -80,3 +81,113 @@ func TestDevice_GetLUNCopys(t *testing.T) {
 		t.Errorf("GetLUNCopys return %+v, want %+v", luncopys, want)
 	}
 }
+
+func TestDevice_CreateLUNCopyWithOptions(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/luncopy", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		testBody(t, r, map[string]interface{}{
+			"NAME":        "LUNCopy_7_12",
+			"DESCRIPTION": "backup",
+			"SOURCELUN":   "INVALID;7;INVALID;INVALID;INVALID",
+			"TARGETLUN":   "INVALID;12;INVALID;INVALID;INVALID,INVALID;13;INVALID;INVALID;INVALID",
+			"COPYSPEED":   float64(SpeedMedium),
+			"LUNCOPYTYPE": float64(LunCopyIncremental),
+		})
+
+		fmt.Fprint(w, `{"data": {"ID": "3", "RUNNINGSTATUS": "36", "TYPE": 219}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	opts := &LunCopyOptions{
+		Description: "backup",
+		Speed:       SpeedMedium,
+		Type:        LunCopyIncremental,
+	}
+	luncopy, err := client.LocalDevice.CreateLUNCopyWithOptions(context.Background(), 7, []int{12, 13}, opts)
+	if err != nil {
+		t.Fatalf("CreateLUNCopyWithOptions return err: %s", err)
+	}
+	if luncopy.Status() != LunCopyStatusQueuing {
+		t.Errorf("Status return %s, want %s", luncopy.Status(), LunCopyStatusQueuing)
+	}
+}
+
+func TestDevice_CreateLUNCopyWithOptions_Default(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/luncopy", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		testBody(t, r, map[string]interface{}{
+			"NAME":        "backup",
+			"SOURCELUN":   "INVALID;7;INVALID;INVALID;INVALID",
+			"TARGETLUN":   "INVALID;12;INVALID;INVALID;INVALID",
+			"COPYSPEED":   float64(SpeedHighest),
+			"LUNCOPYTYPE": float64(LunCopyFull),
+		})
+
+		fmt.Fprint(w, `{"data": {"ID": "3", "RUNNINGSTATUS": "36", "TYPE": 219}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	if _, err := client.LocalDevice.CreateLUNCopyWithOptions(context.Background(), 7, []int{12}, &LunCopyOptions{Name: "backup"}); err != nil {
+		t.Fatalf("CreateLUNCopyWithOptions return err: %s", err)
+	}
+}
+
+func TestDevice_CreateRemoteLUNCopy(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/luncopy", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		testBody(t, r, map[string]interface{}{
+			"NAME":        "LUNCopy_7_0_21",
+			"SOURCELUN":   "INVALID;7;INVALID;INVALID;INVALID",
+			"TARGETLUN":   "0;21;2100e0cc7b000000;6a400e210055e22650d557a000000015;INVALID",
+			"COPYSPEED":   float64(SpeedHighest),
+			"LUNCOPYTYPE": float64(LunCopyFull),
+		})
+
+		fmt.Fprint(w, `{"data": {"ID": "4", "RUNNINGSTATUS": "36", "TYPE": 219}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	remoteArray := &RemoteArray{ID: "0", WWN: "2100e0cc7b000000"}
+	targets := []LUN{{ID: 21, WWN: "6a400e210055e22650d557a000000015"}}
+	luncopy, err := client.LocalDevice.CreateRemoteLUNCopy(context.Background(), 7, remoteArray, targets, nil)
+	if err != nil {
+		t.Fatalf("CreateRemoteLUNCopy return err: %s", err)
+	}
+	if luncopy.ID != 4 {
+		t.Errorf("CreateRemoteLUNCopy return ID %d, want %d", luncopy.ID, 4)
+	}
+}
+
+func TestDevice_StartLUNCopyWithWait_Paused(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/luncopy/start", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testBody(t, r, map[string]interface{}{"TYPE": "219", "ID": "3"})
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+	requested := 0
+	mux.HandleFunc("/luncopy/3", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		requested++
+		fmt.Fprint(w, `{"data": {"ID": "3", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "39", "TYPE": 219}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	err := client.LocalDevice.StartLUNCopyWithWait(context.Background(), 3, 10)
+	if !errors.Is(err, ErrLunCopyPaused) {
+		t.Errorf("StartLUNCopyWithWait return err %v, want %v", err, ErrLunCopyPaused)
+	}
+	if requested != 1 {
+		t.Errorf("paused luncopy is requested %d times, want 1 (must not wait until timeout)", requested)
+	}
+}
@@ -110,11 +110,31 @@ func (d *Device) DeleteLunGroup(ctx context.Context, lungroupID int) error {
 
 // AssociateLun associate lun to lun group
 func (d *Device) AssociateLun(ctx context.Context, lungroupID, lunID int) error {
+	return d.associateLunGroup(ctx, lungroupID, lunID, TypeLUN)
+}
+
+// DisAssociateLun dis associate lun from lun group
+func (d *Device) DisAssociateLun(ctx context.Context, lungroupID, lunID int) error {
+	return d.disAssociateLunGroup(ctx, lungroupID, lunID, TypeLUN)
+}
+
+// AssociateSnapshot associate snapshot to lun group.
+// snapshot must be activated for read from host.
+func (d *Device) AssociateSnapshot(ctx context.Context, lungroupID, snapshotID int) error {
+	return d.associateLunGroup(ctx, lungroupID, snapshotID, TypeSnapshot)
+}
+
+// DisAssociateSnapshot dis associate snapshot from lun group
+func (d *Device) DisAssociateSnapshot(ctx context.Context, lungroupID, snapshotID int) error {
+	return d.disAssociateLunGroup(ctx, lungroupID, snapshotID, TypeSnapshot)
+}
+
+func (d *Device) associateLunGroup(ctx context.Context, lungroupID, objID, objType int) error {
 	spath := "/lungroup/associate"
 	param := AssociateParam{
 		ID:               strconv.Itoa(lungroupID),
-		ASSOCIATEOBJID:   strconv.Itoa(lunID),
-		ASSOCIATEOBJTYPE: TypeLUN,
+		ASSOCIATEOBJID:   strconv.Itoa(objID),
+		ASSOCIATEOBJTYPE: objType,
 	}
 	jb, err := json.Marshal(param)
 	if err != nil {
@@ -134,13 +154,12 @@ func (d *Device) AssociateLun(ctx context.Context, lungroupID, lunID int) error
 	return nil
 }
 
-// DisAssociateLun dis associate lun from lun group
-func (d *Device) DisAssociateLun(ctx context.Context, lungroupID, lunID int) error {
+func (d *Device) disAssociateLunGroup(ctx context.Context, lungroupID, objID, objType int) error {
 	spath := "/lungroup/associate"
 	param := &AssociateParam{
 		ID:               strconv.Itoa(lungroupID),
-		ASSOCIATEOBJID:   strconv.Itoa(lunID),
-		ASSOCIATEOBJTYPE: TypeLUN,
+		ASSOCIATEOBJID:   strconv.Itoa(objID),
+		ASSOCIATEOBJTYPE: objType,
 	}
 
 	req, err := d.newRequest(ctx, "DELETE", spath, nil)
@@ -177,9 +196,18 @@ func (d *Device) GetAssociateLunGroups(ctx context.Context, query *SearchQuery)
 
 // GetLunGroupByLunID get associated lun group by lun id.
 func (d *Device) GetLunGroupByLunID(ctx context.Context, lunID int) (*LunGroup, error) {
+	return d.getLunGroupByObject(ctx, lunID, TypeLUN)
+}
+
+// GetLunGroupBySnapshotID get associated lun group by snapshot id.
+func (d *Device) GetLunGroupBySnapshotID(ctx context.Context, snapshotID int) (*LunGroup, error) {
+	return d.getLunGroupByObject(ctx, snapshotID, TypeSnapshot)
+}
+
+func (d *Device) getLunGroupByObject(ctx context.Context, objID, objType int) (*LunGroup, error) {
 	query := &SearchQuery{
-		AssociateObjType: strconv.Itoa(TypeLUN),
-		AssociateObjID:   strconv.Itoa(lunID),
+		AssociateObjType: strconv.Itoa(objType),
+		AssociateObjID:   strconv.Itoa(objID),
 		Type:             strconv.Itoa(TypeLUNGroup),
 	}
 
@@ -188,7 +216,7 @@ func (d *Device) GetLunGroupByLunID(ctx context.Context, lunID int) (*LunGroup,
 		return nil, fmt.Errorf("failed to get lun group: %w", err)
 	}
 	if len(lungroups) != 1 {
-		return nil, fmt.Errorf("found multiple LUN Group in same lun id: %w", err)
+		return nil, fmt.Errorf("found multiple LUN Group in same object id: %w", err)
 	}
 
 	return &lungroups[0], nil
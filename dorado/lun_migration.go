@@ -0,0 +1,321 @@
+package dorado
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// LunMigration is SmartMigration task (MIGRATION object).
+// ID of task is same as source LUN ID.
+type LunMigration struct {
+	HEALTHSTATUS  string `json:"HEALTHSTATUS"`
+	ID            int    `json:"ID,string"`
+	NAME          string `json:"NAME"`
+	PARENTID      int    `json:"PARENTID,string"` // source LUN ID
+	PROGRESS      string `json:"PROGRESS"`
+	RUNNINGSTATUS string `json:"RUNNINGSTATUS"`
+	SPEED         string `json:"SPEED"`
+	TARGETLUNID   int    `json:"TARGETLUNID,string"`
+	TYPE          int    `json:"TYPE"`
+}
+
+// LunMigrationStatus is RUNNINGSTATUS of LUN Migration
+type LunMigrationStatus int
+
+// LunMigrationStatus const
+const (
+	LunMigrationStatusFault     LunMigrationStatus = 74
+	LunMigrationStatusMigrating LunMigrationStatus = 75
+	LunMigrationStatusComplete  LunMigrationStatus = 76
+)
+
+// String is function compatible for fmt.Stringer
+func (s LunMigrationStatus) String() string {
+	switch s {
+	case LunMigrationStatusFault:
+		return "Fault"
+	case LunMigrationStatusMigrating:
+		return "Migrating"
+	case LunMigrationStatusComplete:
+		return "Complete"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int(s))
+	}
+}
+
+// Status return RUNNINGSTATUS of LUN Migration
+func (lm *LunMigration) Status() LunMigrationStatus {
+	status, err := strconv.Atoi(lm.RUNNINGSTATUS)
+	if err != nil {
+		return 0
+	}
+	return LunMigrationStatus(status)
+}
+
+// Progress return percent of migration, -1 is unknown
+func (lm *LunMigration) Progress() int {
+	if lm.Status() == LunMigrationStatusComplete {
+		return 100
+	}
+
+	progress, err := strconv.Atoi(lm.PROGRESS)
+	if err != nil || progress < 0 {
+		return -1
+	}
+	return progress
+}
+
+// PrefixMigrationTargetDescription is prefix of target LUN Description in LUN Migration
+var PrefixMigrationTargetDescription = "migration-target-"
+
+// GetLUNMigrations get LUN Migration tasks by query
+func (d *Device) GetLUNMigrations(ctx context.Context, query *SearchQuery) ([]LunMigration, error) {
+	spath := "/MIGRATION"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var lunMigrations []LunMigration
+	if err = d.requestWithRetry(req, &lunMigrations, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(lunMigrations) == 0 {
+		return nil, ErrLunMigrationNotFound
+	}
+
+	return lunMigrations, nil
+}
+
+// GetLUNMigration get LUN Migration task by source LUN ID
+func (d *Device) GetLUNMigration(ctx context.Context, sourceLUNID int) (*LunMigration, error) {
+	spath := fmt.Sprintf("/MIGRATION/%d", sourceLUNID)
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	lunMigration := &LunMigration{}
+	if err = d.requestWithRetry(req, lunMigration, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return lunMigration, nil
+}
+
+// CreateLUNMigration create LUN Migration task from source LUN to target LUN.
+// migration is started after created.
+func (d *Device) CreateLUNMigration(ctx context.Context, sourceLUNID, targetLUNID int, speed Speed) (*LunMigration, error) {
+	spath := "/MIGRATION"
+	param := struct {
+		TYPE        string `json:"TYPE"`
+		PARENTID    string `json:"PARENTID"`
+		TARGETLUNID string `json:"TARGETLUNID"`
+		SPEED       int    `json:"SPEED"`
+		WORKMODE    int    `json:"WORKMODE"`
+	}{
+		TYPE:        strconv.Itoa(TypeLUNMigration),
+		PARENTID:    strconv.Itoa(sourceLUNID),
+		TARGETLUNID: strconv.Itoa(targetLUNID),
+		SPEED:       int(speed),
+		WORKMODE:    0,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	lunMigration := &LunMigration{}
+	if err = d.requestWithRetry(req, lunMigration, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return lunMigration, nil
+}
+
+// SplitLUNMigration delete LUN Migration task.
+// if migration is completed, source LUN is split from target (source LUN keep ID, WWN and mappings, data is in target pool).
+// if migration is not completed, migration is canceled.
+func (d *Device) SplitLUNMigration(ctx context.Context, sourceLUNID int) error {
+	spath := fmt.Sprintf("/MIGRATION/%d", sourceLUNID)
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// WaitLUNMigration wait to complete LUN Migration.
+// timeoutCount is seconds to wait, 0 is DefaultCopyTimeoutSecond, and negative is wait until ctx is done.
+func (d *Device) WaitLUNMigration(ctx context.Context, sourceLUNID int, timeoutCount int) error {
+	if timeoutCount == 0 {
+		timeoutCount = DefaultCopyTimeoutSecond
+	}
+
+	for i := 0; timeoutCount < 0 || i < timeoutCount; i++ {
+		isDone, err := d.lunMigrationIsDone(ctx, sourceLUNID)
+		if err != nil {
+			return fmt.Errorf("failed to wait that LUN Migration is done: %w", err)
+		}
+
+		if isDone == true {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
+	}
+
+	return ErrTimeoutWait
+}
+
+func (d *Device) lunMigrationIsDone(ctx context.Context, sourceLUNID int) (bool, error) {
+	lunMigration, err := d.GetLUNMigration(ctx, sourceLUNID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get LUN Migration (ID: %d): %w", sourceLUNID, err)
+	}
+
+	switch lunMigration.Status() {
+	case LunMigrationStatusComplete:
+		return true, nil
+	case LunMigrationStatusFault:
+		return false, errors.New("LUN Migration is fault")
+	}
+
+	return false, nil
+}
+
+// createMigrationTargetLUN create target LUN of LUN Migration in storage pool.
+func (d *Device) createMigrationTargetLUN(ctx context.Context, source *LUN, storagePoolName string) (*LUN, error) {
+	storagePoolID, err := d.getStoragePoolID(ctx, storagePoolName)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep thin or thick provisioning of source
+	allocType, err := strconv.Atoi(source.ALLOCTYPE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ALLOCTYPE of source LUN (ALLOCTYPE: %s): %w", source.ALLOCTYPE, err)
+	}
+
+	p := ParamCreateLUN{
+		NAME:               EncodeLunName(uuid.NewV4()),
+		PARENTID:           strconv.Itoa(storagePoolID),
+		DESCRIPTION:        PrefixMigrationTargetDescription + strconv.Itoa(source.ID),
+		CAPACITY:           source.CAPACITY,
+		WRITEPOLICY:        "1",
+		PREFETCHVALUE:      "0",
+		ALLOCTYPE:          allocType,
+		MIRRORPOLICY:       "1",
+		DATATRANSFERPOLICY: "0",
+		WORKLOADTYPEID:     "0",
+		PREFETCHPOLICY:     "3",
+	}
+	lun, err := d.createLUN(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LUN: %w", err)
+	}
+
+	return d.waitLUNIsReady(ctx, lun.ID)
+}
+
+// MigrateLUNToPool move LUN to other storage pool by SmartMigration, and wait to split.
+// LUN ID, WWN and mappings are not changed, so LUN can be attached while migration.
+// it wait until migration is completed or ctx is done. if ctx is done, migration is kept running,
+// and call again with same storage pool to wait running migration.
+func (d *Device) MigrateLUNToPool(ctx context.Context, lunID int, storagePoolName string, speed Speed) (*LUN, error) {
+	source, err := d.GetLUN(ctx, lunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source LUN: %w", err)
+	}
+	if source.PARENTNAME == storagePoolName {
+		return source, nil
+	}
+
+	targetID, err := d.startLUNMigrationToPool(ctx, source, storagePoolName, speed)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.WaitLUNMigration(ctx, source.ID, -1); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to wait LUN Migration (migration is running yet): %w", err)
+		}
+
+		// migration is fault, cancel it
+		if err := d.SplitLUNMigration(ctx, source.ID); err != nil {
+			d.Logger.Printf("failed to cancel LUN Migration: %v\n", err)
+		} else if err := d.DeleteLUN(ctx, targetID); err != nil {
+			d.Logger.Printf("failed to delete target LUN: %v\n", err)
+		}
+		return nil, fmt.Errorf("failed to migrate LUN: %w", err)
+	}
+	if err := d.SplitLUNMigration(ctx, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to split LUN Migration: %w", err)
+	}
+
+	// target LUN has old data after split
+	if err := d.DeleteLUN(ctx, targetID); err != nil {
+		d.Logger.Printf("failed to delete target LUN: %v\n", err)
+	}
+
+	return d.GetLUN(ctx, source.ID)
+}
+
+// startLUNMigrationToPool create LUN Migration of source to storage pool, and return ID of target LUN.
+// running LUN Migration of source is reused.
+func (d *Device) startLUNMigrationToPool(ctx context.Context, source *LUN, storagePoolName string, speed Speed) (int, error) {
+	lunMigrations, err := d.GetLUNMigrations(ctx, &SearchQuery{Filter: ToFilter("PARENTID", strconv.Itoa(source.ID))})
+	if err != nil && err != ErrLunMigrationNotFound {
+		return 0, fmt.Errorf("failed to get LUN Migrations: %w", err)
+	}
+	if err == nil {
+		target, err := d.GetLUN(ctx, lunMigrations[0].TARGETLUNID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get target LUN of running LUN Migration: %w", err)
+		}
+		if target.PARENTNAME != storagePoolName {
+			return 0, fmt.Errorf("LUN (ID: %d) is migrating to other storage pool (%s)", source.ID, target.PARENTNAME)
+		}
+		return target.ID, nil
+	}
+
+	target, err := d.createMigrationTargetLUN(ctx, source, storagePoolName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create target LUN: %w", err)
+	}
+	if _, err := d.CreateLUNMigration(ctx, source.ID, target.ID, speed); err != nil {
+		if err := d.DeleteLUN(ctx, target.ID); err != nil {
+			d.Logger.Printf("failed to delete target LUN: %v\n", err)
+		}
+		return 0, fmt.Errorf("failed to create LUN Migration: %w", err)
+	}
+
+	return target.ID, nil
+}
@@ -0,0 +1,179 @@
+package dorado
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDevice_CreateLUNMigration(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/MIGRATION", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		testBody(t, r, map[string]interface{}{
+			"TYPE":        "253",
+			"PARENTID":    "148",
+			"TARGETLUNID": "151",
+			"SPEED":       float64(SpeedHigh),
+			"WORKMODE":    float64(0),
+		})
+
+		fmt.Fprint(w, `{"data": {"ID": "148", "PARENTID": "148", "TARGETLUNID": "151", "RUNNINGSTATUS": "75", "PROGRESS": "30", "TYPE": 253}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	lunMigration, err := client.LocalDevice.CreateLUNMigration(context.Background(), 148, 151, SpeedHigh)
+	if err != nil {
+		t.Fatalf("CreateLUNMigration return err: %s", err)
+	}
+	if lunMigration.Status() != LunMigrationStatusMigrating {
+		t.Errorf("Status return %s, want %s", lunMigration.Status(), LunMigrationStatusMigrating)
+	}
+	if lunMigration.Progress() != 30 {
+		t.Errorf("Progress return %d, want %d", lunMigration.Progress(), 30)
+	}
+}
+
+// setupLUNMigration register handlers of LUN 148 that is migrated from pool0 to pool1 (target LUN 200).
+// migrationStatus return RUNNINGSTATUS of LUN Migration, "" is not found.
+func setupLUNMigration(t *testing.T, mux *http.ServeMux, migrationStatus func() string) func() []string {
+	var mu sync.Mutex
+	var calls []string
+	record := func(r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+	}
+
+	mux.HandleFunc("/lun/148", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "148", "ALLOCTYPE": "0", "CAPACITY": "2097152", "PARENTNAME": "pool0", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/200", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			record(r)
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"ID": "200", "HEALTHSTATUS": "%d", "RUNNINGSTATUS": "%d", "ISCLONE": "false", "PARENTNAME": "pool1", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`, StatusHealth, StatusVolumeReady)
+	})
+	mux.HandleFunc("/lun", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		record(r)
+		// target LUN is thick as same as source LUN
+		if body := testDecodeBody(t, r); body != nil && body["ALLOCTYPE"] != float64(0) {
+			t.Errorf("ALLOCTYPE is %v, want 0", body["ALLOCTYPE"])
+		}
+		fmt.Fprint(w, `{"data": {"ID": "200", "PARENTNAME": "pool1", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/storagepool", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "1", "NAME": "pool1"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/MIGRATION", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			if status := migrationStatus(); status != "" {
+				fmt.Fprintf(w, `{"data": [{"ID": "148", "PARENTID": "148", "TARGETLUNID": "200", "RUNNINGSTATUS": "%s", "TYPE": 253}], "error": {"code": 0, "description": "0"}}`, status)
+				return
+			}
+			fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+			return
+		}
+		testMethod(t, r, "POST")
+		record(r)
+		fmt.Fprint(w, `{"data": {"ID": "148", "PARENTID": "148", "TARGETLUNID": "200", "RUNNINGSTATUS": "75", "TYPE": 253}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/MIGRATION/148", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			record(r)
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"ID": "148", "PARENTID": "148", "TARGETLUNID": "200", "RUNNINGSTATUS": "%s", "TYPE": 253}, "error": {"code": 0, "description": "0"}}`, migrationStatus())
+	})
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls
+	}
+}
+
+func TestDevice_MigrateLUNToPool(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	status := ""
+	calls := setupLUNMigration(t, mux, func() string {
+		if status == "" {
+			// created by POST, completed immediately
+			status = "76"
+			return ""
+		}
+		return status
+	})
+
+	if _, err := client.LocalDevice.MigrateLUNToPool(context.Background(), 148, "pool1", SpeedHigh); err != nil {
+		t.Fatalf("MigrateLUNToPool return err: %s", err)
+	}
+
+	want := []string{"POST /lun", "POST /MIGRATION", "DELETE /MIGRATION/148", "DELETE /lun/200"}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("MigrateLUNToPool requests %v, want %v", got, want)
+	}
+}
+
+func TestDevice_MigrateLUNToPool_Canceled(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// LUN Migration is running before call (resume), and ctx is canceled while waiting
+	calls := setupLUNMigration(t, mux, func() string {
+		cancel()
+		return "75"
+	})
+
+	_, err := client.LocalDevice.MigrateLUNToPool(ctx, 148, "pool1", SpeedHigh)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("MigrateLUNToPool return err %v, want %v", err, context.Canceled)
+	}
+	// running migration and target LUN must be kept
+	if got := calls(); len(got) != 0 {
+		t.Errorf("MigrateLUNToPool requests %v, want nothing", got)
+	}
+}
+
+func TestClient_RetypeVolume_Partial(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/HyperMetroPair/3400a30d844d0007", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "3400a30d844d0007", "LOCALOBJID": "148", "REMOTEOBJID": "151"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/148", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "148", "PARENTNAME": "pool1", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/151", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 1077936859, "description": "The LUN does not exist."}}`)
+	})
+
+	err := client.RetypeVolume(context.Background(), "3400a30d844d0007", "pool1", SpeedHigh)
+	var retypeErr *RetypeVolumeError
+	if !errors.As(err, &retypeErr) {
+		t.Fatalf("RetypeVolume return err %v, want *RetypeVolumeError", err)
+	}
+	if !retypeErr.LocalMoved || retypeErr.RemoteMoved {
+		t.Errorf("RetypeVolume return %+v, want only local is moved", retypeErr)
+	}
+}
@@ -84,6 +84,8 @@ func TestDevice_GetLUNs(t *testing.T) {
 			ALLOCCAPACITY:               "0",
 			ALLOCTYPE:                   "1",
 			CAPACITY:                    2097152,
+			CLONESOURCEID:               "2",
+			CLONESOURCETYPE:             "1",
 			COMPRESSION:                 "0",
 			COMPRESSIONSAVEDCAPACITY:    "0",
 			COMPRESSIONSAVEDRATIO:       "0",
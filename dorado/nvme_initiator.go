@@ -0,0 +1,240 @@
+package dorado
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NVMeTransport is transport type of NVMe over Fabrics
+type NVMeTransport int
+
+// NVMeTransport const
+const (
+	NVMeOverRoCE NVMeTransport = iota
+	NVMeOverTCP
+)
+
+// String is function compatible for fmt.Stringer
+func (t NVMeTransport) String() string {
+	switch t {
+	case NVMeOverRoCE:
+		return "rdma"
+	case NVMeOverTCP:
+		return "tcp"
+	default:
+		return ""
+	}
+}
+
+func (t NVMeTransport) initiatorPath() (string, error) {
+	switch t {
+	case NVMeOverRoCE:
+		return "/NVMe_over_RoCE_initiator", nil
+	case NVMeOverTCP:
+		return "/NVMe_over_TCP_initiator", nil
+	default:
+		return "", fmt.Errorf("unknown NVMe transport: %d", t)
+	}
+}
+
+func (t NVMeTransport) initiatorType() int {
+	switch t {
+	case NVMeOverTCP:
+		return TypeNVMeOverTCPInitiator
+	default:
+		return TypeNVMeOverRoCEInitiator
+	}
+}
+
+// NVMe values
+const (
+	MaxNQNLength          = 223
+	DefaultNVMeTargetPort = 4420
+
+	// PrefixNVMeSubsystemNQN is prefix of subsystem NQN, suffix is serial number of device (= System.ID).
+	PrefixNVMeSubsystemNQN = "nqn.2020-02.huawei.nvme:nvm-subsystem-sn-"
+)
+
+// NVMeInitiator is NVMe over Fabrics initiator (keyed by host NQN)
+type NVMeInitiator struct {
+	HEALTHSTATUS  string `json:"HEALTHSTATUS"`
+	ID            string `json:"ID"` // = host NQN
+	ISFREE        bool   `json:"ISFREE,string"`
+	RUNNINGSTATUS string `json:"RUNNINGSTATUS"`
+	TYPE          int    `json:"TYPE"`
+	PARENTID      string `json:"PARENTID,omitempty"`
+	PARENTNAME    string `json:"PARENTNAME,omitempty"`
+	PARENTTYPE    int    `json:"PARENTTYPE,omitempty"`
+}
+
+func validateNQN(nqn string) error {
+	if !strings.HasPrefix(nqn, "nqn.") {
+		return fmt.Errorf("invalid NQN: %s", nqn)
+	}
+	if len(nqn) > MaxNQNLength {
+		return fmt.Errorf("length of NQN must be less than %d (NQN: %s)", MaxNQNLength, nqn)
+	}
+
+	return nil
+}
+
+// GetNVMeInitiators get NVMe initiators by query.
+// you must use encodeIqn when to search NQN.
+func (d *Device) GetNVMeInitiators(ctx context.Context, transport NVMeTransport, query *SearchQuery) ([]NVMeInitiator, error) {
+	spath, err := transport.initiatorPath()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var initiators []NVMeInitiator
+	if err = d.requestWithRetry(req, &initiators, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(initiators) == 0 {
+		return nil, ErrNVMeInitiatorNotFound
+	}
+
+	return initiators, nil
+}
+
+// CreateNVMeInitiator create NVMe initiator.
+func (d *Device) CreateNVMeInitiator(ctx context.Context, transport NVMeTransport, nqn string) (*NVMeInitiator, error) {
+	if err := validateNQN(nqn); err != nil {
+		return nil, err
+	}
+	spath, err := transport.initiatorPath()
+	if err != nil {
+		return nil, err
+	}
+	param := struct {
+		ID string `json:"ID"`
+	}{
+		ID: nqn,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	initiator := &NVMeInitiator{}
+	if err = d.requestWithRetry(req, initiator, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return initiator, nil
+}
+
+// DeleteNVMeInitiator delete NVMe initiator.
+func (d *Device) DeleteNVMeInitiator(ctx context.Context, transport NVMeTransport, nqn string) error {
+	path, err := transport.initiatorPath()
+	if err != nil {
+		return err
+	}
+	spath := fmt.Sprintf("%s/%s", path, nqn)
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// AssociateNVMeInitiator associate NVMe initiator to host.
+func (d *Device) AssociateNVMeInitiator(ctx context.Context, transport NVMeTransport, hostID int, nqn string) error {
+	spath := "/host/create_associate"
+	param := AssociateParam{
+		ID:               strconv.Itoa(hostID),
+		ASSOCIATEOBJID:   nqn,
+		ASSOCIATEOBJTYPE: transport.initiatorType(),
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// GetNVMeInitiatorForce get NVMe initiator and create NVMe initiator if not exists.
+func (d *Device) GetNVMeInitiatorForce(ctx context.Context, transport NVMeTransport, nqn string) (*NVMeInitiator, error) {
+	initiators, err := d.GetNVMeInitiators(ctx, transport, NewSearchQueryID(encodeIqn(nqn)))
+	if err != nil {
+		if err == ErrNVMeInitiatorNotFound {
+			return d.CreateNVMeInitiator(ctx, transport, nqn)
+		}
+
+		return nil, fmt.Errorf("failed to get NVMe initiators: %w", err)
+	}
+
+	if len(initiators) != 1 {
+		return nil, errors.New("found multiple NVMe initiators in same NQN")
+	}
+
+	return &initiators[0], nil
+}
+
+// GetNVMeSubsystemNQN get NQN of NVMe subsystem in device.
+func (d *Device) GetNVMeSubsystemNQN(ctx context.Context) (string, error) {
+	system, err := d.GetSystem(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get system information: %w", err)
+	}
+
+	return PrefixNVMeSubsystemNQN + system.ID, nil
+}
+
+// GetNVMeTransportAddresses get NVMe portal addresses (host:port) that associated port group.
+// use IPv4 and IPv6 addresses and DefaultNVMeTargetPort.
+func (d *Device) GetNVMeTransportAddresses(ctx context.Context, portgroupID int) ([]string, error) {
+	return d.GetNVMeTransportAddressesWithPort(ctx, portgroupID, AddressFamilyBoth, DefaultNVMeTargetPort)
+}
+
+// GetNVMeTransportAddressesWithPort get NVMe portal addresses (host:port) that associated port group.
+// addresses are resolved in the same way as GetPortals (logical ports, or ethernet ports if not found).
+func (d *Device) GetNVMeTransportAddressesWithPort(ctx context.Context, portgroupID int, family AddressFamily, port int) ([]string, error) {
+	// SUPPORTPROTOCOL of NVMe is not checked, logical port of NVMe is selected by ROLE and RUNNINGSTATUS
+	addresses, err := d.getPortGroupPortals(ctx, portgroupID, family, 0, func(EthernetPort) int { return port })
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addresses) == 0 {
+		return nil, errors.New("NVMe transport addresses is not found")
+	}
+
+	return addresses, nil
+}
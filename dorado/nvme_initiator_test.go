@@ -0,0 +1,147 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDevice_GetNVMeInitiators(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/NVMe_over_RoCE_initiator", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w,
+			`
+{
+  "data": [
+    {
+      "HEALTHSTATUS": "1",
+      "ID": "nqn.2014-08.org.nvmexpress:uuid:9b2a6e2e-7b7c-4d6b-9b38-8f3a5d3e1c21",
+      "ISFREE": "false",
+      "PARENTID": "3",
+      "PARENTNAME": "Host001",
+      "PARENTTYPE": 21,
+      "RUNNINGSTATUS": "27",
+      "TYPE": 57870
+    }
+  ],
+  "error": {
+    "code": 0,
+    "description": "0"
+  }
+}`)
+	})
+
+	initiators, err := client.LocalDevice.GetNVMeInitiators(context.Background(), NVMeOverRoCE, nil)
+	if err != nil {
+		t.Errorf("GetNVMeInitiators return err: %s", err)
+	}
+
+	want := []NVMeInitiator{
+		{
+			HEALTHSTATUS:  "1",
+			ID:            "nqn.2014-08.org.nvmexpress:uuid:9b2a6e2e-7b7c-4d6b-9b38-8f3a5d3e1c21",
+			ISFREE:        false,
+			RUNNINGSTATUS: "27",
+			TYPE:          TypeNVMeOverRoCEInitiator,
+			PARENTID:      "3",
+			PARENTNAME:    "Host001",
+			PARENTTYPE:    TypeHost,
+		},
+	}
+
+	if !reflect.DeepEqual(initiators, want) {
+		t.Errorf("GetNVMeInitiators return %+v, want %+v", initiators, want)
+	}
+}
+
+func TestDevice_DeleteNVMeInitiator(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	nqn := "nqn.2014-08.org.nvmexpress:uuid:9b2a6e2e-7b7c-4d6b-9b38-8f3a5d3e1c21"
+	mux.HandleFunc("/NVMe_over_TCP_initiator/"+nqn, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		if r.URL.RawQuery != "" {
+			t.Errorf("query is %s, want empty", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	if err := client.LocalDevice.DeleteNVMeInitiator(context.Background(), NVMeOverTCP, nqn); err != nil {
+		t.Errorf("DeleteNVMeInitiator return err: %s", err)
+	}
+}
+
+func TestDevice_GetNVMeTransportAddresses(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/eth_port/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w,
+			`
+{
+  "data": [
+    {
+      "ID": "131328",
+      "IPV4ADDR": "192.0.2.10",
+      "IPV6ADDR": "2001:db8::10",
+      "TYPE": 213
+    },
+    {
+      "ID": "131329",
+      "IPV4ADDR": "192.0.2.11",
+      "IPV6ADDR": "",
+      "TYPE": 213
+    }
+  ],
+  "error": {
+    "code": 0,
+    "description": "0"
+  }
+}`)
+	})
+
+	logicalPorts := `[]`
+	mux.HandleFunc("/lif", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("filter") != "HOMEPORTID::131329" {
+			fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data": %s, "error": {"code": 0, "description": "0"}}`, logicalPorts)
+	})
+	mux.HandleFunc("/vlan", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+	})
+
+	// logical port is not found: use addresses of ethernet ports
+	addresses, err := client.LocalDevice.GetNVMeTransportAddresses(context.Background(), 1)
+	if err != nil {
+		t.Errorf("GetNVMeTransportAddresses return err: %s", err)
+	}
+
+	want := []string{"192.0.2.10:4420", "[2001:db8::10]:4420", "192.0.2.11:4420"}
+
+	if !reflect.DeepEqual(addresses, want) {
+		t.Errorf("GetNVMeTransportAddresses return %+v, want %+v", addresses, want)
+	}
+
+	// use addresses of logical ports running on ethernet ports in port group
+	logicalPorts = `[{"HOMEPORTID": "131329", "HOMEPORTTYPE": "213", "ID": "0", "IPV4ADDR": "192.0.2.50", "IPV6ADDR": "", "ROLE": "2", "RUNNINGSTATUS": "10", "TYPE": 279}, {"HOMEPORTID": "131329", "HOMEPORTTYPE": "213", "ID": "1", "IPV4ADDR": "192.0.2.51", "IPV6ADDR": "", "ROLE": "1", "RUNNINGSTATUS": "10", "TYPE": 279}]`
+	addresses, err = client.LocalDevice.GetNVMeTransportAddressesWithPort(context.Background(), 1, AddressFamilyIPv4, 4421)
+	if err != nil {
+		t.Errorf("GetNVMeTransportAddressesWithPort return err: %s", err)
+	}
+
+	want = []string{"192.0.2.50:4421"}
+	if !reflect.DeepEqual(addresses, want) {
+		t.Errorf("GetNVMeTransportAddressesWithPort return %+v, want %+v", addresses, want)
+	}
+}
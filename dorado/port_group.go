@@ -1,9 +1,14 @@
 package dorado
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"strconv"
+
+	"github.com/pkg/errors"
 )
 
 // PortGroup is group of Port (ex Ethernet, FiberChannel...)
@@ -53,6 +58,125 @@ func (d *Device) GetPortGroup(ctx context.Context, portgroupID int) (*PortGroup,
 	return portGroup, nil
 }
 
+// CreatePortGroup create port group
+func (d *Device) CreatePortGroup(ctx context.Context, name, description string) (*PortGroup, error) {
+	spath := "/portgroup"
+	if len(name) > MaxNameLength {
+		return nil, fmt.Errorf("length of port group name must be less than %d", MaxNameLength)
+	}
+	param := struct {
+		TYPE        string `json:"TYPE"`
+		NAME        string `json:"NAME"`
+		DESCRIPTION string `json:"DESCRIPTION"`
+	}{
+		TYPE:        strconv.Itoa(TypePortGroup),
+		NAME:        name,
+		DESCRIPTION: description,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	portGroup := &PortGroup{}
+	if err = d.requestWithRetry(req, portGroup, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return portGroup, nil
+}
+
+// DeletePortGroup delete port group
+func (d *Device) DeletePortGroup(ctx context.Context, portgroupID int) error {
+	spath := fmt.Sprintf("/portgroup/%d", portgroupID)
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// AddEthernetPort add ethernet port to port group
+func (d *Device) AddEthernetPort(ctx context.Context, portgroupID int, ethernetPortID string) error {
+	return d.associatePort(ctx, portgroupID, ethernetPortID, TypeEthernetPort)
+}
+
+// RemoveEthernetPort remove ethernet port from port group
+func (d *Device) RemoveEthernetPort(ctx context.Context, portgroupID int, ethernetPortID string) error {
+	return d.disAssociatePort(ctx, portgroupID, ethernetPortID, TypeEthernetPort)
+}
+
+// AddFCPort add FC port to port group
+func (d *Device) AddFCPort(ctx context.Context, portgroupID int, fcPortID string) error {
+	return d.associatePort(ctx, portgroupID, fcPortID, TypeFCPort)
+}
+
+// RemoveFCPort remove FC port from port group
+func (d *Device) RemoveFCPort(ctx context.Context, portgroupID int, fcPortID string) error {
+	return d.disAssociatePort(ctx, portgroupID, fcPortID, TypeFCPort)
+}
+
+func (d *Device) associatePort(ctx context.Context, portgroupID int, portID string, portType int) error {
+	spath := "/portgroup/associate"
+	param := AssociateParam{
+		ID:               strconv.Itoa(portgroupID),
+		TYPE:             strconv.Itoa(TypePortGroup),
+		ASSOCIATEOBJID:   portID,
+		ASSOCIATEOBJTYPE: portType,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+func (d *Device) disAssociatePort(ctx context.Context, portgroupID int, portID string, portType int) error {
+	spath := "/portgroup/associate"
+	param := &AssociateParam{
+		ID:               strconv.Itoa(portgroupID),
+		TYPE:             strconv.Itoa(TypePortGroup),
+		ASSOCIATEOBJID:   portID,
+		ASSOCIATEOBJTYPE: portType,
+	}
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddAssociateParam(req, param)
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
 // GetPortGroupsAssociate get port group that associated by mapping view id
 func (d *Device) GetPortGroupsAssociate(ctx context.Context, mappingviewID int) ([]PortGroup, error) {
 	spath := "/portgroup/associate"
@@ -90,3 +214,30 @@ func (d *Device) IsAddToMappingViewPortGroup(ctx context.Context, mappingViewID,
 
 	return false, nil
 }
+
+// NewHashPortGroupNameFunc create function for Client.PortGroupNameFunc.
+// a host is always assigned to the same port group that selected by rendezvous hashing of hostname,
+// so only hosts in added or removed port group are moved when portgroupNames is changed.
+func NewHashPortGroupNameFunc(portgroupNames []string) (func(hostname string) string, error) {
+	if len(portgroupNames) == 0 {
+		return nil, errors.New("port group names is required")
+	}
+	names := make([]string, len(portgroupNames))
+	copy(names, portgroupNames)
+
+	return func(hostname string) string {
+		var selected string
+		var max uint32
+		for _, name := range names {
+			h := fnv.New32a()
+			h.Write([]byte(name))
+			h.Write([]byte{0})
+			h.Write([]byte(hostname))
+			if weight := h.Sum32(); selected == "" || weight > max {
+				selected = name
+				max = weight
+			}
+		}
+		return selected
+	}, nil
+}
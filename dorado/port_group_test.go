@@ -50,3 +50,152 @@ func TestDevice_GetPortGroups(t *testing.T) {
 		t.Errorf("GetPortGroups return %+v, want %+v", portgroups, want)
 	}
 }
+
+func TestClient_PortGroupNameFunc(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	if got := client.portGroupName("host001"); got != "portgroup" {
+		t.Errorf("portGroupName return %s, want %s", got, "portgroup")
+	}
+
+	names := []string{"PortGroup_A", "PortGroup_B"}
+	f, err := NewHashPortGroupNameFunc(names)
+	if err != nil {
+		t.Fatalf("NewHashPortGroupNameFunc return err: %s", err)
+	}
+	client.PortGroupNameFunc = f
+
+	selected := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		hostname := fmt.Sprintf("host%03d", i)
+		got := client.portGroupName(hostname)
+		if got != client.portGroupName(hostname) {
+			t.Errorf("portGroupName must return same port group in same hostname (hostname: %s)", hostname)
+		}
+		selected[got] = true
+	}
+
+	for _, name := range names {
+		if !selected[name] {
+			t.Errorf("port group %s is never selected", name)
+		}
+	}
+}
+
+func TestNewHashPortGroupNameFunc_AddPortGroup(t *testing.T) {
+	before, err := NewHashPortGroupNameFunc([]string{"PortGroup_A", "PortGroup_B"})
+	if err != nil {
+		t.Fatalf("NewHashPortGroupNameFunc return err: %s", err)
+	}
+	after, err := NewHashPortGroupNameFunc([]string{"PortGroup_A", "PortGroup_B", "PortGroup_C"})
+	if err != nil {
+		t.Fatalf("NewHashPortGroupNameFunc return err: %s", err)
+	}
+
+	moved := 0
+	for i := 0; i < 300; i++ {
+		hostname := fmt.Sprintf("host%03d", i)
+		if b, a := before(hostname), after(hostname); b != a {
+			moved++
+			if a != "PortGroup_C" {
+				t.Errorf("host %s is moved from %s to %s, want to be kept or moved to added port group", hostname, b, a)
+			}
+		}
+	}
+	if moved == 0 {
+		t.Errorf("no host is moved to added port group")
+	}
+}
+
+func TestDevice_CreatePortGroup(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/portgroup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, map[string]interface{}{
+			"TYPE":        "257",
+			"NAME":        "PortGroup_A",
+			"DESCRIPTION": "for host001",
+		})
+		fmt.Fprint(w, `{"data": {"ID": "3", "NAME": "PortGroup_A", "DESCRIPTION": "for host001", "TYPE": 257}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	portgroup, err := client.LocalDevice.CreatePortGroup(context.Background(), "PortGroup_A", "for host001")
+	if err != nil {
+		t.Fatalf("CreatePortGroup return err: %s", err)
+	}
+	want := &PortGroup{ID: 3, NAME: "PortGroup_A", DESCRIPTION: "for host001", TYPE: TypePortGroup}
+	if !reflect.DeepEqual(portgroup, want) {
+		t.Errorf("CreatePortGroup return %+v, want %+v", portgroup, want)
+	}
+}
+
+func TestDevice_DeletePortGroup(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/portgroup/3", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	if err := client.LocalDevice.DeletePortGroup(context.Background(), 3); err != nil {
+		t.Errorf("DeletePortGroup return err: %s", err)
+	}
+}
+
+func TestDevice_AddRemovePort(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var added []map[string]interface{}
+	var removed []map[string]string
+	mux.HandleFunc("/portgroup/associate", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			added = append(added, testDecodeBody(t, r))
+		case "DELETE":
+			q := r.URL.Query()
+			removed = append(removed, map[string]string{
+				"ID":               q.Get("ID"),
+				"TYPE":             q.Get("TYPE"),
+				"ASSOCIATEOBJID":   q.Get("ASSOCIATEOBJID"),
+				"ASSOCIATEOBJTYPE": q.Get("ASSOCIATEOBJTYPE"),
+			})
+		default:
+			t.Errorf("Request method: %v, want POST or DELETE", r.Method)
+		}
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	ctx := context.Background()
+	if err := client.LocalDevice.AddEthernetPort(ctx, 3, "1114112"); err != nil {
+		t.Fatalf("AddEthernetPort return err: %s", err)
+	}
+	if err := client.LocalDevice.AddFCPort(ctx, 3, "2113536"); err != nil {
+		t.Fatalf("AddFCPort return err: %s", err)
+	}
+	if err := client.LocalDevice.RemoveEthernetPort(ctx, 3, "1114112"); err != nil {
+		t.Fatalf("RemoveEthernetPort return err: %s", err)
+	}
+	if err := client.LocalDevice.RemoveFCPort(ctx, 3, "2113536"); err != nil {
+		t.Fatalf("RemoveFCPort return err: %s", err)
+	}
+
+	wantAdded := []map[string]interface{}{
+		{"ID": "3", "TYPE": "257", "ASSOCIATEOBJID": "1114112", "ASSOCIATEOBJTYPE": float64(TypeEthernetPort)},
+		{"ID": "3", "TYPE": "257", "ASSOCIATEOBJID": "2113536", "ASSOCIATEOBJTYPE": float64(TypeFCPort)},
+	}
+	if !reflect.DeepEqual(added, wantAdded) {
+		t.Errorf("add port request %+v, want %+v", added, wantAdded)
+	}
+	wantRemoved := []map[string]string{
+		{"ID": "3", "TYPE": "257", "ASSOCIATEOBJID": "1114112", "ASSOCIATEOBJTYPE": "213"},
+		{"ID": "3", "TYPE": "257", "ASSOCIATEOBJID": "2113536", "ASSOCIATEOBJTYPE": "212"},
+	}
+	if !reflect.DeepEqual(removed, wantRemoved) {
+		t.Errorf("remove port request %+v, want %+v", removed, wantRemoved)
+	}
+}
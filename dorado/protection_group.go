@@ -0,0 +1,224 @@
+package dorado
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// ProtectionGroup is group of LUN for consistency protection (ex: snapshot consistency group)
+type ProtectionGroup struct {
+	DESCRIPTION string `json:"description"`
+	ID          int    `json:"protectGroupId,string"`
+	NAME        string `json:"protectGroupName"`
+	LUNNUM      string `json:"lunNum"`
+	LUNGROUPID  string `json:"lunGroupId"`
+	TYPE        int    `json:"TYPE"`
+}
+
+// GetProtectionGroups get protection groups by query
+func (d *Device) GetProtectionGroups(ctx context.Context, query *SearchQuery) ([]ProtectionGroup, error) {
+	spath := "/protectgroup"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var protectionGroups []ProtectionGroup
+	if err = d.requestWithRetry(req, &protectionGroups, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(protectionGroups) == 0 {
+		return nil, ErrProtectionGroupNotFound
+	}
+
+	return protectionGroups, nil
+}
+
+// GetProtectionGroup get protection group by id
+func (d *Device) GetProtectionGroup(ctx context.Context, protectionGroupID int) (*ProtectionGroup, error) {
+	spath := fmt.Sprintf("/protectgroup/%d", protectionGroupID)
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	protectionGroup := &ProtectionGroup{}
+	if err = d.requestWithRetry(req, protectionGroup, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return protectionGroup, nil
+}
+
+// CreateProtectionGroup create protection group and add LUNs to protection group.
+func (d *Device) CreateProtectionGroup(ctx context.Context, name uuid.UUID, lunIDs []int) (*ProtectionGroup, error) {
+	spath := "/protectgroup"
+	param := struct {
+		NAME        string `json:"protectGroupName"`
+		DESCRIPTION string `json:"description"`
+	}{
+		NAME:        EncodeLunName(name),
+		DESCRIPTION: name.String(),
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	protectionGroup := &ProtectionGroup{}
+	if err = d.requestWithRetry(req, protectionGroup, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	for _, lunID := range lunIDs {
+		if err := d.AddLUNToProtectionGroup(ctx, protectionGroup.ID, lunID); err != nil {
+			if err := d.DeleteProtectionGroup(ctx, protectionGroup.ID); err != nil {
+				d.Logger.Printf("failed to delete protection group: %v\n", err)
+			}
+			return nil, fmt.Errorf("failed to add LUN to protection group (LUN ID: %d): %w", lunID, err)
+		}
+	}
+
+	return d.GetProtectionGroup(ctx, protectionGroup.ID)
+}
+
+// DeleteProtectionGroup delete protection group.
+// all LUNs in protection group are removed before delete.
+func (d *Device) DeleteProtectionGroup(ctx context.Context, protectionGroupID int) error {
+	luns, err := d.GetProtectionGroupLUNs(ctx, protectionGroupID)
+	if err != nil && err != ErrLunNotFound {
+		return fmt.Errorf("failed to get LUNs in protection group: %w", err)
+	}
+	for _, lun := range luns {
+		if err := d.RemoveLUNFromProtectionGroup(ctx, protectionGroupID, lun.ID); err != nil {
+			return fmt.Errorf("failed to remove LUN from protection group (LUN ID: %d): %w", lun.ID, err)
+		}
+	}
+
+	spath := fmt.Sprintf("/protectgroup/%d", protectionGroupID)
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// AddLUNToProtectionGroup add LUN to protection group
+func (d *Device) AddLUNToProtectionGroup(ctx context.Context, protectionGroupID, lunID int) error {
+	spath := "/protectgroup/associate"
+	param := AssociateParam{
+		ID:               strconv.Itoa(protectionGroupID),
+		ASSOCIATEOBJID:   strconv.Itoa(lunID),
+		ASSOCIATEOBJTYPE: TypeLUN,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// RemoveLUNFromProtectionGroup remove LUN from protection group
+func (d *Device) RemoveLUNFromProtectionGroup(ctx context.Context, protectionGroupID, lunID int) error {
+	spath := "/protectgroup/associate"
+	param := &AssociateParam{
+		ID:               strconv.Itoa(protectionGroupID),
+		ASSOCIATEOBJID:   strconv.Itoa(lunID),
+		ASSOCIATEOBJTYPE: TypeLUN,
+	}
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddAssociateParam(req, param)
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// GetProtectionGroupLUNs get LUNs in protection group
+func (d *Device) GetProtectionGroupLUNs(ctx context.Context, protectionGroupID int) ([]LUN, error) {
+	query := &SearchQuery{
+		AssociateObjType: strconv.Itoa(TypeProtectionGroup),
+		AssociateObjID:   strconv.Itoa(protectionGroupID),
+	}
+
+	return d.GetAssociateLUNs(ctx, query)
+}
+
+// GetAssociateProtectionGroups get associated protection groups by query
+func (d *Device) GetAssociateProtectionGroups(ctx context.Context, query *SearchQuery) ([]ProtectionGroup, error) {
+	spath := "/protectgroup/associate"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var protectionGroups []ProtectionGroup
+	if err = d.requestWithRetry(req, &protectionGroups, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(protectionGroups) == 0 {
+		return nil, ErrProtectionGroupNotFound
+	}
+
+	return protectionGroups, nil
+}
+
+// GetProtectionGroupByLUNID get protection group that has LUN. a LUN can belong to only one protection group.
+func (d *Device) GetProtectionGroupByLUNID(ctx context.Context, lunID int) (*ProtectionGroup, error) {
+	query := &SearchQuery{
+		AssociateObjType: strconv.Itoa(TypeLUN),
+		AssociateObjID:   strconv.Itoa(lunID),
+	}
+
+	protectionGroups, err := d.GetAssociateProtectionGroups(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(protectionGroups) != 1 {
+		return nil, fmt.Errorf("found multiple protection groups that has LUN (ID: %d)", lunID)
+	}
+
+	return &protectionGroups[0], nil
+}
@@ -0,0 +1,112 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// QuorumServer is quorum server for arbitration of HyperMetroDomain
+type QuorumServer struct {
+	HEALTHSTATUS  string `json:"HEALTHSTATUS"`
+	ID            string `json:"ID"`
+	NAME          string `json:"NAME"`
+	PORT          string `json:"PORT"`
+	RUNNINGSTATUS string `json:"RUNNINGSTATUS"`
+	SERVERIP      string `json:"SERVERIP"`
+	TYPE          int    `json:"TYPE"`
+}
+
+// QuorumServerLink is link between device and quorum server
+type QuorumServerLink struct {
+	ID          string `json:"ID"`
+	LINKSTATUS  string `json:"LINKSTATUS"`
+	LOCALIP     string `json:"LOCALIP"`
+	PARENTID    string `json:"PARENTID"` // = QuorumServer.ID
+	PARENTNAME  string `json:"PARENTNAME"`
+	REMOTEIP    string `json:"REMOTEIP"`
+	REMOTEPORT  string `json:"REMOTEPORT"`
+	TYPE        int    `json:"TYPE"`
+	DESCRIPTION string `json:"DESCRIPTION"`
+}
+
+// IsUp return true if link is up
+func (l *QuorumServerLink) IsUp() bool {
+	return l.LINKSTATUS == strconv.Itoa(StatusLinkUp)
+}
+
+// GetQuorumServers get quorum servers by query
+func (d *Device) GetQuorumServers(ctx context.Context, query *SearchQuery) ([]QuorumServer, error) {
+	spath := "/QuorumServer"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var quorumServers []QuorumServer
+	if err = d.requestWithRetry(req, &quorumServers, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(quorumServers) == 0 {
+		return nil, ErrQuorumServerNotFound
+	}
+
+	return quorumServers, nil
+}
+
+// GetQuorumServerLinks get links to quorum servers by query
+func (d *Device) GetQuorumServerLinks(ctx context.Context, query *SearchQuery) ([]QuorumServerLink, error) {
+	spath := "/QuorumServerLink"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var links []QuorumServerLink
+	if err = d.requestWithRetry(req, &links, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(links) == 0 {
+		return nil, ErrQuorumServerLinkNotFound
+	}
+
+	return links, nil
+}
+
+// GetQuorumServerLinksByServerID get links to a quorum server
+func (d *Device) GetQuorumServerLinksByServerID(ctx context.Context, quorumServerID string) ([]QuorumServerLink, error) {
+	return d.GetQuorumServerLinks(ctx, &SearchQuery{Filter: ToFilter("PARENTID", quorumServerID)})
+}
+
+// quorumServerIsReachable return true if device has a link that is up to quorum server.
+func (d *Device) quorumServerIsReachable(ctx context.Context, quorumServerName string) (bool, error) {
+	quorumServers, err := d.GetQuorumServers(ctx, NewSearchQueryName(quorumServerName))
+	if err == ErrQuorumServerNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get quorum servers: %w", err)
+	}
+
+	for _, quorumServer := range quorumServers {
+		links, err := d.GetQuorumServerLinksByServerID(ctx, quorumServer.ID)
+		if err == ErrQuorumServerLinkNotFound {
+			continue
+		} else if err != nil {
+			return false, fmt.Errorf("failed to get quorum server links: %w", err)
+		}
+
+		for _, link := range links {
+			if link.IsUp() {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
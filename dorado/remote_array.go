@@ -0,0 +1,165 @@
+package dorado
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// RemoteArray is other storage array that registered in device (remote_device object).
+// it is used by cross-array features (ex: remote LUN copy, remote replication).
+type RemoteArray struct {
+	ARRAYTYPE     string `json:"ARRAYTYPE"`
+	HEALTHSTATUS  string `json:"HEALTHSTATUS"`
+	ID            string `json:"ID"`
+	LINKTYPE      string `json:"LINKTYPE"`
+	NAME          string `json:"NAME"`
+	RUNNINGSTATUS string `json:"RUNNINGSTATUS"`
+	SN            string `json:"SN"` // = System.ID of remote array
+	TYPE          int    `json:"TYPE"`
+	WWN           string `json:"WWN"`
+}
+
+// RemoteArray LINKTYPE
+const (
+	RemoteLinkTypeFC    = 1
+	RemoteLinkTypeISCSI = 2
+)
+
+// RegisterRemoteArrayParam is parameter for RegisterRemoteArray
+type RegisterRemoteArrayParam struct {
+	NAME     string `json:"NAME,omitempty"`
+	LINKTYPE int    `json:"LINKTYPE"`
+	REMOTEIP string `json:"REMOTEIP,omitempty"` // required if LINKTYPE is iSCSI
+	USERNAME string `json:"USERNAME"`
+	PASSWORD string `json:"PASSWORD"`
+}
+
+// GetRemoteArrays get remote arrays by query
+func (d *Device) GetRemoteArrays(ctx context.Context, query *SearchQuery) ([]RemoteArray, error) {
+	spath := "/remote_device"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var remoteArrays []RemoteArray
+	if err = d.requestWithRetry(req, &remoteArrays, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(remoteArrays) == 0 {
+		return nil, ErrRemoteArrayNotFound
+	}
+
+	return remoteArrays, nil
+}
+
+// GetRemoteArray get remote array by id
+func (d *Device) GetRemoteArray(ctx context.Context, remoteArrayID string) (*RemoteArray, error) {
+	spath := fmt.Sprintf("/remote_device/%s", remoteArrayID)
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	remoteArray := &RemoteArray{}
+	if err = d.requestWithRetry(req, remoteArray, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return remoteArray, nil
+}
+
+// GetRemoteArrayBySN get remote array by serial number (= System.ID of remote array)
+func (d *Device) GetRemoteArrayBySN(ctx context.Context, sn string) (*RemoteArray, error) {
+	remoteArrays, err := d.GetRemoteArrays(ctx, &SearchQuery{Filter: ToFilter("SN", sn)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote arrays: %w", err)
+	}
+	if len(remoteArrays) != 1 {
+		return nil, errors.New("found multiple remote arrays in same SN")
+	}
+
+	return &remoteArrays[0], nil
+}
+
+// DiscoverRemoteArray get remote array that is other device.
+// other device must be registered in d (ex: by RegisterRemoteArray).
+func (d *Device) DiscoverRemoteArray(ctx context.Context, other *Device) (*RemoteArray, error) {
+	system, err := other.GetSystem(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system of other device: %w", err)
+	}
+	remoteArray, err := d.GetRemoteArrayBySN(ctx, system.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote array (SN: %s): %w", system.ID, err)
+	}
+
+	return remoteArray, nil
+}
+
+// RegisterRemoteArray register other storage array as remote device
+func (d *Device) RegisterRemoteArray(ctx context.Context, param RegisterRemoteArrayParam) (*RemoteArray, error) {
+	spath := "/remote_device"
+
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	remoteArray := &RemoteArray{}
+	if err = d.requestWithRetry(req, remoteArray, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return remoteArray, nil
+}
+
+// DeleteRemoteArray delete registered remote array
+func (d *Device) DeleteRemoteArray(ctx context.Context, remoteArrayID string) error {
+	spath := fmt.Sprintf("/remote_device/%s", remoteArrayID)
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// remoteLunCopyLUNDescriptor return LUN descriptor of SOURCELUN or TARGETLUN in remote array.
+// format: <remote device ID>;<LUN ID>;<remote device WWN>;<LUN WWN>;INVALID
+func remoteLunCopyLUNDescriptor(remoteArray *RemoteArray, lun *LUN) string {
+	return fmt.Sprintf("%s;%d;%s;%s;INVALID", remoteArray.ID, lun.ID, remoteArray.WWN, lun.WWN)
+}
+
+// CreateRemoteLUNCopy create lun copy definition of local source lun to remote target luns.
+func (d *Device) CreateRemoteLUNCopy(ctx context.Context, sourceLUNID int, remoteArray *RemoteArray, targetLUNs []LUN, opts *LunCopyOptions) (*LunCopy, error) {
+	if len(targetLUNs) == 0 {
+		return nil, errors.New("target LUNs is empty")
+	}
+
+	var targets []string
+	for i := range targetLUNs {
+		targets = append(targets, remoteLunCopyLUNDescriptor(remoteArray, &targetLUNs[i]))
+	}
+
+	return d.createLUNCopy(ctx, lunCopyLUNDescriptor(sourceLUNID), targets, fmt.Sprintf("LUNCopy_%d_%s_%d", sourceLUNID, remoteArray.ID, targetLUNs[0].ID), opts)
+}
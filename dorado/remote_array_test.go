@@ -0,0 +1,114 @@
+package dorado
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDevice_RegisterRemoteArray(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/remote_device", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, map[string]interface{}{
+			"NAME":     "dst",
+			"LINKTYPE": float64(RemoteLinkTypeISCSI),
+			"REMOTEIP": "192.0.2.100",
+			"USERNAME": "username",
+			"PASSWORD": "password",
+		})
+		fmt.Fprint(w, `{"data": {"ARRAYTYPE": "1", "HEALTHSTATUS": "1", "ID": "0", "LINKTYPE": "2", "NAME": "dst", "RUNNINGSTATUS": "10", "SN": "2102351234", "TYPE": 224, "WWN": "2100e0cc7b000000"}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	param := RegisterRemoteArrayParam{
+		NAME:     "dst",
+		LINKTYPE: RemoteLinkTypeISCSI,
+		REMOTEIP: "192.0.2.100",
+		USERNAME: "username",
+		PASSWORD: "password",
+	}
+	remoteArray, err := client.LocalDevice.RegisterRemoteArray(context.Background(), param)
+	if err != nil {
+		t.Fatalf("RegisterRemoteArray return err: %s", err)
+	}
+
+	want := &RemoteArray{
+		ARRAYTYPE:     "1",
+		HEALTHSTATUS:  "1",
+		ID:            "0",
+		LINKTYPE:      "2",
+		NAME:          "dst",
+		RUNNINGSTATUS: "10",
+		SN:            "2102351234",
+		TYPE:          224,
+		WWN:           "2100e0cc7b000000",
+	}
+	if !reflect.DeepEqual(remoteArray, want) {
+		t.Errorf("RegisterRemoteArray return %+v, want %+v", remoteArray, want)
+	}
+}
+
+func TestDevice_GetRemoteArrayBySN(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/remote_device", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("filter") {
+		case "SN::2102351234":
+			fmt.Fprint(w, `{"data": [{"ID": "0", "SN": "2102351234", "WWN": "2100e0cc7b000000"}], "error": {"code": 0, "description": "0"}}`)
+		case "SN::2102355678":
+			fmt.Fprint(w, `{"data": [{"ID": "1", "SN": "2102355678"}, {"ID": "2", "SN": "2102355678"}], "error": {"code": 0, "description": "0"}}`)
+		default:
+			fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+		}
+	})
+
+	remoteArray, err := client.LocalDevice.GetRemoteArrayBySN(context.Background(), "2102351234")
+	if err != nil {
+		t.Fatalf("GetRemoteArrayBySN return err: %s", err)
+	}
+	if remoteArray.ID != "0" || remoteArray.WWN != "2100e0cc7b000000" {
+		t.Errorf("GetRemoteArrayBySN return %+v", remoteArray)
+	}
+
+	if _, err := client.LocalDevice.GetRemoteArrayBySN(context.Background(), "2102355678"); err == nil {
+		t.Errorf("GetRemoteArrayBySN must return err if found multiple remote arrays")
+	}
+
+	if _, err := client.LocalDevice.GetRemoteArrayBySN(context.Background(), "2102359999"); !errors.Is(err, ErrRemoteArrayNotFound) {
+		t.Errorf("GetRemoteArrayBySN return err %v, want %v", err, ErrRemoteArrayNotFound)
+	}
+}
+
+func TestDevice_DiscoverRemoteArray(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	other, otherMux, _, otherTeardown := setup()
+	defer otherTeardown()
+
+	otherMux.HandleFunc("/system/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "2102351234", "TYPE": 201}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/remote_device", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("filter"); got != "SN::2102351234" {
+			t.Errorf("filter is %s, want SN::2102351234", got)
+		}
+		fmt.Fprint(w, `{"data": [{"ID": "0", "SN": "2102351234", "WWN": "2100e0cc7b000000"}], "error": {"code": 0, "description": "0"}}`)
+	})
+
+	remoteArray, err := client.LocalDevice.DiscoverRemoteArray(context.Background(), other.LocalDevice)
+	if err != nil {
+		t.Fatalf("DiscoverRemoteArray return err: %s", err)
+	}
+	if remoteArray.ID != "0" || remoteArray.SN != "2102351234" {
+		t.Errorf("DiscoverRemoteArray return %+v", remoteArray)
+	}
+}
@@ -0,0 +1,341 @@
+package dorado
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RemoteReplicationPair is object of LUN (replicated to remote array)
+type RemoteReplicationPair struct {
+	ENDTIME             string `json:"ENDTIME"`
+	HEALTHSTATUS        string `json:"HEALTHSTATUS"`
+	ID                  string `json:"ID"`
+	ISPRIMARY           string `json:"ISPRIMARY"`
+	LOCALRESID          int    `json:"LOCALRESID,string"`
+	LOCALRESNAME        string `json:"LOCALRESNAME"`
+	LOCALRESTYPE        string `json:"LOCALRESTYPE"`
+	RECOVERYPOLICY      string `json:"RECOVERYPOLICY"`
+	REMOTEDEVICEID      string `json:"REMOTEDEVICEID"`
+	REMOTEDEVICENAME    string `json:"REMOTEDEVICENAME"`
+	REMOTEDEVICESN      string `json:"REMOTEDEVICESN"`
+	REMOTERESID         int    `json:"REMOTERESID,string"`
+	REMOTERESNAME       string `json:"REMOTERESNAME"`
+	REPLICATIONMODEL    string `json:"REPLICATIONMODEL"`
+	REPLICATIONPROGRESS string `json:"REPLICATIONPROGRESS"`
+	RUNNINGSTATUS       string `json:"RUNNINGSTATUS"`
+	SECRESACCESS        string `json:"SECRESACCESS"`
+	SECRESDATASTATUS    string `json:"SECRESDATASTATUS"`
+	SPEED               string `json:"SPEED"`
+	STARTTIME           string `json:"STARTTIME"`
+	SYNCHRONIZETYPE     string `json:"SYNCHRONIZETYPE"`
+	TIMINGVAL           string `json:"TIMINGVAL"`
+	TYPE                int    `json:"TYPE"`
+}
+
+// ReplicationModel is REPLICATIONMODEL of RemoteReplicationPair
+type ReplicationModel int
+
+// ReplicationModel const
+const (
+	ReplicationSync  ReplicationModel = 1
+	ReplicationAsync ReplicationModel = 2
+)
+
+// ReplicationSyncType is SYNCHRONIZETYPE of asynchronous RemoteReplicationPair
+type ReplicationSyncType int
+
+// ReplicationSyncType const
+const (
+	// ReplicationSyncManual sync only by SyncRemoteReplicationPair
+	ReplicationSyncManual ReplicationSyncType = 1
+	// ReplicationSyncAfterStart sync every interval from start of last sync
+	ReplicationSyncAfterStart ReplicationSyncType = 2
+	// ReplicationSyncAfterEnd sync every interval from end of last sync
+	ReplicationSyncAfterEnd ReplicationSyncType = 3
+)
+
+// ReplicationRecoveryPolicy is RECOVERYPOLICY of RemoteReplicationPair
+type ReplicationRecoveryPolicy int
+
+// ReplicationRecoveryPolicy const
+const (
+	ReplicationRecoveryAuto   ReplicationRecoveryPolicy = 1
+	ReplicationRecoveryManual ReplicationRecoveryPolicy = 2
+)
+
+// RemoteReplicationPairState is RUNNINGSTATUS of RemoteReplicationPair
+type RemoteReplicationPairState int
+
+// RemoteReplicationPairState const
+const (
+	ReplicationStateNormal        RemoteReplicationPairState = 1
+	ReplicationStateSynchronizing RemoteReplicationPairState = 23
+	ReplicationStateSplit         RemoteReplicationPairState = 26
+	ReplicationStateToBeRecovered RemoteReplicationPairState = 33
+	ReplicationStateInterrupted   RemoteReplicationPairState = 34
+	ReplicationStateInvalid       RemoteReplicationPairState = 35
+)
+
+// String is function compatible for fmt.Stringer
+func (s RemoteReplicationPairState) String() string {
+	switch s {
+	case ReplicationStateNormal:
+		return "Normal"
+	case ReplicationStateSynchronizing:
+		return "Synchronizing"
+	case ReplicationStateSplit:
+		return "Split"
+	case ReplicationStateToBeRecovered:
+		return "ToBeRecovered"
+	case ReplicationStateInterrupted:
+		return "Interrupted"
+	case ReplicationStateInvalid:
+		return "Invalid"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int(s))
+	}
+}
+
+// State return RUNNINGSTATUS of RemoteReplicationPair
+func (rrp *RemoteReplicationPair) State() RemoteReplicationPairState {
+	state, err := strconv.Atoi(rrp.RUNNINGSTATUS)
+	if err != nil {
+		return 0
+	}
+	return RemoteReplicationPairState(state)
+}
+
+// SECRESACCESS values of RemoteReplicationPair
+const (
+	secondaryAccessReadOnly  = "2"
+	secondaryAccessReadWrite = "3"
+)
+
+// IsSecondaryWriteProtected return true if secondary LUN is read-only
+func (rrp *RemoteReplicationPair) IsSecondaryWriteProtected() bool {
+	return rrp.SECRESACCESS != secondaryAccessReadWrite
+}
+
+// ReplicationOption is option of CreateRemoteReplicationPair.
+// zero fields are filled by NewReplicationOption.
+type ReplicationOption struct {
+	Model          ReplicationModel
+	SyncType       ReplicationSyncType
+	Interval       time.Duration // = RPO, only for ReplicationSyncAfterStart and ReplicationSyncAfterEnd
+	RecoveryPolicy ReplicationRecoveryPolicy
+	Speed          Speed
+	Compress       bool
+}
+
+// NewReplicationOption create default ReplicationOption (asynchronous, sync every 10 minutes)
+func NewReplicationOption() *ReplicationOption {
+	return &ReplicationOption{
+		Model:          ReplicationAsync,
+		SyncType:       ReplicationSyncAfterStart,
+		Interval:       10 * time.Minute,
+		RecoveryPolicy: ReplicationRecoveryAuto,
+		Speed:          SpeedMedium,
+		Compress:       false,
+	}
+}
+
+func (o *ReplicationOption) withDefault() *ReplicationOption {
+	opt := NewReplicationOption()
+	if o == nil {
+		return opt
+	}
+
+	opt.Compress = o.Compress
+	if o.Model != 0 {
+		opt.Model = o.Model
+	}
+	if o.SyncType != 0 {
+		opt.SyncType = o.SyncType
+	}
+	if o.Interval > 0 {
+		opt.Interval = o.Interval
+	}
+	if o.RecoveryPolicy != 0 {
+		opt.RecoveryPolicy = o.RecoveryPolicy
+	}
+	if o.Speed != 0 {
+		opt.Speed = o.Speed
+	}
+	return opt
+}
+
+// GetRemoteReplicationPairs get RemoteReplicationPairs by query
+func (d *Device) GetRemoteReplicationPairs(ctx context.Context, query *SearchQuery) ([]RemoteReplicationPair, error) {
+	spath := "/REPLICATIONPAIR"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var pairs []RemoteReplicationPair
+	if err = d.requestWithRetry(req, &pairs, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(pairs) == 0 {
+		return nil, ErrRemoteReplicationPairNotFound
+	}
+
+	return pairs, nil
+}
+
+// GetRemoteReplicationPair get RemoteReplicationPair by id
+func (d *Device) GetRemoteReplicationPair(ctx context.Context, pairID string) (*RemoteReplicationPair, error) {
+	spath := fmt.Sprintf("/REPLICATIONPAIR/%s", pairID)
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	pair := &RemoteReplicationPair{}
+	if err = d.requestWithRetry(req, pair, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return pair, nil
+}
+
+// GetRemoteReplicationPairByLUNID get RemoteReplicationPair that local LUN is lunID
+func (d *Device) GetRemoteReplicationPairByLUNID(ctx context.Context, lunID int) (*RemoteReplicationPair, error) {
+	pairs, err := d.GetRemoteReplicationPairs(ctx, &SearchQuery{Filter: ToFilter("LOCALRESID", strconv.Itoa(lunID))})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RemoteReplicationPairs: %w", err)
+	}
+	if len(pairs) != 1 {
+		return nil, fmt.Errorf("found multiple RemoteReplicationPairs in same LUN (ID: %d)", lunID)
+	}
+
+	return &pairs[0], nil
+}
+
+// CreateRemoteReplicationPair create RemoteReplicationPair from local LUN to LUN in remote array.
+// data is not synchronized until SyncRemoteReplicationPair is called.
+func (d *Device) CreateRemoteReplicationPair(ctx context.Context, localLUNID int, remoteArray *RemoteArray, remoteLUNID int, opt *ReplicationOption) (*RemoteReplicationPair, error) {
+	opt = opt.withDefault()
+
+	spath := "/REPLICATIONPAIR"
+	param := struct {
+		LOCALRESID       string `json:"LOCALRESID"`
+		LOCALRESTYPE     int    `json:"LOCALRESTYPE"`
+		REMOTEDEVICEID   string `json:"REMOTEDEVICEID"`
+		REMOTERESID      string `json:"REMOTERESID"`
+		REPLICATIONMODEL int    `json:"REPLICATIONMODEL"`
+		SYNCHRONIZETYPE  int    `json:"SYNCHRONIZETYPE,omitempty"`
+		TIMINGVAL        int    `json:"TIMINGVAL,omitempty"`
+		RECOVERYPOLICY   int    `json:"RECOVERYPOLICY"`
+		SPEED            int    `json:"SPEED"`
+		ENABLECOMPRESS   bool   `json:"ENABLECOMPRESS"`
+	}{
+		LOCALRESID:       strconv.Itoa(localLUNID),
+		LOCALRESTYPE:     TypeLUN,
+		REMOTEDEVICEID:   remoteArray.ID,
+		REMOTERESID:      strconv.Itoa(remoteLUNID),
+		REPLICATIONMODEL: int(opt.Model),
+		RECOVERYPOLICY:   int(opt.RecoveryPolicy),
+		SPEED:            int(opt.Speed),
+		ENABLECOMPRESS:   opt.Compress,
+	}
+	if opt.Model == ReplicationAsync {
+		param.SYNCHRONIZETYPE = int(opt.SyncType)
+		if opt.SyncType != ReplicationSyncManual {
+			param.TIMINGVAL = int(opt.Interval / time.Second)
+		}
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	pair := &RemoteReplicationPair{}
+	if err = d.requestWithRetry(req, pair, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return pair, nil
+}
+
+// DeleteRemoteReplicationPair delete RemoteReplicationPair.
+// must be split RemoteReplicationPair before call this method.
+func (d *Device) DeleteRemoteReplicationPair(ctx context.Context, pairID string) error {
+	spath := fmt.Sprintf("/REPLICATIONPAIR/%s", pairID)
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// SplitRemoteReplicationPair stop replication.
+func (d *Device) SplitRemoteReplicationPair(ctx context.Context, pairID string) error {
+	return d.putRemoteReplicationPairAction(ctx, "/REPLICATIONPAIR/split", pairID)
+}
+
+// SyncRemoteReplicationPair start to sync from primary LUN to secondary LUN.
+func (d *Device) SyncRemoteReplicationPair(ctx context.Context, pairID string) error {
+	return d.putRemoteReplicationPairAction(ctx, "/REPLICATIONPAIR/sync", pairID)
+}
+
+// SwitchRemoteReplicationPair swap primary LUN and secondary LUN.
+// RemoteReplicationPair must be split.
+func (d *Device) SwitchRemoteReplicationPair(ctx context.Context, pairID string) error {
+	return d.putRemoteReplicationPairAction(ctx, "/REPLICATIONPAIR/switch", pairID)
+}
+
+// SetSecondaryWriteProtect set secondary LUN to read-only (enable) or read-write (disable).
+// RemoteReplicationPair must be split to disable write protect.
+func (d *Device) SetSecondaryWriteProtect(ctx context.Context, pairID string, enable bool) error {
+	if enable {
+		return d.putRemoteReplicationPairAction(ctx, "/REPLICATIONPAIR/SET_SECODARY_WRITE_LOCK", pairID)
+	}
+	return d.putRemoteReplicationPairAction(ctx, "/REPLICATIONPAIR/CANCEL_SECODARY_WRITE_LOCK", pairID)
+}
+
+func (d *Device) putRemoteReplicationPairAction(ctx context.Context, spath string, pairID string) error {
+	param := struct {
+		ID   string `json:"ID"`
+		TYPE string `json:"TYPE"`
+	}{
+		ID:   pairID,
+		TYPE: strconv.Itoa(TypeRemoteReplicationPair),
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
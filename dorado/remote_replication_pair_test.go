@@ -0,0 +1,66 @@
+package dorado
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestDevice_CreateRemoteReplicationPair(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/REPLICATIONPAIR", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		testBody(t, r, map[string]interface{}{
+			"LOCALRESID":       "148",
+			"LOCALRESTYPE":     float64(TypeLUN),
+			"REMOTEDEVICEID":   "0",
+			"REMOTERESID":      "151",
+			"REPLICATIONMODEL": float64(ReplicationAsync),
+			"SYNCHRONIZETYPE":  float64(ReplicationSyncAfterEnd),
+			"TIMINGVAL":        float64(300),
+			"RECOVERYPOLICY":   float64(ReplicationRecoveryAuto),
+			"SPEED":            float64(SpeedMedium),
+			"ENABLECOMPRESS":   true,
+		})
+
+		fmt.Fprint(w, `{"data": {"ID": "6e4c2d1100eaf02c0001", "LOCALRESID": "148", "REMOTERESID": "151", "RUNNINGSTATUS": "26", "SECRESACCESS": "2", "TYPE": 263}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	// zero fields are filled by default values
+	opt := &ReplicationOption{
+		SyncType: ReplicationSyncAfterEnd,
+		Interval: 5 * time.Minute,
+		Compress: true,
+	}
+	rrp, err := client.LocalDevice.CreateRemoteReplicationPair(context.Background(), 148, &RemoteArray{ID: "0"}, 151, opt)
+	if err != nil {
+		t.Fatalf("CreateRemoteReplicationPair return err: %s", err)
+	}
+	if rrp.State() != ReplicationStateSplit || !rrp.IsSecondaryWriteProtected() {
+		t.Errorf("CreateRemoteReplicationPair return %+v", rrp)
+	}
+}
+
+func TestClient_CreateReplicatedVolume_ProtectionMode(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.CreateReplicatedVolume(context.Background(), uuid.NewV4(), 10, "pool")
+	if !errors.Is(err, ErrInvalidProtectionMode) {
+		t.Errorf("CreateReplicatedVolume return err %v, want %v", err, ErrInvalidProtectionMode)
+	}
+
+	client.ProtectionMode = ProtectionAsyncReplication
+	_, err = client.CreateVolumeRaw(context.Background(), uuid.NewV4(), 10, "pool", "domain")
+	if !errors.Is(err, ErrInvalidProtectionMode) {
+		t.Errorf("CreateVolumeRaw return err %v, want %v", err, ErrInvalidProtectionMode)
+	}
+}
@@ -0,0 +1,202 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// ProtectionMode is how volumes are protected between LocalDevice and RemoteDevice
+type ProtectionMode int
+
+// ProtectionMode const
+const (
+	// ProtectionHyperMetro is that volume is HyperMetroPair (active-active)
+	ProtectionHyperMetro ProtectionMode = iota
+	// ProtectionAsyncReplication is that volume is RemoteReplicationPair from LocalDevice to RemoteDevice (DR site).
+	// use CreateReplicatedVolume instead of CreateVolumeRaw.
+	ProtectionAsyncReplication
+)
+
+// String is function compatible for fmt.Stringer
+func (m ProtectionMode) String() string {
+	switch m {
+	case ProtectionHyperMetro:
+		return "HyperMetro"
+	case ProtectionAsyncReplication:
+		return "AsyncReplication"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int(m))
+	}
+}
+
+// requireProtectionMode return error if ProtectionMode of client is not mode
+func (c *Client) requireProtectionMode(mode ProtectionMode) error {
+	if c.ProtectionMode != mode {
+		return fmt.Errorf("%w: client is %s mode, but %s is required", ErrInvalidProtectionMode, c.ProtectionMode, mode)
+	}
+	return nil
+}
+
+// replicationPrimaryDevice return device and LUN ID that is primary in RemoteReplicationPair.
+func (c *Client) replicationPrimaryDevice(rrp *RemoteReplicationPair) (*Device, int, bool) {
+	if rrp.ISPRIMARY == "true" {
+		return c.LocalDevice, rrp.LOCALRESID, true
+	}
+
+	return c.RemoteDevice, rrp.REMOTERESID, false
+}
+
+// CreateReplicatedVolume create blank LUNs and RemoteReplicationPair from LocalDevice to RemoteDevice.
+// ReplicationOption of client is used, and initial sync is started.
+func (c *Client) CreateReplicatedVolume(ctx context.Context, name uuid.UUID, capacityGB int, storagePoolName string) (*RemoteReplicationPair, error) {
+	if err := c.requireProtectionMode(ProtectionAsyncReplication); err != nil {
+		return nil, err
+	}
+
+	remoteArray, err := c.LocalDevice.DiscoverRemoteArray(ctx, c.RemoteDevice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover remote array of remote device: %w", err)
+	}
+
+	localLUN, err := c.LocalDevice.CreateLUNWithWait(ctx, name, capacityGB, storagePoolName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lun in local device: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if err := c.LocalDevice.DeleteLUN(ctx, localLUN.ID); err != nil {
+				c.LocalDevice.Logger.Printf("failed to delete LUN: %v", err)
+			}
+		}
+	}()
+
+	remoteLUN, err := c.RemoteDevice.CreateLUNWithWait(ctx, name, capacityGB, storagePoolName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lun in remote device: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if err := c.RemoteDevice.DeleteLUN(ctx, remoteLUN.ID); err != nil {
+				c.RemoteDevice.Logger.Printf("failed to delete LUN: %v", err)
+			}
+		}
+	}()
+
+	rrp, err := c.LocalDevice.CreateRemoteReplicationPair(ctx, localLUN.ID, remoteArray, remoteLUN.ID, c.ReplicationOption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RemoteReplicationPair: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if err := c.LocalDevice.DeleteRemoteReplicationPair(ctx, rrp.ID); err != nil {
+				c.LocalDevice.Logger.Printf("failed to delete RemoteReplicationPair: %v", err)
+			}
+		}
+	}()
+
+	if err = c.LocalDevice.SyncRemoteReplicationPair(ctx, rrp.ID); err != nil {
+		return nil, fmt.Errorf("failed to start initial sync: %w", err)
+	}
+
+	return c.LocalDevice.GetRemoteReplicationPair(ctx, rrp.ID)
+}
+
+// GetReplicatedVolume get RemoteReplicationPair by name of volume
+func (c *Client) GetReplicatedVolume(ctx context.Context, name uuid.UUID) (*RemoteReplicationPair, error) {
+	luns, err := c.LocalDevice.GetLUNs(ctx, NewSearchQueryName(EncodeLunName(name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LUN: %w", err)
+	}
+	if len(luns) != 1 {
+		return nil, fmt.Errorf("found multiple LUNs in same name (name: %s)", EncodeLunName(name))
+	}
+
+	return c.LocalDevice.GetRemoteReplicationPairByLUNID(ctx, luns[0].ID)
+}
+
+// AttachReplicatedVolume create mapping from primary LUN of RemoteReplicationPair to host
+func (c *Client) AttachReplicatedVolume(ctx context.Context, pairID, hostname, iqn string) error {
+	rrp, err := c.LocalDevice.GetRemoteReplicationPair(ctx, pairID)
+	if err != nil {
+		return fmt.Errorf("failed to get RemoteReplicationPair: %w", err)
+	}
+	d, lunID, _ := c.replicationPrimaryDevice(rrp)
+
+	if err := d.AttachVolume(ctx, c.portGroupName(hostname), hostname, iqn, lunID); err != nil {
+		return fmt.Errorf("failed to attach primary LUN: %w", err)
+	}
+
+	return nil
+}
+
+// DetachReplicatedVolume delete mapping of LUNs of RemoteReplicationPair in both devices
+func (c *Client) DetachReplicatedVolume(ctx context.Context, pairID string) error {
+	rrp, err := c.LocalDevice.GetRemoteReplicationPair(ctx, pairID)
+	if err != nil {
+		return fmt.Errorf("failed to get RemoteReplicationPair: %w", err)
+	}
+
+	return c.detachReplicatedLUNs(ctx, rrp)
+}
+
+func (c *Client) detachReplicatedLUNs(ctx context.Context, rrp *RemoteReplicationPair) error {
+	for _, side := range []struct {
+		device *Device
+		lunID  int
+	}{
+		{device: c.LocalDevice, lunID: rrp.LOCALRESID},
+		{device: c.RemoteDevice, lunID: rrp.REMOTERESID},
+	} {
+		lun, err := side.device.GetLUN(ctx, side.lunID)
+		if err != nil {
+			return fmt.Errorf("failed to get LUN: %w", err)
+		}
+		if lun.ISADD2LUNGROUP == false {
+			continue
+		}
+		if err := side.device.DetachVolume(ctx, lun.ID); err != nil {
+			return fmt.Errorf("failed to detach LUN (ID: %d): %w", lun.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteReplicatedVolume delete RemoteReplicationPair and LUNs in both devices
+func (c *Client) DeleteReplicatedVolume(ctx context.Context, pairID string) error {
+	// 1: delete LUN Group Associate
+	// 2: split and delete RemoteReplicationPair
+	// 3: delete LUN
+
+	rrp, err := c.LocalDevice.GetRemoteReplicationPair(ctx, pairID)
+	if err != nil {
+		return fmt.Errorf("failed to get RemoteReplicationPair: %w", err)
+	}
+
+	// 1: delete LUN Group Associate
+	if err := c.detachReplicatedLUNs(ctx, rrp); err != nil {
+		return err
+	}
+
+	// 2: split and delete RemoteReplicationPair
+	if rrp.State() != ReplicationStateSplit {
+		if err := c.LocalDevice.SplitRemoteReplicationPair(ctx, rrp.ID); err != nil {
+			return fmt.Errorf("failed to split RemoteReplicationPair: %w", err)
+		}
+	}
+	if err := c.LocalDevice.DeleteRemoteReplicationPair(ctx, rrp.ID); err != nil {
+		return fmt.Errorf("failed to delete RemoteReplicationPair: %w", err)
+	}
+
+	// 3: delete LUN
+	if err := c.LocalDevice.DeleteLUN(ctx, rrp.LOCALRESID); err != nil {
+		return fmt.Errorf("failed to delete Local LUN: %w", err)
+	}
+	if err := c.RemoteDevice.DeleteLUN(ctx, rrp.REMOTERESID); err != nil {
+		return fmt.Errorf("failed to delete Remote LUN: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,619 @@
+package dorado
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SmartQoS is SmartQoS policy (ioclass object)
+type SmartQoS struct {
+	CYCLESET          string `json:"CYCLESET"`
+	DESCRIPTION       string `json:"DESCRIPTION"`
+	DURATION          string `json:"DURATION"`
+	ENABLESTATUS      bool   `json:"ENABLESTATUS,string"`
+	HEALTHSTATUS      string `json:"HEALTHSTATUS"`
+	ID                string `json:"ID"`
+	LATENCY           string `json:"LATENCY"`
+	LUNGROUPLIST      string `json:"LUNGROUPLIST"` // ex: ["1","2"]
+	LUNLIST           string `json:"LUNLIST"`      // ex: ["1","2"]
+	MAXBANDWIDTH      string `json:"MAXBANDWIDTH"`
+	MAXIOPS           string `json:"MAXIOPS"`
+	MINBANDWIDTH      string `json:"MINBANDWIDTH"`
+	MINIOPS           string `json:"MINIOPS"`
+	NAME              string `json:"NAME"`
+	RUNNINGSTATUS     string `json:"RUNNINGSTATUS"`
+	SCHEDULEPOLICY    string `json:"SCHEDULEPOLICY"`
+	SCHEDULESTARTTIME string `json:"SCHEDULESTARTTIME"`
+	STARTTIME         string `json:"STARTTIME"`
+	TYPE              int    `json:"TYPE"`
+}
+
+// LUNIDs return IDs of LUNs that associated to SmartQoS
+func (q *SmartQoS) LUNIDs() ([]int, error) {
+	return parseSmartQoSList(q.LUNLIST)
+}
+
+// LunGroupIDs return IDs of LUN groups that associated to SmartQoS
+func (q *SmartQoS) LunGroupIDs() ([]int, error) {
+	return parseSmartQoSList(q.LUNGROUPLIST)
+}
+
+func parseSmartQoSList(list string) ([]int, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	var values []string
+	if err := json.Unmarshal([]byte(list), &values); err != nil {
+		return nil, fmt.Errorf("failed to parse list of SmartQoS (%s): %w", list, err)
+	}
+
+	var ids []int
+	for _, v := range values {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ID in list of SmartQoS (%s): %w", list, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// QoSSchedulePolicy is SCHEDULEPOLICY of SmartQoS
+type QoSSchedulePolicy int
+
+// QoSSchedulePolicy const
+const (
+	QoSScheduleOnce   QoSSchedulePolicy = 0
+	QoSScheduleDaily  QoSSchedulePolicy = 1
+	QoSScheduleWeekly QoSSchedulePolicy = 2
+)
+
+// QoSSchedule is schedule that SmartQoS is effective
+type QoSSchedule struct {
+	Policy    QoSSchedulePolicy
+	StartDate time.Time     // first day of schedule
+	StartTime string        // ex: 08:00
+	Duration  time.Duration // max 24h
+	Weekdays  []time.Weekday
+}
+
+// NewQoSSchedule create QoSSchedule that SmartQoS is always effective
+func NewQoSSchedule() *QoSSchedule {
+	y, m, d := time.Now().Date()
+	return &QoSSchedule{
+		Policy:    QoSScheduleDaily,
+		StartDate: time.Date(y, m, d, 0, 0, 0, 0, time.Local),
+		StartTime: "00:00",
+		Duration:  24 * time.Hour,
+	}
+}
+
+// SmartQoSParam is parameter of SmartQoS.
+// 0 is not limited. max limits (MaxIOPS, MaxBandwidthMB) and
+// min limits (MinIOPS, MinBandwidthMB, LatencyMs) can not be set in same policy.
+type SmartQoSParam struct {
+	Name           string
+	Description    string
+	MaxIOPS        int
+	MinIOPS        int
+	MaxBandwidthMB int // MB/s
+	MinBandwidthMB int // MB/s
+	LatencyMs      int
+	Schedule       *QoSSchedule // nil is always effective
+}
+
+// Validate validate value of SmartQoSParam
+func (p *SmartQoSParam) Validate() error {
+	hasMax := p.MaxIOPS > 0 || p.MaxBandwidthMB > 0
+	hasMin := p.MinIOPS > 0 || p.MinBandwidthMB > 0 || p.LatencyMs > 0
+
+	switch {
+	case p.MaxIOPS < 0 || p.MinIOPS < 0 || p.MaxBandwidthMB < 0 || p.MinBandwidthMB < 0 || p.LatencyMs < 0:
+		return errors.New("limit of SmartQoS must not be negative")
+	case !hasMax && !hasMin:
+		return errors.New("SmartQoS must have one or more limits")
+	case hasMax && hasMin:
+		return errors.New("max limits and min limits can not be set in same SmartQoS")
+	}
+
+	if p.Schedule != nil {
+		if _, err := time.Parse("15:04", p.Schedule.StartTime); err != nil {
+			return fmt.Errorf("invalid start time of SmartQoS schedule: %w", err)
+		}
+		if p.Schedule.Duration <= 0 || p.Schedule.Duration > 24*time.Hour {
+			return fmt.Errorf("invalid duration of SmartQoS schedule: %s", p.Schedule.Duration)
+		}
+		if p.Schedule.Policy == QoSScheduleWeekly && len(p.Schedule.Weekdays) == 0 {
+			return errors.New("weekly SmartQoS schedule must have weekdays")
+		}
+	}
+
+	return nil
+}
+
+// smartQoSBody is request body to create SmartQoS
+type smartQoSBody struct {
+	NAME              string   `json:"NAME"`
+	DESCRIPTION       string   `json:"DESCRIPTION,omitempty"`
+	TYPE              int      `json:"TYPE"`
+	CLASSTYPE         string   `json:"CLASSTYPE"`
+	IOTYPE            string   `json:"IOTYPE"`
+	MAXIOPS           int      `json:"MAXIOPS,omitempty"`
+	MINIOPS           int      `json:"MINIOPS,omitempty"`
+	MAXBANDWIDTH      int      `json:"MAXBANDWIDTH,omitempty"`
+	MINBANDWIDTH      int      `json:"MINBANDWIDTH,omitempty"`
+	LATENCY           int      `json:"LATENCY,omitempty"`
+	SCHEDULEPOLICY    string   `json:"SCHEDULEPOLICY"`
+	SCHEDULESTARTTIME string   `json:"SCHEDULESTARTTIME"`
+	STARTTIME         string   `json:"STARTTIME"`
+	DURATION          string   `json:"DURATION"`
+	CYCLESET          string   `json:"CYCLESET,omitempty"`
+	LUNLIST           []string `json:"LUNLIST,omitempty"`
+}
+
+// smartQoSUpdateBody is request body to update SmartQoS.
+// all limits are sent even if 0, to reset limits that set before.
+type smartQoSUpdateBody struct {
+	NAME              string `json:"NAME,omitempty"` // empty is not renamed
+	DESCRIPTION       string `json:"DESCRIPTION"`
+	MAXIOPS           int    `json:"MAXIOPS"`
+	MINIOPS           int    `json:"MINIOPS"`
+	MAXBANDWIDTH      int    `json:"MAXBANDWIDTH"`
+	MINBANDWIDTH      int    `json:"MINBANDWIDTH"`
+	LATENCY           int    `json:"LATENCY"`
+	SCHEDULEPOLICY    string `json:"SCHEDULEPOLICY"`
+	SCHEDULESTARTTIME string `json:"SCHEDULESTARTTIME"`
+	STARTTIME         string `json:"STARTTIME"`
+	DURATION          string `json:"DURATION"`
+	CYCLESET          string `json:"CYCLESET,omitempty"`
+}
+
+func newSmartQoSBody(p *SmartQoSParam) *smartQoSBody {
+	u := newSmartQoSUpdateBody(p)
+
+	return &smartQoSBody{
+		NAME:              u.NAME,
+		DESCRIPTION:       u.DESCRIPTION,
+		TYPE:              TypeSmartQoS,
+		CLASSTYPE:         "1",
+		IOTYPE:            "2", // read and write
+		MAXIOPS:           u.MAXIOPS,
+		MINIOPS:           u.MINIOPS,
+		MAXBANDWIDTH:      u.MAXBANDWIDTH,
+		MINBANDWIDTH:      u.MINBANDWIDTH,
+		LATENCY:           u.LATENCY,
+		SCHEDULEPOLICY:    u.SCHEDULEPOLICY,
+		SCHEDULESTARTTIME: u.SCHEDULESTARTTIME,
+		STARTTIME:         u.STARTTIME,
+		DURATION:          u.DURATION,
+		CYCLESET:          u.CYCLESET,
+	}
+}
+
+func newSmartQoSUpdateBody(p *SmartQoSParam) *smartQoSUpdateBody {
+	schedule := p.Schedule
+	if schedule == nil {
+		schedule = NewQoSSchedule()
+	}
+
+	body := &smartQoSUpdateBody{
+		NAME:              p.Name,
+		DESCRIPTION:       p.Description,
+		MAXIOPS:           p.MaxIOPS,
+		MINIOPS:           p.MinIOPS,
+		MAXBANDWIDTH:      p.MaxBandwidthMB,
+		MINBANDWIDTH:      p.MinBandwidthMB,
+		LATENCY:           p.LatencyMs,
+		SCHEDULEPOLICY:    strconv.Itoa(int(schedule.Policy)),
+		SCHEDULESTARTTIME: strconv.FormatInt(schedule.StartDate.Unix(), 10),
+		STARTTIME:         schedule.StartTime,
+		DURATION:          strconv.Itoa(int(schedule.Duration.Seconds())),
+	}
+	if schedule.Policy == QoSScheduleWeekly {
+		cycle, _ := json.Marshal(schedule.Weekdays) // Sunday is 0
+		body.CYCLESET = string(cycle)
+	}
+
+	return body
+}
+
+func toSmartQoSList(ids []int) []string {
+	list := []string{}
+	for _, id := range ids {
+		list = append(list, strconv.Itoa(id))
+	}
+	return list
+}
+
+// GetSmartQoSs get SmartQoS policies by query
+func (d *Device) GetSmartQoSs(ctx context.Context, query *SearchQuery) ([]SmartQoS, error) {
+	spath := "/ioclass"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var smartQoSs []SmartQoS
+	if err = d.requestWithRetry(req, &smartQoSs, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(smartQoSs) == 0 {
+		return nil, ErrSmartQoSNotFound
+	}
+
+	return smartQoSs, nil
+}
+
+// GetSmartQoS get SmartQoS policy by id
+func (d *Device) GetSmartQoS(ctx context.Context, smartQoSID string) (*SmartQoS, error) {
+	spath := fmt.Sprintf("/ioclass/%s", smartQoSID)
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	smartQoS := &SmartQoS{}
+	if err = d.requestWithRetry(req, smartQoS, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return smartQoS, nil
+}
+
+// CreateSmartQoS create SmartQoS policy that associated to LUNs and activate it.
+// a LUN can be associated to only one SmartQoS.
+func (d *Device) CreateSmartQoS(ctx context.Context, param *SmartQoSParam, lunIDs []int) (*SmartQoS, error) {
+	if err := param.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid SmartQoS parameter: %w", err)
+	}
+
+	spath := "/ioclass"
+	body := newSmartQoSBody(param)
+	body.LUNLIST = toSmartQoSList(lunIDs)
+	jb, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	smartQoS := &SmartQoS{}
+	if err = d.requestWithRetry(req, smartQoS, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if err := d.DeleteSmartQoS(ctx, smartQoS.ID); err != nil {
+				d.Logger.Printf("failed to delete SmartQoS: %v", err)
+			}
+		}
+	}()
+
+	if err = d.ActivateSmartQoS(ctx, smartQoS.ID, true); err != nil {
+		return nil, fmt.Errorf("failed to activate SmartQoS: %w", err)
+	}
+
+	return d.GetSmartQoS(ctx, smartQoS.ID)
+}
+
+// UpdateSmartQoS update limits and schedule of SmartQoS policy.
+// limits that are 0 in param are reset.
+func (d *Device) UpdateSmartQoS(ctx context.Context, smartQoSID string, param *SmartQoSParam) error {
+	if err := param.Validate(); err != nil {
+		return fmt.Errorf("invalid SmartQoS parameter: %w", err)
+	}
+
+	return d.putSmartQoS(ctx, smartQoSID, newSmartQoSUpdateBody(param))
+}
+
+// DeleteSmartQoS deactivate and delete SmartQoS policy
+func (d *Device) DeleteSmartQoS(ctx context.Context, smartQoSID string) error {
+	if err := d.ActivateSmartQoS(ctx, smartQoSID, false); err != nil {
+		return fmt.Errorf("failed to deactivate SmartQoS: %w", err)
+	}
+
+	spath := fmt.Sprintf("/ioclass/%s", smartQoSID)
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// ActivateSmartQoS activate (enable is true) or deactivate SmartQoS policy
+func (d *Device) ActivateSmartQoS(ctx context.Context, smartQoSID string, enable bool) error {
+	spath := "/ioclass/active"
+	param := struct {
+		ID           string `json:"ID"`
+		ENABLESTATUS string `json:"ENABLESTATUS"`
+	}{
+		ID:           smartQoSID,
+		ENABLESTATUS: strconv.FormatBool(enable),
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// AssociateSmartQoSLUN associate LUN to SmartQoS policy
+func (d *Device) AssociateSmartQoSLUN(ctx context.Context, smartQoSID string, lunID int) error {
+	return d.updateSmartQoSMembers(ctx, smartQoSID, TypeLUN, lunID, true)
+}
+
+// DisAssociateSmartQoSLUN dis associate LUN from SmartQoS policy.
+// SmartQoS policy is deleted if it has no LUNs and LUN groups, array can not keep empty policy.
+func (d *Device) DisAssociateSmartQoSLUN(ctx context.Context, smartQoSID string, lunID int) error {
+	return d.updateSmartQoSMembers(ctx, smartQoSID, TypeLUN, lunID, false)
+}
+
+// AssociateSmartQoSLunGroup associate LUN group to SmartQoS policy
+func (d *Device) AssociateSmartQoSLunGroup(ctx context.Context, smartQoSID string, lungroupID int) error {
+	return d.updateSmartQoSMembers(ctx, smartQoSID, TypeLUNGroup, lungroupID, true)
+}
+
+// DisAssociateSmartQoSLunGroup dis associate LUN group from SmartQoS policy.
+// SmartQoS policy is deleted if it has no LUNs and LUN groups, array can not keep empty policy.
+func (d *Device) DisAssociateSmartQoSLunGroup(ctx context.Context, smartQoSID string, lungroupID int) error {
+	return d.updateSmartQoSMembers(ctx, smartQoSID, TypeLUNGroup, lungroupID, false)
+}
+
+// updateSmartQoSMembers add (associate is true) or remove objID to list of SmartQoS.
+// API replace list, so current list is merged.
+func (d *Device) updateSmartQoSMembers(ctx context.Context, smartQoSID string, objType, objID int, associate bool) error {
+	smartQoS, err := d.GetSmartQoS(ctx, smartQoSID)
+	if err != nil {
+		return fmt.Errorf("failed to get SmartQoS: %w", err)
+	}
+	lunIDs, err := smartQoS.LUNIDs()
+	if err != nil {
+		return err
+	}
+	lunGroupIDs, err := smartQoS.LunGroupIDs()
+	if err != nil {
+		return err
+	}
+
+	var key string
+	var current, others []int
+	switch objType {
+	case TypeLUN:
+		key, current, others = "LUNLIST", lunIDs, lunGroupIDs
+	case TypeLUNGroup:
+		key, current, others = "LUNGROUPLIST", lunGroupIDs, lunIDs
+	default:
+		return fmt.Errorf("unsupported object type for SmartQoS: %d", objType)
+	}
+
+	var ids []int
+	found := false
+	for _, id := range current {
+		if id == objID {
+			found = true
+			if !associate {
+				continue
+			}
+		}
+		ids = append(ids, id)
+	}
+	if found == associate {
+		// already associated or dis associated
+		return nil
+	}
+	if associate {
+		ids = append(ids, objID)
+	}
+
+	if len(ids) == 0 && len(others) == 0 {
+		if err := d.DeleteSmartQoS(ctx, smartQoSID); err != nil {
+			return fmt.Errorf("failed to delete SmartQoS that has no members: %w", err)
+		}
+		return nil
+	}
+
+	// empty list is sent explicitly to remove last member
+	return d.putSmartQoS(ctx, smartQoSID, map[string][]string{key: toSmartQoSList(ids)})
+}
+
+func (d *Device) putSmartQoS(ctx context.Context, smartQoSID string, body interface{}) error {
+	spath := fmt.Sprintf("/ioclass/%s", smartQoSID)
+	jb, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// Param return SmartQoSParam of SmartQoS
+func (q *SmartQoS) Param() (*SmartQoSParam, error) {
+	atoi := func(v string) (int, error) {
+		if v == "" {
+			return 0, nil
+		}
+		return strconv.Atoi(v)
+	}
+
+	p := &SmartQoSParam{Name: q.NAME, Description: q.DESCRIPTION}
+	for _, f := range []struct {
+		value string
+		dst   *int
+	}{
+		{value: q.MAXIOPS, dst: &p.MaxIOPS},
+		{value: q.MINIOPS, dst: &p.MinIOPS},
+		{value: q.MAXBANDWIDTH, dst: &p.MaxBandwidthMB},
+		{value: q.MINBANDWIDTH, dst: &p.MinBandwidthMB},
+		{value: q.LATENCY, dst: &p.LatencyMs},
+	} {
+		v, err := atoi(f.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse limit of SmartQoS: %w", err)
+		}
+		*f.dst = v
+	}
+
+	if q.SCHEDULEPOLICY == "" {
+		return p, nil
+	}
+	policy, err := atoi(q.SCHEDULEPOLICY)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SCHEDULEPOLICY: %w", err)
+	}
+	start, err := strconv.ParseInt(q.SCHEDULESTARTTIME, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SCHEDULESTARTTIME: %w", err)
+	}
+	duration, err := atoi(q.DURATION)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DURATION: %w", err)
+	}
+	p.Schedule = &QoSSchedule{
+		Policy:    QoSSchedulePolicy(policy),
+		StartDate: time.Unix(start, 0),
+		StartTime: q.STARTTIME,
+		Duration:  time.Duration(duration) * time.Second,
+	}
+	if q.CYCLESET != "" {
+		if err := json.Unmarshal([]byte(q.CYCLESET), &p.Schedule.Weekdays); err != nil {
+			return nil, fmt.Errorf("failed to parse CYCLESET: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+// getLUNQoS return SmartQoS of LUN that created by setLUNQoS. return nil if LUN has no SmartQoS.
+func (d *Device) getLUNQoS(ctx context.Context, lunID int) (*LUN, *SmartQoS, error) {
+	lun, err := d.GetLUN(ctx, lunID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get LUN: %w", err)
+	}
+	if lun.IOCLASSID == "" {
+		return lun, nil, nil
+	}
+
+	smartQoS, err := d.GetSmartQoS(ctx, lun.IOCLASSID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get SmartQoS of LUN: %w", err)
+	}
+	if smartQoS.NAME != lun.NAME {
+		return nil, nil, fmt.Errorf("LUN (ID: %d) is associated to other SmartQoS (ID: %s)", lun.ID, smartQoS.ID)
+	}
+
+	return lun, smartQoS, nil
+}
+
+// setLUNQoS set SmartQoS of LUN. SmartQoS of LUN is same name as LUN.
+// nil param remove SmartQoS of LUN.
+func (d *Device) setLUNQoS(ctx context.Context, lunID int, param *SmartQoSParam) error {
+	lun, current, err := d.getLUNQoS(ctx, lunID)
+	if err != nil {
+		return err
+	}
+
+	var p SmartQoSParam
+	if param != nil {
+		p = *param
+		p.Name = lun.NAME
+	}
+
+	switch {
+	case current != nil && param != nil:
+		return d.UpdateSmartQoS(ctx, current.ID, &p)
+	case current != nil:
+		// SmartQoS is deleted with last LUN
+		if err := d.DisAssociateSmartQoSLUN(ctx, current.ID, lun.ID); err != nil {
+			return fmt.Errorf("failed to dis associate LUN from SmartQoS: %w", err)
+		}
+		return nil
+	case param != nil:
+		if _, err := d.CreateSmartQoS(ctx, &p, []int{lun.ID}); err != nil {
+			return fmt.Errorf("failed to create SmartQoS: %w", err)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// SetVolumeQoS set SmartQoS to both LUNs of HyperMetroPair.
+// SmartQoS is created per LUN, nil param remove SmartQoS of volume.
+// SmartQoS of Local LUN is restored if failed to set Remote LUN.
+func (c *Client) SetVolumeQoS(ctx context.Context, hyperMetroPairID string, param *SmartQoSParam) error {
+	if param != nil {
+		if err := param.Validate(); err != nil {
+			return fmt.Errorf("invalid SmartQoS parameter: %w", err)
+		}
+	}
+
+	hmp, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+	if err != nil {
+		return fmt.Errorf("failed to get HyperMetro Pair: %w", err)
+	}
+
+	_, previous, err := c.LocalDevice.getLUNQoS(ctx, hmp.LOCALOBJID)
+	if err != nil {
+		return fmt.Errorf("failed to get SmartQoS of Local LUN: %w", err)
+	}
+	var previousParam *SmartQoSParam
+	if previous != nil {
+		if previousParam, err = previous.Param(); err != nil {
+			return fmt.Errorf("failed to get SmartQoS of Local LUN: %w", err)
+		}
+	}
+
+	if err := c.LocalDevice.setLUNQoS(ctx, hmp.LOCALOBJID, param); err != nil {
+		return fmt.Errorf("failed to set SmartQoS of Local LUN: %w", err)
+	}
+	if err := c.RemoteDevice.setLUNQoS(ctx, hmp.REMOTEOBJID, param); err != nil {
+		if err := c.LocalDevice.setLUNQoS(ctx, hmp.LOCALOBJID, previousParam); err != nil {
+			c.LocalDevice.Logger.Printf("failed to restore SmartQoS of Local LUN: %v", err)
+		}
+		return fmt.Errorf("failed to set SmartQoS of Remote LUN: %w", err)
+	}
+
+	return nil
+}
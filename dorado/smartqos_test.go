@@ -0,0 +1,257 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDevice_CreateSmartQoS(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	startDate := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	mux.HandleFunc("/ioclass", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		testBody(t, r, map[string]interface{}{
+			"NAME":              "gold",
+			"TYPE":              float64(TypeSmartQoS),
+			"CLASSTYPE":         "1",
+			"IOTYPE":            "2",
+			"MAXIOPS":           float64(5000),
+			"MAXBANDWIDTH":      float64(200),
+			"SCHEDULEPOLICY":    "2",
+			"SCHEDULESTARTTIME": strconv.FormatInt(startDate.Unix(), 10),
+			"STARTTIME":         "08:00",
+			"DURATION":          "36000",
+			"CYCLESET":          "[1,2,3,4,5]",
+			"LUNLIST":           []interface{}{"148"},
+		})
+
+		fmt.Fprint(w, `{"data": {"ID": "1", "NAME": "gold", "TYPE": 230}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/ioclass/active", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/ioclass/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ENABLESTATUS": "true", "ID": "1", "LUNLIST": "[\"148\"]", "MAXIOPS": "5000", "NAME": "gold", "TYPE": 230}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	param := &SmartQoSParam{
+		Name:           "gold",
+		MaxIOPS:        5000,
+		MaxBandwidthMB: 200,
+		Schedule: &QoSSchedule{
+			Policy:    QoSScheduleWeekly,
+			StartDate: startDate,
+			StartTime: "08:00",
+			Duration:  10 * time.Hour,
+			Weekdays:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		},
+	}
+	smartQoS, err := client.LocalDevice.CreateSmartQoS(context.Background(), param, []int{148})
+	if err != nil {
+		t.Fatalf("CreateSmartQoS return err: %s", err)
+	}
+
+	lunIDs, err := smartQoS.LUNIDs()
+	if err != nil {
+		t.Fatalf("LUNIDs return err: %s", err)
+	}
+	if !smartQoS.ENABLESTATUS || !reflect.DeepEqual(lunIDs, []int{148}) {
+		t.Errorf("CreateSmartQoS return %+v", smartQoS)
+	}
+}
+
+func TestDevice_AssociateSmartQoSLUN(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/ioclass/1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"data": {"ID": "1", "LUNLIST": "[\"148\",\"149\"]", "NAME": "gold", "TYPE": 230}, "error": {"code": 0, "description": "0"}}`)
+		case "PUT":
+			testBody(t, r, map[string]interface{}{
+				"LUNLIST": []interface{}{"148", "149", "150"},
+			})
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+		default:
+			t.Errorf("Request method: %v, want GET or PUT", r.Method)
+		}
+	})
+
+	if err := client.LocalDevice.AssociateSmartQoSLUN(context.Background(), "1", 150); err != nil {
+		t.Fatalf("AssociateSmartQoSLUN return err: %s", err)
+	}
+	// already associated, not updated
+	if err := client.LocalDevice.AssociateSmartQoSLUN(context.Background(), "1", 148); err != nil {
+		t.Fatalf("AssociateSmartQoSLUN return err: %s", err)
+	}
+}
+
+func TestSmartQoSParam_Validate(t *testing.T) {
+	tests := []struct {
+		param   SmartQoSParam
+		wantErr bool
+	}{
+		{param: SmartQoSParam{MaxIOPS: 1000}, wantErr: false},
+		{param: SmartQoSParam{MinIOPS: 1000, LatencyMs: 1}, wantErr: false},
+		{param: SmartQoSParam{}, wantErr: true},
+		{param: SmartQoSParam{MaxIOPS: 1000, MinIOPS: 100}, wantErr: true},
+		{param: SmartQoSParam{MaxIOPS: -1}, wantErr: true},
+		{param: SmartQoSParam{MaxIOPS: 1000, Schedule: &QoSSchedule{Policy: QoSScheduleWeekly, StartTime: "08:00", Duration: time.Hour}}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := test.param.Validate()
+		if (err != nil) != test.wantErr {
+			t.Errorf("Validate(%+v) return err %v, wantErr %v", test.param, err, test.wantErr)
+		}
+	}
+}
+
+func TestDevice_DisAssociateSmartQoSLUN(t *testing.T) {
+	tests := []struct {
+		name         string
+		lunGroupList string
+		wantPut      map[string]interface{}
+		wantDeleted  bool
+	}{
+		{
+			name:         "LUN group is remained",
+			lunGroupList: `[\"1\"]`,
+			wantPut:      map[string]interface{}{"LUNLIST": []interface{}{}},
+		},
+		{
+			name:        "last member",
+			wantDeleted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			var deactivated, deleted bool
+			mux.HandleFunc("/ioclass/1", func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+					fmt.Fprintf(w, `{"data": {"ID": "1", "LUNLIST": "[\"148\"]", "LUNGROUPLIST": "%s", "NAME": "gold", "TYPE": 230}, "error": {"code": 0, "description": "0"}}`, test.lunGroupList)
+					return
+				case "PUT":
+					testBody(t, r, test.wantPut)
+				case "DELETE":
+					if !deactivated {
+						t.Errorf("SmartQoS is deleted before deactivate")
+					}
+					deleted = true
+				}
+				fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/ioclass/active", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "PUT")
+				deactivated = true
+				fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			})
+
+			if err := client.LocalDevice.DisAssociateSmartQoSLUN(context.Background(), "1", 148); err != nil {
+				t.Fatalf("DisAssociateSmartQoSLUN return err: %s", err)
+			}
+			if deleted != test.wantDeleted {
+				t.Errorf("SmartQoS is deleted: %t, want %t", deleted, test.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestDevice_UpdateSmartQoS(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	startDate := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	mux.HandleFunc("/ioclass/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+
+		// max limits of previous tier must be reset
+		testBody(t, r, map[string]interface{}{
+			"DESCRIPTION":       "",
+			"MAXIOPS":           float64(0),
+			"MINIOPS":           float64(1000),
+			"MAXBANDWIDTH":      float64(0),
+			"MINBANDWIDTH":      float64(0),
+			"LATENCY":           float64(0),
+			"SCHEDULEPOLICY":    "1",
+			"SCHEDULESTARTTIME": strconv.FormatInt(startDate.Unix(), 10),
+			"STARTTIME":         "00:00",
+			"DURATION":          "86400",
+		})
+
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	schedule := NewQoSSchedule()
+	schedule.StartDate = startDate
+	err := client.LocalDevice.UpdateSmartQoS(context.Background(), "1", &SmartQoSParam{MinIOPS: 1000, Schedule: schedule})
+	if err != nil {
+		t.Fatalf("UpdateSmartQoS return err: %s", err)
+	}
+}
+
+func TestClient_SetVolumeQoS_Rollback(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	localIOClassID := ""
+	deleted := false
+
+	mux.HandleFunc("/HyperMetroPair/3400a30d844d0007", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "3400a30d844d0007", "LOCALOBJID": "148", "REMOTEOBJID": "151"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/148", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"data": {"ID": "148", "IOCLASSID": "%s", "NAME": "volume", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`, localIOClassID)
+	})
+	mux.HandleFunc("/lun/151", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 1077936859, "description": "The LUN does not exist."}}`)
+	})
+	mux.HandleFunc("/ioclass", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		localIOClassID = "1"
+		fmt.Fprint(w, `{"data": {"ID": "1", "NAME": "volume", "TYPE": 230}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/ioclass/active", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/ioclass/1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"data": {"ID": "1", "LUNLIST": "[\"148\"]", "NAME": "volume", "TYPE": 230}, "error": {"code": 0, "description": "0"}}`)
+		case "DELETE":
+			deleted = true
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+		default:
+			t.Errorf("Request method: %v, want GET or DELETE", r.Method)
+		}
+	})
+
+	err := client.SetVolumeQoS(context.Background(), "3400a30d844d0007", &SmartQoSParam{MaxIOPS: 1000})
+	if err == nil {
+		t.Fatalf("SetVolumeQoS must return err")
+	}
+	if !deleted {
+		t.Errorf("SmartQoS of Local LUN is not restored")
+	}
+}
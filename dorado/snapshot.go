@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -94,8 +95,76 @@ func (d *Device) GetSnapshot(ctx context.Context, snapshotID int) (*Snapshot, er
 	return snapshots, nil
 }
 
+// ListSnapshotsForLUN get snapshots that source is LUN.
+// cascaded snapshots (snapshot of snapshot) are not included.
+func (d *Device) ListSnapshotsForLUN(ctx context.Context, lunID int) ([]Snapshot, error) {
+	return d.listChildSnapshots(ctx, lunID, TypeLUN)
+}
+
+// listChildSnapshots get snapshots that parent is object of parentType.
+func (d *Device) listChildSnapshots(ctx context.Context, parentID, parentType int) ([]Snapshot, error) {
+	query := &SearchQuery{
+		Filter: ToFilter("PARENTID", strconv.Itoa(parentID)),
+	}
+	snapshots, err := d.GetSnapshots(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// PARENTID is same value in LUN and snapshot
+	var children []Snapshot
+	for _, snapshot := range snapshots {
+		if snapshot.PARENTTYPE == parentType {
+			children = append(children, snapshot)
+		}
+	}
+
+	if len(children) == 0 {
+		return nil, ErrSnapshotNotFound
+	}
+
+	return children, nil
+}
+
+// GetAssociateSnapshots get snapshots that associated object (ex: snapshot consistency group)
+func (d *Device) GetAssociateSnapshots(ctx context.Context, query *SearchQuery) ([]Snapshot, error) {
+	spath := "/snapshot/associate"
+
+	if query == nil || query.AssociateObjType == "" || query.AssociateObjID == "" {
+		return nil, errors.New("you must set associated parameter")
+	}
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var snapshots []Snapshot
+	if err = d.requestWithRetry(req, &snapshots, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(snapshots) == 0 {
+		return nil, ErrSnapshotNotFound
+	}
+
+	return snapshots, nil
+}
+
 // CreateSnapshot create object of snapshot
 func (d *Device) CreateSnapshot(ctx context.Context, lunID int, name uuid.UUID, description string) (*Snapshot, error) {
+	return d.createSnapshot(ctx, lunID, TypeLUN, name, description)
+}
+
+// CreateCascadedSnapshot create cascaded snapshot (snapshot of snapshot, PARENTTYPE is snapshot).
+// a point-in-time image of snapshot is kept even if source snapshot is rolled back or re-activated.
+// it is not a snapshot duplicate, cascaded snapshot depends on source snapshot.
+func (d *Device) CreateCascadedSnapshot(ctx context.Context, snapshotID int, name uuid.UUID, description string) (*Snapshot, error) {
+	return d.createSnapshot(ctx, snapshotID, TypeSnapshot, name, description)
+}
+
+func (d *Device) createSnapshot(ctx context.Context, parentID, parentType int, name uuid.UUID, description string) (*Snapshot, error) {
 	spath := "/snapshot"
 	param := struct {
 		TYPE        string `json:"TYPE"`
@@ -106,8 +175,8 @@ func (d *Device) CreateSnapshot(ctx context.Context, lunID int, name uuid.UUID,
 	}{
 		TYPE:        strconv.Itoa(TypeSnapshot),
 		NAME:        EncodeSnapshotName(name),
-		PARENTTYPE:  strconv.Itoa(TypeLUN),
-		PARENTID:    strconv.Itoa(lunID),
+		PARENTTYPE:  strconv.Itoa(parentType),
+		PARENTID:    strconv.Itoa(parentID),
 		DESCRIPTION: description,
 	}
 	jb, err := json.Marshal(param)
@@ -135,18 +204,33 @@ func (d *Device) CreateSnapshotWithWait(ctx context.Context, lunID int, name uui
 		return nil, fmt.Errorf("failed to create snapshot: %w", err)
 	}
 
+	return d.waitSnapshotIsReady(ctx, snapshot.ID)
+}
+
+// CreateCascadedSnapshotWithWait create cascaded snapshot and waiting ready
+func (d *Device) CreateCascadedSnapshotWithWait(ctx context.Context, snapshotID int, name uuid.UUID, description string) (*Snapshot, error) {
+	snapshot, err := d.CreateCascadedSnapshot(ctx, snapshotID, name, description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cascaded snapshot: %w", err)
+	}
+
+	return d.waitSnapshotIsReady(ctx, snapshot.ID)
+}
+
+// waitSnapshotIsReady wait snapshot is ready, and delete snapshot if failed.
+func (d *Device) waitSnapshotIsReady(ctx context.Context, snapshotID int) (*Snapshot, error) {
 	// wait 10 seconds
 	for i := 0; i < 10; i++ {
-		isReady, err := d.snapshotIsReady(ctx, snapshot.ID)
+		isReady, err := d.snapshotIsReady(ctx, snapshotID)
 		if err != nil {
-			if err := d.DeleteSnapshot(ctx, snapshot.ID); err != nil {
+			if err := d.DeleteSnapshot(ctx, snapshotID); err != nil {
 				d.Logger.Printf("failed to delete snapshot: %v\n", err)
 			}
 			return nil, fmt.Errorf("failed to wait that snapshot is ready: %w", err)
 		}
 
 		if isReady == true {
-			return d.GetSnapshot(ctx, snapshot.ID)
+			return d.GetSnapshot(ctx, snapshotID)
 		}
 
 		time.Sleep(1 * time.Second)
@@ -251,3 +335,177 @@ func (d *Device) StopSnapshot(ctx context.Context, snapshotID int) error {
 
 	return nil
 }
+
+// RollbackSnapshot start to rollback source LUN to snapshot.
+// snapshot must be activated.
+func (d *Device) RollbackSnapshot(ctx context.Context, snapshotID int, speed Speed) error {
+	spath := "/snapshot/rollback"
+	param := struct {
+		ID            string `json:"ID"`
+		ROLLBACKSPEED int    `json:"ROLLBACKSPEED"`
+	}{
+		ID:            strconv.Itoa(snapshotID),
+		ROLLBACKSPEED: int(speed),
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// StopRollbackSnapshot stop to rollback snapshot
+func (d *Device) StopRollbackSnapshot(ctx context.Context, snapshotID int) error {
+	spath := "/snapshot/stop_rollback"
+	param := struct {
+		ID string `json:"ID"`
+	}{
+		ID: strconv.Itoa(snapshotID),
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// RollbackSnapshotWithWait start to rollback and wait to done.
+func (d *Device) RollbackSnapshotWithWait(ctx context.Context, snapshotID int, speed Speed, timeoutCount int) error {
+	if timeoutCount == 0 {
+		timeoutCount = DefaultCopyTimeoutSecond
+	}
+
+	// ROLLBACKENDTIME is kept after previous rollback, record it to know that this rollback is done.
+	before, err := d.GetSnapshot(ctx, snapshotID)
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot (ID: %d): %w", snapshotID, err)
+	}
+
+	err = d.RollbackSnapshot(ctx, snapshotID, speed)
+	if err != nil {
+		return fmt.Errorf("failed to rollback snapshot (ID: %d): %w", snapshotID, err)
+	}
+
+	started := false
+	for i := 0; i < timeoutCount; i++ {
+		isDone, err := d.snapshotRollbackIsDone(ctx, snapshotID, before.ROLLBACKENDTIME, &started)
+		if err != nil {
+			return fmt.Errorf("failed to wait that rollback is done: %w", err)
+		}
+
+		if isDone == true {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
+	}
+
+	return ErrTimeoutWait
+}
+
+// snapshotRollbackIsDone return true after rollback is started and finished.
+// RUNNINGSTATUS is not changed to rolling back just after rollback is requested,
+// so rollback is regarded as started when RUNNINGSTATUS was rolling back or ROLLBACKENDTIME is updated.
+func (d *Device) snapshotRollbackIsDone(ctx context.Context, snapshotID int, lastEndTime string, started *bool) (bool, error) {
+	snapshot, err := d.GetSnapshot(ctx, snapshotID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get snapshot (ID: %d): %w", snapshotID, err)
+	}
+
+	if snapshot.HEALTHSTATUS != strconv.Itoa(StatusHealth) {
+		return false, fmt.Errorf("snapshot health status is bad (HEALTHSTATUS: %s)", snapshot.HEALTHSTATUS)
+	}
+
+	if snapshot.RUNNINGSTATUS == strconv.Itoa(StatusSnapshotRollingBack) {
+		*started = true
+		d.Logger.Printf("rollback snapshot is in progress (ID: %d, ROLLBACKRATE: %s)", snapshotID, snapshot.ROLLBACKRATE)
+		return false, nil
+	}
+
+	if !*started && snapshot.ROLLBACKENDTIME == lastEndTime {
+		d.Logger.Printf("rollback snapshot is not started yet (ID: %d)", snapshotID)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// RestoreVolumeFromSnapshot restore HyperMetroPair from snapshot of local LUN.
+// 1: suspend HyperMetroPair, 2: rollback local LUN, 3: re-sync to remote LUN.
+// local LUN must be primary in HyperMetroPair, and HyperMetroPair must not be in consistency group.
+func (c *Client) RestoreVolumeFromSnapshot(ctx context.Context, hyperMetroPairID string, snapshotID int, speed Speed) error {
+	hmp, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+	if err != nil {
+		return fmt.Errorf("failed to get HyperMetro Pair: %w", err)
+	}
+
+	snapshot, err := c.LocalDevice.GetSnapshot(ctx, snapshotID)
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	if snapshot.PARENTID != hmp.LOCALOBJID {
+		return errors.New("snapshot is not a snapshot of local LUN in HyperMetroPair")
+	}
+	if hmp.ISPRIMARY != "true" {
+		// re-sync will overwrite local LUN by remote LUN
+		return errors.New("local LUN is not primary in HyperMetroPair")
+	}
+	if hmp.ISINCG == "true" {
+		// rollback only one LUN breaks consistency of group
+		return fmt.Errorf("HyperMetroPair is in consistency group (CGID: %s), use RestoreVolumesFromGroupSnapshot", hmp.CGID)
+	}
+	if snapshot.RUNNINGSTATUS != strconv.Itoa(StatusSnapshotActive) {
+		if err := c.LocalDevice.ActivateSnapshot(ctx, snapshot.ID); err != nil {
+			return fmt.Errorf("failed to activate snapshot: %w", err)
+		}
+	}
+
+	// 1: suspend HyperMetroPair
+	if hmp.RUNNINGSTATUS != strconv.Itoa(StatusPause) {
+		err = c.SuspendHyperMetroPair(ctx, hmp.ID)
+		if err != nil {
+			return fmt.Errorf("failed to suspend HyperMetroPair: %w", err)
+		}
+	}
+
+	// 2: rollback local LUN
+	// NOTE: HyperMetroPair is kept suspended if failed, local LUN may not be consistent.
+	err = c.LocalDevice.RollbackSnapshotWithWait(ctx, snapshot.ID, speed, 0)
+	if err != nil {
+		return fmt.Errorf("failed to rollback local LUN: %w", err)
+	}
+
+	// 3: re-sync to remote LUN
+	err = c.SyncHyperMetroPair(ctx, hmp.ID)
+	if err != nil {
+		return fmt.Errorf("failed to re-sync HyperMetro Pair: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,293 @@
+package dorado
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// SnapshotConsistencyGroup is group of snapshots that are taken at the same time from LUNs in protection group
+type SnapshotConsistencyGroup struct {
+	DESCRIPTION   string `json:"DESCRIPTION"`
+	HEALTHSTATUS  string `json:"HEALTHSTATUS"`
+	ID            int    `json:"ID,string"`
+	NAME          string `json:"NAME"`
+	PARENTID      int    `json:"PARENTID,string"` // = ID of protection group
+	PARENTNAME    string `json:"PARENTNAME"`
+	PARENTTYPE    int    `json:"PARENTTYPE"`
+	RUNNINGSTATUS string `json:"RUNNINGSTATUS"`
+	TIMESTAMP     string `json:"TIMESTAMP"`
+	TYPE          int    `json:"TYPE"`
+}
+
+// GetSnapshotConsistencyGroups get snapshot consistency groups by query
+func (d *Device) GetSnapshotConsistencyGroups(ctx context.Context, query *SearchQuery) ([]SnapshotConsistencyGroup, error) {
+	spath := "/snapshot_consistency_group"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var scgs []SnapshotConsistencyGroup
+	if err = d.requestWithRetry(req, &scgs, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(scgs) == 0 {
+		return nil, ErrSnapshotConsistencyGroupNotFound
+	}
+
+	return scgs, nil
+}
+
+// GetSnapshotConsistencyGroup get snapshot consistency group by id
+func (d *Device) GetSnapshotConsistencyGroup(ctx context.Context, scgID int) (*SnapshotConsistencyGroup, error) {
+	spath := fmt.Sprintf("/snapshot_consistency_group/%d", scgID)
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	scg := &SnapshotConsistencyGroup{}
+	if err = d.requestWithRetry(req, scg, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return scg, nil
+}
+
+// CreateSnapshotConsistencyGroup create snapshot consistency group.
+// snapshots of all LUNs in protection group are created atomically.
+func (d *Device) CreateSnapshotConsistencyGroup(ctx context.Context, protectionGroupID int, name uuid.UUID, description string) (*SnapshotConsistencyGroup, error) {
+	spath := "/snapshot_consistency_group"
+	param := struct {
+		NAME        string `json:"NAME"`
+		PARENTID    string `json:"PARENTID"`
+		DESCRIPTION string `json:"DESCRIPTION"`
+	}{
+		NAME:        EncodeSnapshotName(name),
+		PARENTID:    strconv.Itoa(protectionGroupID),
+		DESCRIPTION: description,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "POST", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	scg := &SnapshotConsistencyGroup{}
+	if err = d.requestWithRetry(req, scg, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return scg, nil
+}
+
+// CreateSnapshotConsistencyGroupWithWait create snapshot consistency group and waiting ready
+func (d *Device) CreateSnapshotConsistencyGroupWithWait(ctx context.Context, protectionGroupID int, name uuid.UUID, description string) (*SnapshotConsistencyGroup, error) {
+	scg, err := d.CreateSnapshotConsistencyGroup(ctx, protectionGroupID, name, description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot consistency group: %w", err)
+	}
+
+	// wait 10 seconds
+	for i := 0; i < 10; i++ {
+		isReady, err := d.snapshotConsistencyGroupIsReady(ctx, scg.ID)
+		if err != nil {
+			if err := d.DeleteSnapshotConsistencyGroup(ctx, scg.ID); err != nil {
+				d.Logger.Printf("failed to delete snapshot consistency group: %v\n", err)
+			}
+			return nil, fmt.Errorf("failed to wait that snapshot consistency group is ready: %w", err)
+		}
+
+		if isReady == true {
+			return d.GetSnapshotConsistencyGroup(ctx, scg.ID)
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+
+	return nil, ErrTimeoutWait
+}
+
+func (d *Device) snapshotConsistencyGroupIsReady(ctx context.Context, scgID int) (bool, error) {
+	scg, err := d.GetSnapshotConsistencyGroup(ctx, scgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get snapshot consistency group (ID: %d): %w", scgID, err)
+	}
+
+	if scg.HEALTHSTATUS != strconv.Itoa(StatusHealth) {
+		return false, fmt.Errorf("snapshot consistency group health status is bad (HEALTHSTATUS: %s)", scg.HEALTHSTATUS)
+	}
+
+	if scg.RUNNINGSTATUS == strconv.Itoa(StatusSnapshotActive) || scg.RUNNINGSTATUS == strconv.Itoa(StatusSnapshotInactive) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// DeleteSnapshotConsistencyGroup delete snapshot consistency group and member snapshots
+func (d *Device) DeleteSnapshotConsistencyGroup(ctx context.Context, scgID int) error {
+	spath := fmt.Sprintf("/snapshot_consistency_group/%d", scgID)
+
+	req, err := d.newRequest(ctx, "DELETE", spath, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// ActivateSnapshotConsistencyGroup activate all snapshots in snapshot consistency group
+func (d *Device) ActivateSnapshotConsistencyGroup(ctx context.Context, scgID int) error {
+	return d.putSnapshotConsistencyGroup(ctx, "/snapshot_consistency_group/activate", scgID, nil)
+}
+
+// StopSnapshotConsistencyGroup stop all snapshots in snapshot consistency group
+func (d *Device) StopSnapshotConsistencyGroup(ctx context.Context, scgID int) error {
+	return d.putSnapshotConsistencyGroup(ctx, "/snapshot_consistency_group/stop", scgID, nil)
+}
+
+// RollbackSnapshotConsistencyGroup start to rollback all source LUNs to snapshots in snapshot consistency group.
+// snapshot consistency group must be activated.
+func (d *Device) RollbackSnapshotConsistencyGroup(ctx context.Context, scgID int, speed Speed) error {
+	s := int(speed)
+	return d.putSnapshotConsistencyGroup(ctx, "/snapshot_consistency_group/rollback", scgID, &s)
+}
+
+// StopRollbackSnapshotConsistencyGroup stop to rollback snapshot consistency group
+func (d *Device) StopRollbackSnapshotConsistencyGroup(ctx context.Context, scgID int) error {
+	return d.putSnapshotConsistencyGroup(ctx, "/snapshot_consistency_group/stop_rollback", scgID, nil)
+}
+
+func (d *Device) putSnapshotConsistencyGroup(ctx context.Context, spath string, scgID int, rollbackSpeed *int) error {
+	param := struct {
+		ID            string `json:"ID"`
+		ROLLBACKSPEED *int   `json:"ROLLBACKSPEED,omitempty"`
+	}{
+		ID:            strconv.Itoa(scgID),
+		ROLLBACKSPEED: rollbackSpeed,
+	}
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf(ErrCreatePostValue+": %w", err)
+	}
+
+	req, err := d.newRequest(ctx, "PUT", spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err = d.requestWithRetry(req, i, DefaultHTTPRetryCount); err != nil {
+		return fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	return nil
+}
+
+// RollbackSnapshotConsistencyGroupWithWait start to rollback and wait to done.
+func (d *Device) RollbackSnapshotConsistencyGroupWithWait(ctx context.Context, scgID int, speed Speed, timeoutCount int) error {
+	if timeoutCount == 0 {
+		timeoutCount = DefaultCopyTimeoutSecond
+	}
+
+	// ROLLBACKENDTIME of snapshots is kept after previous rollback, record it to know that this rollback is done.
+	lastEndTimes, err := d.getRollbackEndTimes(ctx, scgID)
+	if err != nil {
+		return fmt.Errorf("failed to get snapshots in snapshot consistency group (ID: %d): %w", scgID, err)
+	}
+
+	err = d.RollbackSnapshotConsistencyGroup(ctx, scgID, speed)
+	if err != nil {
+		return fmt.Errorf("failed to rollback snapshot consistency group (ID: %d): %w", scgID, err)
+	}
+
+	started := false
+	for i := 0; i < timeoutCount; i++ {
+		scg, err := d.GetSnapshotConsistencyGroup(ctx, scgID)
+		if err != nil {
+			return fmt.Errorf("failed to get snapshot consistency group (ID: %d): %w", scgID, err)
+		}
+		if scg.HEALTHSTATUS != strconv.Itoa(StatusHealth) {
+			return fmt.Errorf("snapshot consistency group health status is bad (HEALTHSTATUS: %s)", scg.HEALTHSTATUS)
+		}
+
+		if scg.RUNNINGSTATUS == strconv.Itoa(StatusSnapshotRollingBack) {
+			started = true
+		} else if started {
+			return nil
+		} else {
+			// RUNNINGSTATUS is not changed to rolling back just after rollback is requested
+			endTimes, err := d.getRollbackEndTimes(ctx, scgID)
+			if err != nil {
+				return fmt.Errorf("failed to get snapshots in snapshot consistency group (ID: %d): %w", scgID, err)
+			}
+			if isAllUpdated(lastEndTimes, endTimes) {
+				return nil
+			}
+			d.Logger.Printf("rollback snapshot consistency group is not started yet (ID: %d)", scgID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
+	}
+
+	return ErrTimeoutWait
+}
+
+// getRollbackEndTimes return ROLLBACKENDTIME of snapshots in snapshot consistency group (key: snapshot ID)
+func (d *Device) getRollbackEndTimes(ctx context.Context, scgID int) (map[int]string, error) {
+	snapshots, err := d.GetSnapshotConsistencyGroupMembers(ctx, scgID)
+	if err != nil {
+		return nil, err
+	}
+
+	endTimes := make(map[int]string, len(snapshots))
+	for _, snapshot := range snapshots {
+		endTimes[snapshot.ID] = snapshot.ROLLBACKENDTIME
+	}
+
+	return endTimes, nil
+}
+
+func isAllUpdated(before, after map[int]string) bool {
+	for id, b := range before {
+		if a, ok := after[id]; !ok || a == b {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetSnapshotConsistencyGroupMembers get snapshots in snapshot consistency group
+func (d *Device) GetSnapshotConsistencyGroupMembers(ctx context.Context, scgID int) ([]Snapshot, error) {
+	query := &SearchQuery{
+		AssociateObjType: strconv.Itoa(TypeSnapshotConsistencyGroup),
+		AssociateObjID:   strconv.Itoa(scgID),
+	}
+
+	return d.GetAssociateSnapshots(ctx, query)
+}
@@ -0,0 +1,154 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDevice_GetSnapshotConsistencyGroupMembers(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/snapshot/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("ASSOCIATEOBJTYPE"); got != "57955" {
+			t.Errorf("ASSOCIATEOBJTYPE is %s, want %s", got, "57955")
+		}
+		if got := r.URL.Query().Get("ASSOCIATEOBJID"); got != "3" {
+			t.Errorf("ASSOCIATEOBJID is %s, want %s", got, "3")
+		}
+
+		fmt.Fprint(w, `
+{
+  "data": [
+    {
+      "HEALTHSTATUS": "1",
+      "ID": "21",
+      "NAME": "snap_vol1",
+      "PARENTID": "7",
+      "RUNNINGSTATUS": "43",
+      "TYPE": 27,
+      "snapCgId": "3"
+    },
+    {
+      "HEALTHSTATUS": "1",
+      "ID": "22",
+      "NAME": "snap_vol2",
+      "PARENTID": "8",
+      "RUNNINGSTATUS": "43",
+      "TYPE": 27,
+      "snapCgId": "3"
+    }
+  ],
+  "error": {
+    "code": 0,
+    "description": "0"
+  }
+}`)
+	})
+
+	snapshots, err := client.LocalDevice.GetSnapshotConsistencyGroupMembers(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("GetSnapshotConsistencyGroupMembers return err: %s", err)
+	}
+
+	want := []Snapshot{
+		{HEALTHSTATUS: "1", ID: 21, NAME: "snap_vol1", PARENTID: 7, RUNNINGSTATUS: "43", TYPE: 27, SnapCgID: "3"},
+		{HEALTHSTATUS: "1", ID: 22, NAME: "snap_vol2", PARENTID: 8, RUNNINGSTATUS: "43", TYPE: 27, SnapCgID: "3"},
+	}
+	if !reflect.DeepEqual(snapshots, want) {
+		t.Errorf("GetSnapshotConsistencyGroupMembers return %+v, want %+v", snapshots, want)
+	}
+}
+
+func TestDevice_RollbackSnapshotConsistencyGroupWithWait(t *testing.T) {
+	tests := []struct {
+		name          string
+		runningStatus []string // RUNNINGSTATUS of snapshot consistency group after rollback is requested
+		endTime       string   // ROLLBACKENDTIME of snapshots after rollback is requested
+		wantErr       error
+	}{
+		{
+			name:          "rollback is done before first poll",
+			runningStatus: []string{"43"},
+			endTime:       "1594809000",
+		},
+		{
+			name:          "rollback is in progress",
+			runningStatus: []string{"44", "43"},
+			endTime:       "1594708000",
+		},
+		{
+			name:          "rollback is not started",
+			runningStatus: []string{"43"},
+			endTime:       "1594708000",
+			wantErr:       ErrTimeoutWait,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			rollbacked := false
+			mux.HandleFunc("/snapshot_consistency_group/rollback", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "PUT")
+
+				testBody(t, r, map[string]interface{}{
+					"ID":            "3",
+					"ROLLBACKSPEED": float64(SpeedHigh),
+				})
+				rollbacked = true
+
+				fmt.Fprintf(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			})
+			runningStatus := test.runningStatus
+			mux.HandleFunc("/snapshot_consistency_group/3", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "GET")
+				status := runningStatus[0]
+				if len(runningStatus) > 1 {
+					runningStatus = runningStatus[1:]
+				}
+				fmt.Fprintf(w, `{"data": {"HEALTHSTATUS": "1", "ID": "3", "PARENTID": "5", "RUNNINGSTATUS": "%s", "TYPE": 57955}, "error": {"code": 0, "description": "0"}}`, status)
+			})
+			mux.HandleFunc("/snapshot/associate", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "GET")
+				// ROLLBACKENDTIME of previous rollback
+				endTime := "1594708000"
+				if rollbacked {
+					endTime = test.endTime
+				}
+				fmt.Fprintf(w, `{"data": [{"HEALTHSTATUS": "1", "ID": "21", "ROLLBACKENDTIME": "%s", "RUNNINGSTATUS": "43", "TYPE": 27}, {"HEALTHSTATUS": "1", "ID": "22", "ROLLBACKENDTIME": "%s", "RUNNINGSTATUS": "43", "TYPE": 27}], "error": {"code": 0, "description": "0"}}`, endTime, endTime)
+			})
+
+			err := client.LocalDevice.RollbackSnapshotConsistencyGroupWithWait(context.Background(), 3, SpeedHigh, 2)
+			if err != test.wantErr {
+				t.Errorf("RollbackSnapshotConsistencyGroupWithWait return %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestDevice_StopSnapshotConsistencyGroup(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/snapshot_consistency_group/stop", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+
+		testBody(t, r, map[string]interface{}{
+			"ID": "3",
+		})
+
+		fmt.Fprintf(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	err := client.LocalDevice.StopSnapshotConsistencyGroup(context.Background(), 3)
+	if err != nil {
+		t.Errorf("StopSnapshotConsistencyGroup return err: %s", err)
+	}
+}
@@ -0,0 +1,305 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SnapshotSchedule is schedule of snapshot policy
+type SnapshotSchedule string
+
+// SnapshotSchedule const
+const (
+	ScheduleHourly SnapshotSchedule = "hourly"
+	ScheduleDaily  SnapshotSchedule = "daily"
+	ScheduleWeekly SnapshotSchedule = "weekly"
+)
+
+// periodStart return start time of period that include now.
+// a week is started from Monday.
+func (s SnapshotSchedule) periodStart(now time.Time) (time.Time, error) {
+	y, m, d := now.Date()
+
+	switch s {
+	case ScheduleHourly:
+		return time.Date(y, m, d, now.Hour(), 0, 0, 0, now.Location()), nil
+	case ScheduleDaily:
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location()), nil
+	case ScheduleWeekly:
+		offset := (int(now.Weekday()) + 6) % 7 // days from Monday
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, now.Location()), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown snapshot schedule: %s", s)
+	}
+}
+
+// Description key of snapshot policy
+const (
+	descriptionKeyPolicy    = "policy"
+	descriptionKeyCreatedAt = "created"
+)
+
+// SnapshotPolicy is policy of scheduled snapshot
+type SnapshotPolicy struct {
+	Name      string // stored in snapshot DESCRIPTION, must be unique in a LUN
+	Schedule  SnapshotSchedule
+	Retention int // number of snapshots to keep
+}
+
+// Validate validate value of SnapshotPolicy
+func (p *SnapshotPolicy) Validate() error {
+	if p.Name == "" || strings.ContainsAny(p.Name, ";=") {
+		return fmt.Errorf("invalid snapshot policy name: %s", p.Name)
+	}
+	if _, err := p.Schedule.periodStart(time.Now()); err != nil {
+		return err
+	}
+	if p.Retention < 1 {
+		return errors.New("retention of snapshot policy must be greater than 0")
+	}
+
+	return nil
+}
+
+// PolicySnapshot is snapshot that created by snapshot policy
+type PolicySnapshot struct {
+	ID         uuid.UUID
+	SnapshotID int
+	Policy     string
+	CreatedAt  time.Time
+}
+
+// SnapshotPlan is result of PlanSnapshotPolicy
+type SnapshotPlan struct {
+	Policy  SnapshotPolicy
+	Create  bool             // true if snapshot of current period is not created yet
+	Expired []PolicySnapshot // snapshots that exceed retention
+}
+
+// PlanSnapshotPolicy decide snapshot to create and snapshots to delete at now.
+// snapshots that not created by policy are ignored.
+func PlanSnapshotPolicy(policy SnapshotPolicy, snapshots []PolicySnapshot, now time.Time) (*SnapshotPlan, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid snapshot policy: %w", err)
+	}
+	start, err := policy.Schedule.periodStart(now)
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []PolicySnapshot
+	for _, s := range snapshots {
+		if s.Policy == policy.Name {
+			owned = append(owned, s)
+		}
+	}
+	// newest first
+	sort.SliceStable(owned, func(i, j int) bool {
+		return owned[i].CreatedAt.After(owned[j].CreatedAt)
+	})
+
+	plan := &SnapshotPlan{
+		Policy: policy,
+		Create: len(owned) == 0 || owned[0].CreatedAt.Before(start),
+	}
+
+	keep := policy.Retention
+	if plan.Create {
+		// a new snapshot is counted in retention
+		keep--
+	}
+	if len(owned) > keep {
+		plan.Expired = owned[keep:]
+	}
+
+	return plan, nil
+}
+
+// SnapshotPolicyError is error of a snapshot policy in running snapshot policies
+type SnapshotPolicyError struct {
+	Policy     string
+	SnapshotID int // ID of snapshot failed to delete, 0 if failed to plan or create
+	Err        error
+}
+
+// SnapshotPolicyResult is result of running snapshot policies.
+// other policies and snapshots are processed even if some of them are failed.
+type SnapshotPolicyResult struct {
+	Created []PolicySnapshot
+	Deleted []PolicySnapshot
+	Errors  []SnapshotPolicyError
+}
+
+// err return summary of Errors, nil if no error.
+func (r *SnapshotPolicyResult) err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+
+	var messages []string
+	for _, e := range r.Errors {
+		messages = append(messages, fmt.Sprintf("policy: %s: %v", e.Policy, e.Err))
+	}
+	return fmt.Errorf("failed to run snapshot policies (%d errors): %s", len(r.Errors), strings.Join(messages, ", "))
+}
+
+// parsePolicySnapshot parse snapshot that created by snapshot policy.
+func parsePolicySnapshot(snapshot Snapshot) (*PolicySnapshot, bool) {
+	sd, err := ParseSnapshotDescription(snapshot.DESCRIPTION)
+	if err != nil {
+		return nil, false
+	}
+	policy, ok := sd.Values[descriptionKeyPolicy]
+	if !ok {
+		return nil, false
+	}
+	created, err := strconv.ParseInt(sd.Values[descriptionKeyCreatedAt], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	return &PolicySnapshot{
+		ID:         sd.VolumeSnapshotID,
+		SnapshotID: snapshot.ID,
+		Policy:     policy,
+		CreatedAt:  time.Unix(created, 0),
+	}, true
+}
+
+func policyValues(policy SnapshotPolicy, now time.Time) map[string]string {
+	return map[string]string{
+		descriptionKeyPolicy:    policy.Name,
+		descriptionKeyCreatedAt: strconv.FormatInt(now.Unix(), 10),
+	}
+}
+
+// runSnapshotPolicies execute plans of policies.
+// a runner is idempotent in a period, so call it periodically (ex: every few minutes).
+// errors are collected into result.Errors and summarized in returned error.
+func runSnapshotPolicies(policies []SnapshotPolicy, snapshots []PolicySnapshot, now time.Time,
+	createSnapshot func(policy SnapshotPolicy) (*PolicySnapshot, error),
+	deleteSnapshot func(s PolicySnapshot) error) (*SnapshotPolicyResult, error) {
+	result := &SnapshotPolicyResult{}
+
+	for _, policy := range policies {
+		plan, err := PlanSnapshotPolicy(policy, snapshots, now)
+		if err != nil {
+			result.Errors = append(result.Errors, SnapshotPolicyError{Policy: policy.Name, Err: fmt.Errorf("failed to plan snapshot policy: %w", err)})
+			continue
+		}
+
+		expired := plan.Expired
+		if plan.Create {
+			s, err := createSnapshot(policy)
+			if err != nil {
+				result.Errors = append(result.Errors, SnapshotPolicyError{Policy: policy.Name, Err: fmt.Errorf("failed to create snapshot: %w", err)})
+				// new snapshot is not counted in retention, keep the newest expired snapshot instead
+				if len(expired) > 0 {
+					expired = expired[1:]
+				}
+			} else {
+				result.Created = append(result.Created, *s)
+			}
+		}
+
+		for _, s := range expired {
+			if err := deleteSnapshot(s); err != nil {
+				result.Errors = append(result.Errors, SnapshotPolicyError{Policy: policy.Name, SnapshotID: s.SnapshotID, Err: fmt.Errorf("failed to delete expired snapshot (ID: %d): %w", s.SnapshotID, err)})
+				continue
+			}
+			result.Deleted = append(result.Deleted, s)
+		}
+	}
+
+	return result, result.err()
+}
+
+// RunSnapshotPolicies create due snapshots and delete expired snapshots of LUN.
+// policy and created time are stored in snapshot DESCRIPTION.
+// result is returned with error if some policies are failed, failed policies are in result.Errors.
+func (d *Device) RunSnapshotPolicies(ctx context.Context, lunID int, policies []SnapshotPolicy, now time.Time) (*SnapshotPolicyResult, error) {
+	snapshots, err := d.ListSnapshotsForLUN(ctx, lunID)
+	if err != nil && err != ErrSnapshotNotFound {
+		return nil, fmt.Errorf("failed to get snapshots: %w", err)
+	}
+
+	var policySnapshots []PolicySnapshot
+	for _, snapshot := range snapshots {
+		if ps, ok := parsePolicySnapshot(snapshot); ok {
+			policySnapshots = append(policySnapshots, *ps)
+		}
+	}
+
+	createSnapshot := func(policy SnapshotPolicy) (*PolicySnapshot, error) {
+		u := uuid.NewV4()
+		description := &SnapshotDescription{
+			VolumeSnapshotID: u,
+			Values:           policyValues(policy, now),
+		}
+		if err := description.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to validate snapshot description: %w", err)
+		}
+		snapshot, err := d.CreateSnapshotWithWait(ctx, lunID, u, description.Encode())
+		if err != nil {
+			return nil, err
+		}
+		if err := d.ActivateSnapshot(ctx, snapshot.ID); err != nil {
+			// inactive snapshot has DESCRIPTION of policy, so it is counted in retention if it is kept
+			if err := d.DeleteSnapshot(ctx, snapshot.ID); err != nil {
+				d.Logger.Printf("failed to delete snapshot: %v\n", err)
+			}
+			return nil, fmt.Errorf("failed to activate snapshot: %w", err)
+		}
+
+		return &PolicySnapshot{ID: u, SnapshotID: snapshot.ID, Policy: policy.Name, CreatedAt: time.Unix(now.Unix(), 0)}, nil
+	}
+	deleteSnapshot := func(s PolicySnapshot) error {
+		if err := d.StopSnapshot(ctx, s.SnapshotID); err != nil {
+			d.Logger.Printf("failed to stop snapshot: %v\n", err)
+		}
+		return d.DeleteSnapshot(ctx, s.SnapshotID)
+	}
+
+	return runSnapshotPolicies(policies, policySnapshots, now, createSnapshot, deleteSnapshot)
+}
+
+// RunVolumeSnapshotPolicies create due snapshots and delete expired snapshots of volume (= HyperMetroPair).
+// snapshots are created in primary side by CreateVolumeSnapshot.
+// result is returned with error if some policies are failed, failed policies are in result.Errors.
+func (c *Client) RunVolumeSnapshotPolicies(ctx context.Context, hyperMetroPairID string, policies []SnapshotPolicy, now time.Time) (*SnapshotPolicyResult, error) {
+	volumeSnapshots, err := c.ListVolumeSnapshots(ctx, hyperMetroPairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume snapshots: %w", err)
+	}
+
+	var policySnapshots []PolicySnapshot
+	volumeSnapshotByID := map[uuid.UUID]VolumeSnapshot{}
+	for _, vs := range volumeSnapshots {
+		if ps, ok := parsePolicySnapshot(vs.Snapshot); ok {
+			policySnapshots = append(policySnapshots, *ps)
+			volumeSnapshotByID[vs.ID] = vs
+		}
+	}
+
+	createSnapshot := func(policy SnapshotPolicy) (*PolicySnapshot, error) {
+		vs, err := c.createVolumeSnapshot(ctx, hyperMetroPairID, uuid.NewV4(), policyValues(policy, now))
+		if err != nil {
+			return nil, err
+		}
+
+		return &PolicySnapshot{ID: vs.ID, SnapshotID: vs.Snapshot.ID, Policy: policy.Name, CreatedAt: time.Unix(now.Unix(), 0)}, nil
+	}
+	deleteSnapshot := func(s PolicySnapshot) error {
+		vs := volumeSnapshotByID[s.ID]
+		return c.deleteVolumeSnapshot(ctx, &vs)
+	}
+
+	return runSnapshotPolicies(policies, policySnapshots, now, createSnapshot, deleteSnapshot)
+}
@@ -0,0 +1,216 @@
+package dorado
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlanSnapshotPolicy(t *testing.T) {
+	// Wednesday
+	now := time.Date(2020, 7, 15, 10, 30, 0, 0, time.UTC)
+
+	snapshot := func(policy string, createdAt time.Time) PolicySnapshot {
+		return PolicySnapshot{Policy: policy, CreatedAt: createdAt}
+	}
+
+	tests := []struct {
+		name        string
+		policy      SnapshotPolicy
+		snapshots   []PolicySnapshot
+		wantCreate  bool
+		wantExpired []PolicySnapshot
+	}{
+		{
+			name:       "first snapshot",
+			policy:     SnapshotPolicy{Name: "hourly", Schedule: ScheduleHourly, Retention: 3},
+			wantCreate: true,
+		},
+		{
+			name:   "already created in this hour",
+			policy: SnapshotPolicy{Name: "hourly", Schedule: ScheduleHourly, Retention: 3},
+			snapshots: []PolicySnapshot{
+				snapshot("hourly", now.Add(-20*time.Minute)),
+				snapshot("hourly", now.Add(-80*time.Minute)),
+			},
+			wantCreate: false,
+		},
+		{
+			name:   "expire oldest snapshots",
+			policy: SnapshotPolicy{Name: "hourly", Schedule: ScheduleHourly, Retention: 2},
+			snapshots: []PolicySnapshot{
+				snapshot("hourly", now.Add(-150*time.Minute)),
+				snapshot("hourly", now.Add(-90*time.Minute)),
+				snapshot("hourly", now.Add(-210*time.Minute)),
+			},
+			wantCreate: true,
+			wantExpired: []PolicySnapshot{
+				snapshot("hourly", now.Add(-150*time.Minute)),
+				snapshot("hourly", now.Add(-210*time.Minute)),
+			},
+		},
+		{
+			name:   "ignore other policy",
+			policy: SnapshotPolicy{Name: "daily", Schedule: ScheduleDaily, Retention: 1},
+			snapshots: []PolicySnapshot{
+				snapshot("hourly", now.Add(-5*time.Minute)),
+				snapshot("daily", now.Add(-2*time.Hour)),
+				snapshot("daily", now.Add(-26*time.Hour)),
+			},
+			wantCreate: false,
+			wantExpired: []PolicySnapshot{
+				snapshot("daily", now.Add(-26*time.Hour)),
+			},
+		},
+		{
+			name:   "weekly is started from Monday",
+			policy: SnapshotPolicy{Name: "weekly", Schedule: ScheduleWeekly, Retention: 4},
+			snapshots: []PolicySnapshot{
+				// Sunday
+				snapshot("weekly", time.Date(2020, 7, 12, 23, 0, 0, 0, time.UTC)),
+			},
+			wantCreate: true,
+		},
+		{
+			name:   "weekly already created",
+			policy: SnapshotPolicy{Name: "weekly", Schedule: ScheduleWeekly, Retention: 4},
+			snapshots: []PolicySnapshot{
+				// Monday
+				snapshot("weekly", time.Date(2020, 7, 13, 0, 0, 0, 0, time.UTC)),
+			},
+			wantCreate: false,
+		},
+	}
+
+	for _, test := range tests {
+		plan, err := PlanSnapshotPolicy(test.policy, test.snapshots, now)
+		if err != nil {
+			t.Fatalf("%s: PlanSnapshotPolicy return err: %s", test.name, err)
+		}
+
+		if plan.Create != test.wantCreate {
+			t.Errorf("%s: Create is %t, want %t", test.name, plan.Create, test.wantCreate)
+		}
+		if !reflect.DeepEqual(plan.Expired, test.wantExpired) {
+			t.Errorf("%s: Expired is %+v, want %+v", test.name, plan.Expired, test.wantExpired)
+		}
+	}
+}
+
+func TestPlanSnapshotPolicy_Invalid(t *testing.T) {
+	now := time.Date(2020, 7, 15, 10, 30, 0, 0, time.UTC)
+
+	for _, policy := range []SnapshotPolicy{
+		{Name: "", Schedule: ScheduleDaily, Retention: 1},
+		{Name: "a;b", Schedule: ScheduleDaily, Retention: 1},
+		{Name: "monthly", Schedule: "monthly", Retention: 1},
+		{Name: "daily", Schedule: ScheduleDaily, Retention: 0},
+	} {
+		if _, err := PlanSnapshotPolicy(policy, nil, now); err == nil {
+			t.Errorf("PlanSnapshotPolicy(%+v) must return err", policy)
+		}
+	}
+}
+
+func TestRunSnapshotPolicies_ContinueOnError(t *testing.T) {
+	now := time.Date(2020, 7, 15, 10, 30, 0, 0, time.UTC)
+
+	policies := []SnapshotPolicy{
+		{Name: "invalid", Schedule: "monthly", Retention: 1},
+		{Name: "hourly", Schedule: ScheduleHourly, Retention: 1},
+		{Name: "daily", Schedule: ScheduleDaily, Retention: 1},
+	}
+	snapshots := []PolicySnapshot{
+		{SnapshotID: 1, Policy: "hourly", CreatedAt: now.Add(-2 * time.Hour)},
+		{SnapshotID: 2, Policy: "hourly", CreatedAt: now.Add(-3 * time.Hour)},
+		{SnapshotID: 3, Policy: "hourly", CreatedAt: now.Add(-4 * time.Hour)},
+	}
+
+	createSnapshot := func(policy SnapshotPolicy) (*PolicySnapshot, error) {
+		if policy.Name == "hourly" {
+			return nil, errors.New("injected failure")
+		}
+		return &PolicySnapshot{SnapshotID: 10, Policy: policy.Name, CreatedAt: now}, nil
+	}
+	deleteSnapshot := func(s PolicySnapshot) error {
+		if s.SnapshotID == 2 {
+			return errors.New("injected failure")
+		}
+		return nil
+	}
+
+	result, err := runSnapshotPolicies(policies, snapshots, now, createSnapshot, deleteSnapshot)
+	if err == nil {
+		t.Errorf("runSnapshotPolicies must return err")
+	}
+
+	var gotErrors []SnapshotPolicyError
+	for _, e := range result.Errors {
+		gotErrors = append(gotErrors, SnapshotPolicyError{Policy: e.Policy, SnapshotID: e.SnapshotID})
+	}
+	wantErrors := []SnapshotPolicyError{
+		{Policy: "invalid"},
+		{Policy: "hourly"},
+		{Policy: "hourly", SnapshotID: 2},
+	}
+	if !reflect.DeepEqual(gotErrors, wantErrors) {
+		t.Errorf("Errors is %+v, want %+v", gotErrors, wantErrors)
+	}
+	if len(result.Created) != 1 || result.Created[0].Policy != "daily" {
+		t.Errorf("Created is %+v, want snapshot of daily", result.Created)
+	}
+	// snapshot 1 is kept because new snapshot of hourly is not created
+	if len(result.Deleted) != 1 || result.Deleted[0].SnapshotID != 3 {
+		t.Errorf("Deleted is %+v, want snapshot 3", result.Deleted)
+	}
+}
+
+func TestDevice_RunSnapshotPolicies_ActivateError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	now := time.Date(2020, 7, 15, 10, 30, 0, 0, time.UTC)
+
+	mux.HandleFunc("/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+		case "POST":
+			fmt.Fprint(w, `{"data": {"ID": "15", "PARENTID": "12", "PARENTTYPE": 11, "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+		default:
+			t.Errorf("Request method: %v, want GET or POST", r.Method)
+		}
+	})
+	deleted := false
+	mux.HandleFunc("/snapshot/15", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"data": {"ID": "15", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "45", "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+		case "DELETE":
+			deleted = true
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+		default:
+			t.Errorf("Request method: %v, want GET or DELETE", r.Method)
+		}
+	})
+	mux.HandleFunc("/snapshot/activate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 1077949001, "description": "internal error"}}`)
+	})
+
+	policies := []SnapshotPolicy{{Name: "hourly", Schedule: ScheduleHourly, Retention: 1}}
+	result, err := client.LocalDevice.RunSnapshotPolicies(context.Background(), 12, policies, now)
+	if err == nil {
+		t.Fatalf("RunSnapshotPolicies must return err if failed to activate snapshot")
+	}
+	if len(result.Created) != 0 || len(result.Errors) != 1 {
+		t.Errorf("RunSnapshotPolicies return %+v", result)
+	}
+	if !deleted {
+		t.Errorf("snapshot that failed to activate must be deleted")
+	}
+}
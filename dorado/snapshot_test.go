@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+
+	uuid "github.com/satori/go.uuid"
 )
 
 func TestDevice_GetSnapshots(t *testing.T) {
@@ -176,3 +178,154 @@ func TestDevice_GetSnapshots(t *testing.T) {
 		t.Errorf("GetSnapshots return %+v, want %+v", snapshots, want)
 	}
 }
+
+func TestDevice_RollbackSnapshotWithWait(t *testing.T) {
+	tests := []struct {
+		name   string
+		status []string // RUNNINGSTATUS and ROLLBACKENDTIME after rollback is requested
+	}{
+		{
+			name:   "rollback is done before first poll",
+			status: []string{"43", "1594809000"},
+		},
+		{
+			name:   "rollback is started after first poll",
+			status: []string{"43", "1594708000", "44", "1594708000", "43", "1594809000"},
+		},
+		{
+			name:   "rollback is in progress",
+			status: []string{"44", "1594708000", "43", "1594708000"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			rollbacked := false
+			mux.HandleFunc("/snapshot/rollback", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "PUT")
+
+				testBody(t, r, map[string]interface{}{
+					"ID":            "15",
+					"ROLLBACKSPEED": float64(SpeedHighest),
+				})
+				rollbacked = true
+
+				fmt.Fprintf(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			})
+			status := test.status
+			mux.HandleFunc("/snapshot/15", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "GET")
+				// ROLLBACKENDTIME of previous rollback
+				runningStatus, endTime := "43", "1594708000"
+				if rollbacked {
+					runningStatus, endTime = status[0], status[1]
+					if len(status) > 2 {
+						status = status[2:]
+					}
+				}
+				fmt.Fprintf(w, `{"data": {"HEALTHSTATUS": "1", "ID": "15", "ROLLBACKENDTIME": "%s", "ROLLBACKRATE": "100", "RUNNINGSTATUS": "%s", "TYPE": 27}, "error": {"code": 0, "description": "0"}}`, endTime, runningStatus)
+			})
+
+			err := client.LocalDevice.RollbackSnapshotWithWait(context.Background(), 15, SpeedHighest, 3)
+			if err != nil {
+				t.Errorf("RollbackSnapshotWithWait return err: %s", err)
+			}
+		})
+	}
+}
+
+func TestDevice_RollbackSnapshotWithWait_NotStarted(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/snapshot/rollback", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		fmt.Fprintf(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/snapshot/15", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"data": {"HEALTHSTATUS": "1", "ID": "15", "ROLLBACKENDTIME": "1594708000", "ROLLBACKRATE": "100", "RUNNINGSTATUS": "43", "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	err := client.LocalDevice.RollbackSnapshotWithWait(context.Background(), 15, SpeedHighest, 1)
+	if err != ErrTimeoutWait {
+		t.Errorf("RollbackSnapshotWithWait return %v, want %v", err, ErrTimeoutWait)
+	}
+}
+
+func TestDevice_RollbackSnapshotWithWait_Canceled(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mux.HandleFunc("/snapshot/rollback", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		fmt.Fprintf(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+		cancel()
+	})
+	mux.HandleFunc("/snapshot/15", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"data": {"HEALTHSTATUS": "1", "ID": "15", "RUNNINGSTATUS": "43", "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	err := client.LocalDevice.RollbackSnapshotWithWait(ctx, 15, SpeedHighest, 100)
+	if err == nil {
+		t.Errorf("RollbackSnapshotWithWait must return err if context is canceled")
+	}
+}
+
+func TestDevice_CreateCascadedSnapshot(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	u := uuid.FromStringOrNil("77bea474-0f5b-4f1a-9e6e-0b1f0a4b7c2d")
+
+	mux.HandleFunc("/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		testBody(t, r, map[string]interface{}{
+			"TYPE":        "27",
+			"NAME":        EncodeSnapshotName(u),
+			"PARENTTYPE":  "27",
+			"PARENTID":    "12",
+			"DESCRIPTION": "backup",
+		})
+
+		fmt.Fprintf(w, `{"data": {"ID": "15", "PARENTID": "12", "PARENTTYPE": 27, "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	snapshot, err := client.LocalDevice.CreateCascadedSnapshot(context.Background(), 12, u, "backup")
+	if err != nil {
+		t.Fatalf("CreateCascadedSnapshot return err: %s", err)
+	}
+
+	want := &Snapshot{ID: 15, PARENTID: 12, PARENTTYPE: TypeSnapshot, TYPE: TypeSnapshot}
+	if !reflect.DeepEqual(snapshot, want) {
+		t.Errorf("CreateCascadedSnapshot return %+v, want %+v", snapshot, want)
+	}
+}
+
+func TestClient_RestoreVolumeFromSnapshot_InCG(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/HyperMetroPair/2a4c2d1100eaf02c0000000000000001", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "2a4c2d1100eaf02c0000000000000001", "LOCALOBJID": "517", "REMOTEOBJID": "514", "ISPRIMARY": "true", "ISINCG": "true", "CGID": "5", "RUNNINGSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/snapshot/30", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "30", "PARENTID": "517", "RUNNINGSTATUS": "43"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/HyperMetroPair/disable_hcpair", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("HyperMetroPair in consistency group must not be suspended alone")
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	if err := client.RestoreVolumeFromSnapshot(context.Background(), "2a4c2d1100eaf02c0000000000000001", 30, SpeedHighest); err == nil {
+		t.Errorf("RestoreVolumeFromSnapshot must return err if HyperMetroPair is in consistency group")
+	}
+}
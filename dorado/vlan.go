@@ -0,0 +1,41 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+)
+
+// VLAN is VLAN sub-interface of ethernet port or bond port
+type VLAN struct {
+	HEALTHSTATUS  string `json:"HEALTHSTATUS"`
+	ID            string `json:"ID"`
+	MTU           string `json:"MTU"`
+	NAME          string `json:"NAME"`
+	PORTID        string `json:"PORTID"`
+	PORTTYPE      string `json:"PORTTYPE"`
+	RUNNINGSTATUS string `json:"RUNNINGSTATUS"`
+	TAG           string `json:"TAG"`
+	TYPE          int    `json:"TYPE"`
+}
+
+// GetVLANs get VLANs by query
+func (d *Device) GetVLANs(ctx context.Context, query *SearchQuery) ([]VLAN, error) {
+	spath := "/vlan"
+
+	req, err := d.newRequest(ctx, "GET", spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest+": %w", err)
+	}
+	req = AddSearchQuery(req, query)
+
+	var vlans []VLAN
+	if err = d.requestWithRetry(req, &vlans, DefaultHTTPRetryCount); err != nil {
+		return nil, fmt.Errorf(ErrRequestWithRetry+": %w", err)
+	}
+
+	if len(vlans) == 0 {
+		return nil, ErrVLANNotFound
+	}
+
+	return vlans, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"time"
 
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
@@ -13,6 +12,10 @@ import (
 
 // CreateVolumeRaw create blank HyperMetroPair
 func (c *Client) CreateVolumeRaw(ctx context.Context, name uuid.UUID, capacityGB int, storagePoolName, hyperMetroDomainID string) (*HyperMetroPair, error) {
+	if err := c.requireProtectionMode(ProtectionHyperMetro); err != nil {
+		return nil, err
+	}
+
 	// create volume (= hypermetro enabled lun)
 	localLun, err := c.LocalDevice.CreateLUN(ctx, name, capacityGB, storagePoolName)
 	if err != nil {
@@ -34,37 +37,64 @@ func (c *Client) CreateVolumeRaw(ctx context.Context, name uuid.UUID, capacityGB
 
 // CreateVolumeFromSource create HyperMetroPair to copy from sourceHyperMetroPairID
 func (c *Client) CreateVolumeFromSource(ctx context.Context, name uuid.UUID, capacityGB int, storagePoolName, hyperMetroDomainID string, sourceHyperMetroPairID string) (*HyperMetroPair, error) {
+	return c.CreateVolumeFromSourceWithOption(ctx, name, capacityGB, storagePoolName, hyperMetroDomainID, sourceHyperMetroPairID, nil)
+}
+
+// CreateVolumeFromSourceWithOption create HyperMetroPair to copy from sourceHyperMetroPairID by LUN Clone with option.
+// use SplitLater for linked clone, and split by SplitVolumeClone later.
+// created LUNs are deleted on error, even if clone LUN is splitting yet.
+func (c *Client) CreateVolumeFromSourceWithOption(ctx context.Context, name uuid.UUID, capacityGB int, storagePoolName, hyperMetroDomainID string, sourceHyperMetroPairID string, opt *CloneOption) (*HyperMetroPair, error) {
+	if err := c.requireProtectionMode(ProtectionHyperMetro); err != nil {
+		return nil, err
+	}
+
 	source, err := c.GetHyperMetroPair(ctx, sourceHyperMetroPairID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get source HyperMetroPair: %w", err)
 	}
 
-	var localLUNID, remoteLUNID int
+	// clone LUN is returned with ErrTimeoutWait if it is splitting yet, so record it to delete.
+	var localLUN, remoteLUN *LUN
 	eg := errgroup.Group{}
 	eg.Go(func() error {
-		localLun, err := c.LocalDevice.CreateLUNFromSource(ctx, source.LOCALOBJID, name, capacityGB, storagePoolName)
+		lun, err := c.LocalDevice.CreateLUNFromSourceByLUNCloneWithOption(ctx, source.LOCALOBJID, name, capacityGB, opt)
+		localLUN = lun
 		if err != nil {
 			return fmt.Errorf("failed to crteate lun from source in local device: %w", err)
 		}
 
-		localLUNID = localLun.ID
 		return nil
 	})
 	eg.Go(func() error {
-		remoteLun, err := c.RemoteDevice.CreateLUNFromSource(ctx, source.REMOTEOBJID, name, capacityGB, storagePoolName)
+		lun, err := c.RemoteDevice.CreateLUNFromSourceByLUNCloneWithOption(ctx, source.REMOTEOBJID, name, capacityGB, opt)
+		remoteLUN = lun
 		if err != nil {
 			return fmt.Errorf("failed to crteate lun from source in remote device: %w", err)
 		}
 
-		remoteLUNID = remoteLun.ID
 		return nil
 	})
 
-	if err := eg.Wait(); err != nil {
+	err = eg.Wait()
+	defer func() {
+		if err != nil {
+			if localLUN != nil {
+				if err := c.LocalDevice.deleteCloneLUN(ctx, localLUN); err != nil {
+					c.LocalDevice.Logger.Printf("failed to delete LUN: %v", err)
+				}
+			}
+			if remoteLUN != nil {
+				if err := c.RemoteDevice.deleteCloneLUN(ctx, remoteLUN); err != nil {
+					c.RemoteDevice.Logger.Printf("failed to delete LUN: %v", err)
+				}
+			}
+		}
+	}()
+	if err != nil {
 		return nil, fmt.Errorf("failed to create lun from source: %w", err)
 	}
 
-	hyperMetroPair, err := c.CreateHyperMetroPair(ctx, hyperMetroDomainID, localLUNID, remoteLUNID)
+	hyperMetroPair, err := c.CreateHyperMetroPair(ctx, hyperMetroDomainID, localLUN.ID, remoteLUN.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HyperMetroPair from source: %w", err)
 	}
@@ -72,50 +102,32 @@ func (c *Client) CreateVolumeFromSource(ctx context.Context, name uuid.UUID, cap
 	return hyperMetroPair, nil
 }
 
-// CreateLUNFromSource create lun from source lun
-// low level function for CreateVolumeFromSource
-func (d *Device) CreateLUNFromSource(ctx context.Context, sourceLUNID int, name uuid.UUID, capacityGB int, storagePoolName string) (*LUN, error) {
-	return d.CreateLUNFromSourceByLUNClone(ctx, sourceLUNID, name, capacityGB)
-}
-
-// CreateLUNFromSourceByLUNClone create lun from source lun by LUN Clone.
-func (d *Device) CreateLUNFromSourceByLUNClone(ctx context.Context, sourceLUNID int, name uuid.UUID, capacityGB int) (*LUN, error) {
-	cloneLUN, err := d.CreateCloneLUN(ctx, sourceLUNID, name)
+// SplitVolumeClone start to split clone LUNs of HyperMetroPair in local device and remote device.
+func (c *Client) SplitVolumeClone(ctx context.Context, hyperMetroPairID string, speed Speed) error {
+	hmp, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create clone LUN: %w", err)
+		return fmt.Errorf("failed to get HyperMetroPair: %w", err)
 	}
-	defer func() {
-		if err != nil {
-			if err := d.DeleteLUN(ctx, cloneLUN.ID); err != nil {
-				d.Logger.Printf("failed to delete LUN: %v", err)
-			}
-		}
-	}()
 
-	if cloneLUN.CAPACITY < capacityGB*CapacityUnit {
-		if err := d.ExpandLUN(ctx, cloneLUN.ID, capacityGB); err != nil {
-			return nil, fmt.Errorf("failed to expand LUN: %w", err)
-		}
+	if err := c.LocalDevice.SplitCloneLUNWithSpeed(ctx, hmp.LOCALOBJID, speed); err != nil {
+		return fmt.Errorf("failed to split clone LUN in local device: %w", err)
 	}
-
-	if err := d.SplitCloneLUN(ctx, cloneLUN.ID); err != nil {
-		return nil, fmt.Errorf("failed to split clone LUN: %w", err)
+	if err := c.RemoteDevice.SplitCloneLUNWithSpeed(ctx, hmp.REMOTEOBJID, speed); err != nil {
+		return fmt.Errorf("failed to split clone LUN in remote device: %w", err)
 	}
 
-	for i := 0; i < DefaultCopyTimeoutSecond; i++ {
-		isReady, err := d.lunIsReady(ctx, cloneLUN.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to wait that LUN is ready: %w", err)
-		}
-
-		if isReady == true {
-			return d.GetLUN(ctx, cloneLUN.ID)
-		}
+	return nil
+}
 
-		time.Sleep(1 * time.Second)
-	}
+// CreateLUNFromSource create lun from source lun
+// low level function for CreateVolumeFromSource
+func (d *Device) CreateLUNFromSource(ctx context.Context, sourceLUNID int, name uuid.UUID, capacityGB int, storagePoolName string) (*LUN, error) {
+	return d.CreateLUNFromSourceByLUNClone(ctx, sourceLUNID, name, capacityGB)
+}
 
-	return nil, ErrTimeoutWait
+// CreateLUNFromSourceByLUNClone create lun from source lun by LUN Clone.
+func (d *Device) CreateLUNFromSourceByLUNClone(ctx context.Context, sourceLUNID int, name uuid.UUID, capacityGB int) (*LUN, error) {
+	return d.CreateLUNFromSourceByLUNCloneWithOption(ctx, sourceLUNID, name, capacityGB, nil)
 }
 
 // CreateLUNFromSourceByLUNCopy create lun from source lun by LUN Copy.
@@ -139,12 +151,17 @@ func (d *Device) CreateLUNFromSourceByLUNCopy(ctx context.Context, sourceLUNID i
 		return nil, fmt.Errorf("failed to activate snapshot: %w", err)
 	}
 
+	return d.CreateLUNFromSnapshot(ctx, snapshot.ID, name, capacityGB, storagePoolName)
+}
+
+// CreateLUNFromSnapshot create lun from activated snapshot by LUN Copy.
+func (d *Device) CreateLUNFromSnapshot(ctx context.Context, snapshotID int, name uuid.UUID, capacityGB int, storagePoolName string) (*LUN, error) {
 	targetLUN, err := d.CreateLUNWithWait(ctx, name, capacityGB, storagePoolName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create raw LUN: %w", err)
 	}
 
-	luncopy, err := d.CreateLUNCopy(ctx, snapshot.ID, targetLUN.ID)
+	luncopy, err := d.CreateLUNCopy(ctx, snapshotID, targetLUN.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create luncopy object: %w", err)
 	}
@@ -161,6 +178,127 @@ func (d *Device) CreateLUNFromSourceByLUNCopy(ctx context.Context, sourceLUNID i
 	return d.GetLUN(ctx, targetLUN.ID)
 }
 
+// SnapshotCopyMethod is method to create LUN from snapshot
+type SnapshotCopyMethod int
+
+// SnapshotCopyMethod const
+const (
+	// SnapshotCopyByLUNCopy copy snapshot to new LUN in storagePoolName by LUN Copy
+	SnapshotCopyByLUNCopy SnapshotCopyMethod = iota
+	// SnapshotCopyByLUNClone create clone LUN of snapshot in storage pool of snapshot, storagePoolName is used only for other device
+	SnapshotCopyByLUNClone
+)
+
+// VolumeFromSnapshotOption is option of CreateVolumeFromSnapshotWithOption
+type VolumeFromSnapshotOption struct {
+	Method SnapshotCopyMethod
+	Clone  *CloneOption // only for SnapshotCopyByLUNClone, nil is NewCloneOption()
+}
+
+// CreateVolumeFromSnapshot create HyperMetroPair from snapshot in local device.
+// copy snapshot to local LUN, and remote LUN is seeded by initial sync of HyperMetro.
+func (c *Client) CreateVolumeFromSnapshot(ctx context.Context, name uuid.UUID, snapshotID int, capacityGB int, storagePoolName, hyperMetroDomainID string) (*HyperMetroPair, error) {
+	return c.CreateVolumeFromSnapshotWithOption(ctx, name, snapshotID, capacityGB, storagePoolName, hyperMetroDomainID, nil)
+}
+
+// CreateVolumeFromSnapshotWithOption create HyperMetroPair from snapshot in local device with option.
+// nil opt is SnapshotCopyByLUNCopy.
+func (c *Client) CreateVolumeFromSnapshotWithOption(ctx context.Context, name uuid.UUID, snapshotID int, capacityGB int, storagePoolName, hyperMetroDomainID string, opt *VolumeFromSnapshotOption) (*HyperMetroPair, error) {
+	return c.createVolumeFromSnapshot(ctx, name, c.LocalDevice, c.RemoteDevice, snapshotID, capacityGB, storagePoolName, hyperMetroDomainID, opt)
+}
+
+// CreateVolumeFromVolumeSnapshot create HyperMetroPair from snapshot of volume.
+// copy snapshot in the device that has snapshot, and other device is seeded by initial sync of HyperMetro.
+func (c *Client) CreateVolumeFromVolumeSnapshot(ctx context.Context, name uuid.UUID, hyperMetroPairID string, snapshotUUID uuid.UUID, capacityGB int, storagePoolName, hyperMetroDomainID string) (*HyperMetroPair, error) {
+	return c.CreateVolumeFromVolumeSnapshotWithOption(ctx, name, hyperMetroPairID, snapshotUUID, capacityGB, storagePoolName, hyperMetroDomainID, nil)
+}
+
+// CreateVolumeFromVolumeSnapshotWithOption create HyperMetroPair from snapshot of volume with option.
+// nil opt is SnapshotCopyByLUNCopy.
+func (c *Client) CreateVolumeFromVolumeSnapshotWithOption(ctx context.Context, name uuid.UUID, hyperMetroPairID string, snapshotUUID uuid.UUID, capacityGB int, storagePoolName, hyperMetroDomainID string, opt *VolumeFromSnapshotOption) (*HyperMetroPair, error) {
+	vs, err := c.GetVolumeSnapshot(ctx, hyperMetroPairID, snapshotUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume snapshot: %w", err)
+	}
+
+	if vs.IsLocal {
+		return c.createVolumeFromSnapshot(ctx, name, c.LocalDevice, c.RemoteDevice, vs.Snapshot.ID, capacityGB, storagePoolName, hyperMetroDomainID, opt)
+	}
+	return c.createVolumeFromSnapshot(ctx, name, c.RemoteDevice, c.LocalDevice, vs.Snapshot.ID, capacityGB, storagePoolName, hyperMetroDomainID, opt)
+}
+
+// createVolumeFromSnapshot create LUN from snapshot in source device, blank LUN in target device,
+// and create HyperMetroPair in source device with first sync.
+// snapshot is activated if needed, and deactivated on exit unless created LUN is linked to snapshot.
+func (c *Client) createVolumeFromSnapshot(ctx context.Context, name uuid.UUID, source, target *Device, snapshotID int, capacityGB int, storagePoolName, hyperMetroDomainID string, opt *VolumeFromSnapshotOption) (*HyperMetroPair, error) {
+	if err := c.requireProtectionMode(ProtectionHyperMetro); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := source.GetSnapshot(ctx, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	isClone := opt != nil && opt.Method == SnapshotCopyByLUNClone
+	if snapshot.RUNNINGSTATUS != strconv.Itoa(StatusSnapshotActive) {
+		if err := source.ActivateSnapshot(ctx, snapshot.ID); err != nil {
+			return nil, fmt.Errorf("failed to activate snapshot: %w", err)
+		}
+		defer func() {
+			// linked clone LUN and splitting clone LUN need activated snapshot
+			if err == nil && isClone && (opt.Clone == nil || opt.Clone.SplitMode != SplitNow) {
+				return
+			}
+			if err := source.StopSnapshot(ctx, snapshot.ID); err != nil {
+				source.Logger.Printf("failed to deactivate snapshot: %v", err)
+			}
+		}()
+	}
+
+	var sourceLUN *LUN
+	if isClone {
+		sourceLUN, err = source.CreateLUNFromSnapshotByLUNCloneWithOption(ctx, snapshot.ID, name, capacityGB, opt.Clone)
+	} else {
+		sourceLUN, err = source.CreateLUNFromSnapshot(ctx, snapshot.ID, name, capacityGB, storagePoolName)
+	}
+	// clone LUN is returned with ErrTimeoutWait if it is splitting yet
+	if sourceLUN != nil {
+		defer func() {
+			if err != nil {
+				if err := source.deleteCloneLUN(ctx, sourceLUN); err != nil {
+					source.Logger.Printf("failed to delete LUN: %v", err)
+				}
+			}
+		}()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lun from snapshot: %w", err)
+	}
+
+	targetLUN, err := target.CreateLUNWithWait(ctx, name, capacityGB, storagePoolName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create raw lun: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if err := target.DeleteLUN(ctx, targetLUN.ID); err != nil {
+				target.Logger.Printf("failed to delete LUN: %v", err)
+			}
+		}
+	}()
+
+	hmpOpt := NewHyperMetroPairOption()
+	hmpOpt.IsFirstSync = true // seed data from source LUN
+	param := newHyperMetroPairParam(hyperMetroDomainID, sourceLUN.ID, targetLUN.ID, hmpOpt)
+	hmp, err := source.createHyperMetroPair(ctx, param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HyperMetroPair: %w", err)
+	}
+
+	// return HyperMetroPair in a view of local device
+	return c.GetHyperMetroPair(ctx, hmp.ID)
+}
+
 // DeleteVolume delete HyperMetroPair
 func (c *Client) DeleteVolume(ctx context.Context, hyperMetroPairID string) error {
 	// 1: delete HyperMetro Pair
@@ -204,6 +342,20 @@ func (c *Client) DeleteVolume(ctx context.Context, hyperMetroPairID string) erro
 	}
 
 	// 1: delete HyperMetro Pair
+	if hmp.ISINCG == "true" {
+		err = c.modifyHyperMetroCG(ctx, hmp.CGID, func() error {
+			return c.RemoveHyperMetroPairFromCG(ctx, hmp.CGID, hmp.ID)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove HyperMetroPair from consistency group: %w", err)
+		}
+
+		// RUNNINGSTATUS is changed by suspending group
+		hmp, err = c.GetHyperMetroPair(ctx, hyperMetroPairID)
+		if err != nil {
+			return fmt.Errorf("failed to get HyperMetro Pair: %w", err)
+		}
+	}
 	if hmp.RUNNINGSTATUS != strconv.Itoa(StatusPause) {
 		err = c.SuspendHyperMetroPair(ctx, hmp.ID)
 		if err != nil {
@@ -228,7 +380,8 @@ func (c *Client) DeleteVolume(ctx context.Context, hyperMetroPairID string) erro
 	return nil
 }
 
-// ExtendVolume expand HyperMetroPair
+// ExtendVolume expand HyperMetroPair.
+// HyperMetro consistency group is suspended and re-synced if HyperMetroPair is in group.
 func (c *Client) ExtendVolume(ctx context.Context, hyperMetroPairID string, newVolumeSizeGb int) error {
 	// 1: Suspend HyperMetro Pair
 	// 2: Expand LUN
@@ -240,7 +393,7 @@ func (c *Client) ExtendVolume(ctx context.Context, hyperMetroPairID string, newV
 		return fmt.Errorf("failed to get HyperMetro Pair: %w", err)
 	}
 
-	err = c.SuspendHyperMetroPair(ctx, hmp.ID)
+	err = c.suspendHyperMetroPairOrCG(ctx, hmp)
 	if err != nil {
 		return fmt.Errorf("failed to suspend HyperMetroPair: %w", err)
 	}
@@ -256,7 +409,7 @@ func (c *Client) ExtendVolume(ctx context.Context, hyperMetroPairID string, newV
 	}
 
 	// 3: Re-sync HyperMetro Pair
-	err = c.SyncHyperMetroPair(ctx, hmp.ID)
+	err = c.syncHyperMetroPairOrCG(ctx, hmp)
 	if err != nil {
 		return fmt.Errorf("failed to re-sync HyperMetro Pair: %w", err)
 	}
@@ -264,6 +417,58 @@ func (c *Client) ExtendVolume(ctx context.Context, hyperMetroPairID string, newV
 	return nil
 }
 
+// RetypeVolumeError is error of RetypeVolume, LUNs of volume may be in different storage pools.
+// call RetypeVolume again to retry, LUN that is already moved is skipped.
+type RetypeVolumeError struct {
+	LocalMoved  bool
+	RemoteMoved bool
+	Err         error
+}
+
+// Error is function compatible for error
+func (e *RetypeVolumeError) Error() string {
+	return fmt.Sprintf("failed to retype volume (local moved: %t, remote moved: %t): %v", e.LocalMoved, e.RemoteMoved, e.Err)
+}
+
+// Unwrap return underlying error
+func (e *RetypeVolumeError) Unwrap() error {
+	return e.Err
+}
+
+// RetypeVolume move LUNs of HyperMetroPair to other storage pool by SmartMigration.
+// volume can be attached while retype, HyperMetroPair is not changed.
+// if migration of one side is failed, *RetypeVolumeError is returned.
+func (c *Client) RetypeVolume(ctx context.Context, hyperMetroPairID string, storagePoolName string, speed Speed) error {
+	hmp, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+	if err != nil {
+		return fmt.Errorf("failed to get HyperMetro Pair: %w", err)
+	}
+
+	result := &RetypeVolumeError{}
+	eg := errgroup.Group{}
+	eg.Go(func() error {
+		if _, err := c.LocalDevice.MigrateLUNToPool(ctx, hmp.LOCALOBJID, storagePoolName, speed); err != nil {
+			return fmt.Errorf("failed to migrate Local LUN: %w", err)
+		}
+		result.LocalMoved = true
+		return nil
+	})
+	eg.Go(func() error {
+		if _, err := c.RemoteDevice.MigrateLUNToPool(ctx, hmp.REMOTEOBJID, storagePoolName, speed); err != nil {
+			return fmt.Errorf("failed to migrate Remote LUN: %w", err)
+		}
+		result.RemoteMoved = true
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	return nil
+}
+
 // AttachVolume create mapping to host
 func (c *Client) AttachVolume(ctx context.Context, hyperMetroPairID, hostname, iqn string) error {
 	volume, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
@@ -271,11 +476,11 @@ func (c *Client) AttachVolume(ctx context.Context, hyperMetroPairID, hostname, i
 		return fmt.Errorf("failed to get volume information: %w", err)
 	}
 
-	err = c.LocalDevice.AttachVolume(ctx, c.PortGroupName, hostname, iqn, volume.LOCALOBJID)
+	err = c.LocalDevice.AttachVolume(ctx, c.portGroupName(hostname), hostname, iqn, volume.LOCALOBJID)
 	if err != nil {
 		return fmt.Errorf("failed to attach volume in Local Device: %w", err)
 	}
-	err = c.RemoteDevice.AttachVolume(ctx, c.PortGroupName, hostname, iqn, volume.REMOTEOBJID)
+	err = c.RemoteDevice.AttachVolume(ctx, c.portGroupName(hostname), hostname, iqn, volume.REMOTEOBJID)
 	if err != nil {
 		return fmt.Errorf("failed to attach volume in Remote Device: %w", err)
 	}
@@ -283,59 +488,323 @@ func (c *Client) AttachVolume(ctx context.Context, hyperMetroPairID, hostname, i
 	return nil
 }
 
+// AttachVolumeWithCHAP create mapping to host that authenticated by CHAP.
+// generate new CHAP credential (= rotate) if chap is nil, isMutual is used only for generating.
+// return CHAP credential that set to initiator in both devices.
+// CHAP credential is also returned with error if it may be already set to initiator in Local Device,
+// caller must keep it because host can not login to Local Device by previous credential.
+func (c *Client) AttachVolumeWithCHAP(ctx context.Context, hyperMetroPairID, hostname, iqn string, chap *CHAPParam, isMutual bool) (*CHAPParam, error) {
+	if chap != nil && chap.IsMutual() != isMutual {
+		return nil, fmt.Errorf("isMutual (%t) is not match with chap", isMutual)
+	}
+	if chap == nil {
+		newCHAP, err := NewCHAPParam(hostname, isMutual)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate CHAP credential: %w", err)
+		}
+		chap = newCHAP
+	}
+	if err := chap.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid CHAP parameter: %w", err)
+	}
+
+	volume, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume information: %w", err)
+	}
+
+	err = c.LocalDevice.AttachVolumeWithCHAP(ctx, c.portGroupName(hostname), hostname, iqn, volume.LOCALOBJID, chap)
+	if err != nil {
+		return chap, fmt.Errorf("failed to attach volume in Local Device: %w", err)
+	}
+	err = c.RemoteDevice.AttachVolumeWithCHAP(ctx, c.portGroupName(hostname), hostname, iqn, volume.REMOTEOBJID, chap)
+	if err != nil {
+		return chap, fmt.Errorf("failed to attach volume in Remote Device: %w", err)
+	}
+
+	return chap, nil
+}
+
+// AttachVolumeFC create mapping to host that connected by Fibre Channel.
+// return connection information of local device and remote device.
+func (c *Client) AttachVolumeFC(ctx context.Context, hyperMetroPairID, hostname string, wwpns []string) ([]FCConnectionInfo, error) {
+	volume, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume information: %w", err)
+	}
+
+	localInfo, err := c.LocalDevice.AttachVolumeFC(ctx, c.portGroupName(hostname), hostname, wwpns, volume.LOCALOBJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach volume in Local Device: %w", err)
+	}
+	remoteInfo, err := c.RemoteDevice.AttachVolumeFC(ctx, c.portGroupName(hostname), hostname, wwpns, volume.REMOTEOBJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach volume in Remote Device: %w", err)
+	}
+
+	return []FCConnectionInfo{*localInfo, *remoteInfo}, nil
+}
+
+// FCConnectionInfo is information for connecting to LUN by Fibre Channel
+type FCConnectionInfo struct {
+	TargetWWPNs        []string
+	HostLUNID          int
+	InitiatorTargetMap map[string][]string // key: host WWPN, value: target WWPNs
+}
+
+// AttachVolumeFC create mapping to host (register host WWPNs) in device.
+func (d *Device) AttachVolumeFC(ctx context.Context, portgroupName, hostname string, wwpns []string, lunID int) (*FCConnectionInfo, error) {
+	if len(wwpns) == 0 {
+		return nil, errors.New("WWPNs is required")
+	}
+	var normalized []string
+	for _, wwpn := range wwpns {
+		w, err := NormalizeWWPN(wwpn)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, w)
+	}
+
+	portgroup, host, err := d.attachVolume(ctx, portgroupName, hostname, lunID, func(host *Host) error {
+		for _, wwpn := range normalized {
+			_, err := d.GetFCInitiatorForce(ctx, wwpn)
+			if err != nil {
+				return fmt.Errorf("failed to get FC initiator: %w", err)
+			}
+
+			param := UpdateFCInitiatorParam{
+				ID:         wwpn,
+				TYPE:       strconv.Itoa(TypeFCInitiator),
+				PARENTID:   strconv.Itoa(host.ID),
+				PARENTTYPE: strconv.Itoa(TypeHost),
+			}
+			_, err = d.UpdateFCInitiator(ctx, wwpn, param) // set PARENTID (= host.ID)
+			if err != nil {
+				return fmt.Errorf("failed to set parameter for FC initiator: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	targetWWPNs, err := d.GetTargetWWPNs(ctx, portgroup.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target WWPNs: %w", err)
+	}
+	hostLUNID, err := d.GetHostLUNID(ctx, lunID, host.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get host LUN ID: %w", err)
+	}
+
+	initiatorTargetMap := map[string][]string{}
+	for _, wwpn := range normalized {
+		initiatorTargetMap[wwpn] = targetWWPNs
+	}
+
+	return &FCConnectionInfo{
+		TargetWWPNs:        targetWWPNs,
+		HostLUNID:          hostLUNID,
+		InitiatorTargetMap: initiatorTargetMap,
+	}, nil
+}
+
+// AttachVolumeNVMe create mapping to host that connected by NVMe over Fabrics.
+// return connection information of local device and remote device.
+func (c *Client) AttachVolumeNVMe(ctx context.Context, hyperMetroPairID, hostname, hostNQN string, transport NVMeTransport) ([]NVMeConnectionInfo, error) {
+	volume, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume information: %w", err)
+	}
+
+	localInfo, err := c.LocalDevice.AttachVolumeNVMe(ctx, c.portGroupName(hostname), hostname, hostNQN, transport, volume.LOCALOBJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach volume in Local Device: %w", err)
+	}
+	remoteInfo, err := c.RemoteDevice.AttachVolumeNVMe(ctx, c.portGroupName(hostname), hostname, hostNQN, transport, volume.REMOTEOBJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach volume in Remote Device: %w", err)
+	}
+
+	return []NVMeConnectionInfo{*localInfo, *remoteInfo}, nil
+}
+
+// NVMeConnectionInfo is information for connecting to LUN by NVMe over Fabrics
+type NVMeConnectionInfo struct {
+	SubsystemNQN       string
+	Transport          NVMeTransport
+	TransportAddresses []string // host:port
+	NamespaceID        int      // = host LUN ID
+}
+
+// AttachVolumeNVMe create mapping to host (register host NQN) in device.
+func (d *Device) AttachVolumeNVMe(ctx context.Context, portgroupName, hostname, hostNQN string, transport NVMeTransport, lunID int) (*NVMeConnectionInfo, error) {
+	if err := validateNQN(hostNQN); err != nil {
+		return nil, err
+	}
+
+	portgroup, host, err := d.attachVolume(ctx, portgroupName, hostname, lunID, func(host *Host) error {
+		initiator, err := d.GetNVMeInitiatorForce(ctx, transport, hostNQN)
+		if err != nil {
+			return fmt.Errorf("failed to get NVMe initiator: %w", err)
+		}
+		if initiator.PARENTID == strconv.Itoa(host.ID) {
+			return nil
+		}
+
+		if err := d.AssociateNVMeInitiator(ctx, transport, host.ID, hostNQN); err != nil {
+			return fmt.Errorf("failed to associate NVMe initiator to host: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subsystemNQN, err := d.GetNVMeSubsystemNQN(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subsystem NQN: %w", err)
+	}
+	addresses, err := d.GetNVMeTransportAddresses(ctx, portgroup.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transport addresses: %w", err)
+	}
+	hostLUNID, err := d.GetHostLUNID(ctx, lunID, host.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get host LUN ID: %w", err)
+	}
+
+	return &NVMeConnectionInfo{
+		SubsystemNQN:       subsystemNQN,
+		Transport:          transport,
+		TransportAddresses: addresses,
+		NamespaceID:        hostLUNID,
+	}, nil
+}
+
 // AttachVolume create mapping to host in device
 func (d *Device) AttachVolume(ctx context.Context, portgroupName, hostname, iqn string, lunID int) error {
+	return d.AttachVolumeWithCHAP(ctx, portgroupName, hostname, iqn, lunID, nil)
+}
+
+// AttachVolumeWithCHAP create mapping to host in device, and set CHAP credential to initiator.
+// CHAP is disabled if chap is nil.
+func (d *Device) AttachVolumeWithCHAP(ctx context.Context, portgroupName, hostname, iqn string, lunID int, chap *CHAPParam) error {
 	// wrapper function for client.AttachVolume
+	_, _, err := d.attachVolume(ctx, portgroupName, hostname, lunID, d.setISCSIInitiator(ctx, iqn, chap))
+
+	return err
+}
+
+// setISCSIInitiator return function that associate iSCSI initiator to host.
+func (d *Device) setISCSIInitiator(ctx context.Context, iqn string, chap *CHAPParam) func(host *Host) error {
+	return func(host *Host) error {
+		_, err := d.GetInitiatorForce(ctx, iqn)
+		if err != nil {
+			return fmt.Errorf("failed to get initiator: %w", err)
+		}
+		initiatorUpdateParam := UpdateInitiatorParam{
+			ID:         iqn,
+			TYPE:       strconv.Itoa(TypeInitiator),
+			PARENTID:   strconv.Itoa(host.ID),
+			PARENTTYPE: strconv.Itoa(TypeHost),
+		}
+		initiatorUpdateParam.SetCHAP(chap)
+		_, err = d.UpdateInitiator(ctx, iqn, initiatorUpdateParam) // set PARENTID (= host.ID)
+		if err != nil {
+			return fmt.Errorf("failed to set parameter for initiator: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// keepISCSIInitiator return function that associate iSCSI initiator to host without changing CHAP setting.
+func (d *Device) keepISCSIInitiator(ctx context.Context, iqn string) func(host *Host) error {
+	return func(host *Host) error {
+		initiator, err := d.GetInitiatorForce(ctx, iqn)
+		if err != nil {
+			return fmt.Errorf("failed to get initiator: %w", err)
+		}
+		if initiator.PARENTID == strconv.Itoa(host.ID) {
+			return nil
+		}
+		if initiator.USECHAP == "true" {
+			// CHAP credential can not be read from device
+			return fmt.Errorf("initiator that enabled CHAP is not in host (PARENTID: %s)", initiator.PARENTID)
+		}
+
+		return d.setISCSIInitiator(ctx, iqn, nil)(host)
+	}
+}
+
+// attachVolume create mapping lunID to hostname.
+// setInitiators is called for associating initiators (ex: iSCSI, FC) to host.
+func (d *Device) attachVolume(ctx context.Context, portgroupName, hostname string, lunID int, setInitiators func(host *Host) error) (*PortGroup, *Host, error) {
+	associate := func(lungroup *LunGroup) error {
+		if err := d.AssociateLun(ctx, lungroup.ID, lunID); err != nil {
+			return fmt.Errorf("failed to associate lun to lungroup: %w", err)
+		}
+		return nil
+	}
+
+	return d.attachObject(ctx, portgroupName, hostname, associate, setInitiators)
+}
+
+// attachObject create mapping object (ex: LUN, snapshot) to hostname.
+// associate is called for associating object to lun group of host.
+func (d *Device) attachObject(ctx context.Context, portgroupName, hostname string, associate func(lungroup *LunGroup) error, setInitiators func(host *Host) error) (*PortGroup, *Host, error) {
 	portgroups, err := d.GetPortGroups(ctx, NewSearchQueryName(portgroupName))
 	if err != nil {
-		return fmt.Errorf("failed to get portgroup: %w", err)
+		return nil, nil, fmt.Errorf("failed to get portgroup: %w", err)
 	}
 	if len(portgroups) != 1 {
-		return errors.New("found multiple portgroup in same PortGroup name")
+		return nil, nil, errors.New("found multiple portgroup in same PortGroup name")
 	}
 	portgroup := portgroups[0]
 
 	hostgroup, host, err := d.GetHostGroupForce(ctx, hostname)
 	if err != nil {
-		return fmt.Errorf("failed to get hostgroup: %w", err)
-	}
-	_, err = d.GetInitiatorForce(ctx, iqn)
-	if err != nil {
-		return fmt.Errorf("failed to get initiator: %w", err)
-	}
-	initiatorUpdateParam := UpdateInitiatorParam{
-		ID:         iqn,
-		TYPE:       strconv.Itoa(TypeInitiator),
-		USECHAP:    "false",
-		PARENTID:   strconv.Itoa(host.ID),
-		PARENTTYPE: strconv.Itoa(TypeHost),
+		return nil, nil, fmt.Errorf("failed to get hostgroup: %w", err)
 	}
-	_, err = d.UpdateInitiator(ctx, iqn, initiatorUpdateParam) // set PARENTID (= host.ID)
-	if err != nil {
-		return fmt.Errorf("failed to set parameter for initiator: %w", err)
+
+	if err := setInitiators(host); err != nil {
+		return nil, nil, err
 	}
 
 	lungroup, err := d.GetLunGroupForce(ctx, hostname)
 	if err != nil {
-		return fmt.Errorf("failed to get lungroup: %w", err)
+		return nil, nil, fmt.Errorf("failed to get lungroup: %w", err)
 	}
 
-	err = d.AssociateLun(ctx, lungroup.ID, lunID)
-	if err != nil {
-		return fmt.Errorf("failed to associate lun to lungroup: %w", err)
+	if err := associate(lungroup); err != nil {
+		return nil, nil, err
 	}
 
 	mappingview, err := d.GetMappingViewForce(ctx, hostname)
 	if err != nil {
-		return fmt.Errorf("failed to get mappingview: %w", err)
+		return nil, nil, fmt.Errorf("failed to get mappingview: %w", err)
+	}
+
+	// a mapping view has only one port group, so keep port group that host is already mapped
+	mapped, err := d.GetPortGroupsAssociate(ctx, mappingview.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get portgroup of mappingview: %w", err)
+	}
+	if len(mapped) != 0 && mapped[0].ID != portgroup.ID {
+		d.Logger.Printf("host %s is already mapped to portgroup %s, use it instead of %s\n", hostname, mapped[0].NAME, portgroup.NAME)
+		portgroup = mapped[0]
 	}
 
 	err = d.DoMapping(ctx, mappingview, hostgroup, lungroup, portgroup.ID)
 	if err != nil {
-		return fmt.Errorf("failed to associate object to mappingview: %w", err)
+		return nil, nil, fmt.Errorf("failed to associate object to mappingview: %w", err)
 	}
 
-	return nil
+	return &portgroup, host, nil
 }
 
 // DetachVolume delete mapping from host
@@ -378,3 +847,57 @@ func (d *Device) DetachVolume(ctx context.Context, lunID int) error {
 
 	return nil
 }
+
+// AttachSnapshot create mapping activated snapshot to hostname by iSCSI.
+// host can read point-in-time image of snapshot without clone.
+// CHAP setting of initiator is not changed, so initiator that enabled CHAP must be already in host.
+func (d *Device) AttachSnapshot(ctx context.Context, portgroupName, hostname, iqn string, snapshotID int) error {
+	return d.attachSnapshot(ctx, portgroupName, hostname, snapshotID, d.keepISCSIInitiator(ctx, iqn))
+}
+
+// AttachSnapshotWithCHAP create mapping activated snapshot to hostname, and set CHAP credential to initiator.
+// CHAP is disabled if chap is nil.
+func (d *Device) AttachSnapshotWithCHAP(ctx context.Context, portgroupName, hostname, iqn string, snapshotID int, chap *CHAPParam) error {
+	if chap != nil {
+		if err := chap.Validate(); err != nil {
+			return fmt.Errorf("invalid CHAP parameter: %w", err)
+		}
+	}
+
+	return d.attachSnapshot(ctx, portgroupName, hostname, snapshotID, d.setISCSIInitiator(ctx, iqn, chap))
+}
+
+func (d *Device) attachSnapshot(ctx context.Context, portgroupName, hostname string, snapshotID int, setInitiators func(host *Host) error) error {
+	snapshot, err := d.GetSnapshot(ctx, snapshotID)
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	if snapshot.RUNNINGSTATUS != strconv.Itoa(StatusSnapshotActive) {
+		return fmt.Errorf("snapshot is not activated (RUNNINGSTATUS: %s)", snapshot.RUNNINGSTATUS)
+	}
+
+	associate := func(lungroup *LunGroup) error {
+		if err := d.AssociateSnapshot(ctx, lungroup.ID, snapshot.ID); err != nil {
+			return fmt.Errorf("failed to associate snapshot to lungroup: %w", err)
+		}
+		return nil
+	}
+
+	_, _, err = d.attachObject(ctx, portgroupName, hostname, associate, setInitiators)
+	return err
+}
+
+// DetachSnapshot delete mapping from snapshot.
+func (d *Device) DetachSnapshot(ctx context.Context, snapshotID int) error {
+	lungroup, err := d.GetLunGroupBySnapshotID(ctx, snapshotID)
+	if err != nil {
+		return fmt.Errorf("failed to get lungroup: %w", err)
+	}
+
+	err = d.DisAssociateSnapshot(ctx, lungroup.ID, snapshotID)
+	if err != nil {
+		return fmt.Errorf("failed to disassociate snapshot: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// VolumeGroupSnapshot is crash-consistent snapshot of multiple volumes (= HyperMetroPair).
+// a snapshot consistency group is created in device that has primary LUNs.
+type VolumeGroupSnapshot struct {
+	ID                       uuid.UUID
+	IsLocal                  bool // true if snapshot consistency group is in local device
+	ProtectionGroupID        int
+	SnapshotConsistencyGroup SnapshotConsistencyGroup
+}
+
+// groupPrimaryDevice return device and LUN IDs that are primary in all HyperMetroPairs.
+// all primary LUNs must be in same device for atomic snapshot.
+func (c *Client) groupPrimaryDevice(ctx context.Context, hyperMetroPairIDs []string) (*Device, []int, bool, error) {
+	if len(hyperMetroPairIDs) == 0 {
+		return nil, nil, false, errors.New("HyperMetroPair IDs is empty")
+	}
+
+	var device *Device
+	var lunIDs []int
+	var isLocal bool
+	for _, hyperMetroPairID := range hyperMetroPairIDs {
+		hmp, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to get HyperMetro Pair: %w", err)
+		}
+
+		d, lunID, l := c.primaryDevice(hmp)
+		if device != nil && device != d {
+			return nil, nil, false, errors.New("primary LUNs of HyperMetroPairs are not in same device")
+		}
+		device = d
+		isLocal = l
+		lunIDs = append(lunIDs, lunID)
+	}
+
+	return device, lunIDs, isLocal, nil
+}
+
+func (c *Client) volumeGroupSnapshotDevice(vgs *VolumeGroupSnapshot) *Device {
+	if vgs.IsLocal {
+		return c.LocalDevice
+	}
+	return c.RemoteDevice
+}
+
+// CreateVolumeGroupSnapshot create activated snapshots of volumes at the same time in primary side.
+// protection group that has same volumes is reused, because a LUN can belong to only one protection group.
+func (c *Client) CreateVolumeGroupSnapshot(ctx context.Context, hyperMetroPairIDs []string, u uuid.UUID) (*VolumeGroupSnapshot, error) {
+	d, lunIDs, isLocal, err := c.groupPrimaryDevice(ctx, hyperMetroPairIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get primary device: %w", err)
+	}
+
+	protectionGroup, created, err := d.getOrCreateProtectionGroup(ctx, u, lunIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get protection group: %w", err)
+	}
+	defer func() {
+		if err != nil && created {
+			if err := d.DeleteProtectionGroup(ctx, protectionGroup.ID); err != nil {
+				d.Logger.Printf("failed to delete protection group: %v\n", err)
+			}
+		}
+	}()
+
+	scg, err := d.CreateSnapshotConsistencyGroupWithWait(ctx, protectionGroup.ID, u, u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot consistency group: %w", err)
+	}
+	scgID := scg.ID
+	activated := false
+	defer func() {
+		if err != nil {
+			if activated {
+				if err := d.StopSnapshotConsistencyGroup(ctx, scgID); err != nil {
+					d.Logger.Printf("failed to stop snapshot consistency group: %v\n", err)
+				}
+			}
+			if err := d.DeleteSnapshotConsistencyGroup(ctx, scgID); err != nil {
+				d.Logger.Printf("failed to delete snapshot consistency group: %v\n", err)
+			}
+		}
+	}()
+
+	if err = d.ActivateSnapshotConsistencyGroup(ctx, scgID); err != nil {
+		return nil, fmt.Errorf("failed to activate snapshot consistency group: %w", err)
+	}
+	activated = true
+	scg, err = d.GetSnapshotConsistencyGroup(ctx, scgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot consistency group: %w", err)
+	}
+
+	return &VolumeGroupSnapshot{
+		ID:                       u,
+		IsLocal:                  isLocal,
+		ProtectionGroupID:        protectionGroup.ID,
+		SnapshotConsistencyGroup: *scg,
+	}, nil
+}
+
+// getOrCreateProtectionGroup return protection group that has just LUNs, create it if LUNs are not in any protection group.
+// a LUN can belong to only one protection group, so snapshots of same volumes share a protection group.
+func (d *Device) getOrCreateProtectionGroup(ctx context.Context, u uuid.UUID, lunIDs []int) (*ProtectionGroup, bool, error) {
+	var protectionGroup *ProtectionGroup
+	ungrouped := 0
+	for _, lunID := range lunIDs {
+		pg, err := d.GetProtectionGroupByLUNID(ctx, lunID)
+		if err == ErrProtectionGroupNotFound {
+			ungrouped++
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get protection group by LUN (ID: %d): %w", lunID, err)
+		}
+		if protectionGroup != nil && protectionGroup.ID != pg.ID {
+			return nil, false, fmt.Errorf("LUNs are in different protection groups (ID: %d, %d)", protectionGroup.ID, pg.ID)
+		}
+		protectionGroup = pg
+	}
+
+	if protectionGroup == nil {
+		pg, err := d.CreateProtectionGroup(ctx, u, lunIDs)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to create protection group: %w", err)
+		}
+		return pg, true, nil
+	}
+	if ungrouped > 0 {
+		return nil, false, fmt.Errorf("some LUNs are already in protection group (ID: %d)", protectionGroup.ID)
+	}
+
+	luns, err := d.GetProtectionGroupLUNs(ctx, protectionGroup.ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get LUNs in protection group: %w", err)
+	}
+	if !isSameLUNIDs(luns, lunIDs) {
+		return nil, false, fmt.Errorf("protection group (ID: %d) has other LUNs", protectionGroup.ID)
+	}
+
+	return protectionGroup, false, nil
+}
+
+// GetVolumeGroupSnapshot get snapshot of volumes by uuid.
+func (c *Client) GetVolumeGroupSnapshot(ctx context.Context, u uuid.UUID) (*VolumeGroupSnapshot, error) {
+	for _, side := range []struct {
+		device  *Device
+		isLocal bool
+	}{
+		{device: c.LocalDevice, isLocal: true},
+		{device: c.RemoteDevice, isLocal: false},
+	} {
+		scgs, err := side.device.GetSnapshotConsistencyGroups(ctx, NewSearchQueryName(EncodeSnapshotName(u)))
+		if err != nil {
+			if err == ErrSnapshotConsistencyGroupNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get snapshot consistency groups: %w", err)
+		}
+		if len(scgs) != 1 {
+			return nil, fmt.Errorf("found multiple snapshot consistency groups in same name (name: %s)", EncodeSnapshotName(u))
+		}
+
+		return &VolumeGroupSnapshot{
+			ID:                       u,
+			IsLocal:                  side.isLocal,
+			ProtectionGroupID:        scgs[0].PARENTID,
+			SnapshotConsistencyGroup: scgs[0],
+		}, nil
+	}
+
+	return nil, ErrSnapshotConsistencyGroupNotFound
+}
+
+// ListVolumeGroupSnapshotMembers list snapshots in snapshot of volumes.
+func (c *Client) ListVolumeGroupSnapshotMembers(ctx context.Context, u uuid.UUID) ([]Snapshot, error) {
+	vgs, err := c.GetVolumeGroupSnapshot(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume group snapshot: %w", err)
+	}
+
+	return c.volumeGroupSnapshotDevice(vgs).GetSnapshotConsistencyGroupMembers(ctx, vgs.SnapshotConsistencyGroup.ID)
+}
+
+// DeleteVolumeGroupSnapshot delete snapshot of volumes, and protection group if no other snapshot use it.
+func (c *Client) DeleteVolumeGroupSnapshot(ctx context.Context, u uuid.UUID) error {
+	vgs, err := c.GetVolumeGroupSnapshot(ctx, u)
+	if err != nil {
+		return fmt.Errorf("failed to get volume group snapshot: %w", err)
+	}
+	d := c.volumeGroupSnapshotDevice(vgs)
+
+	if vgs.SnapshotConsistencyGroup.RUNNINGSTATUS == strconv.Itoa(StatusSnapshotActive) {
+		if err := d.StopSnapshotConsistencyGroup(ctx, vgs.SnapshotConsistencyGroup.ID); err != nil {
+			return fmt.Errorf("failed to stop snapshot consistency group: %w", err)
+		}
+	}
+	if err := d.DeleteSnapshotConsistencyGroup(ctx, vgs.SnapshotConsistencyGroup.ID); err != nil {
+		return fmt.Errorf("failed to delete snapshot consistency group: %w", err)
+	}
+
+	// protection group is shared by other snapshots of same volumes
+	query := &SearchQuery{Filter: ToFilter("PARENTID", strconv.Itoa(vgs.ProtectionGroupID))}
+	if _, err := d.GetSnapshotConsistencyGroups(ctx, query); err != ErrSnapshotConsistencyGroupNotFound {
+		if err != nil {
+			return fmt.Errorf("failed to get snapshot consistency groups in protection group: %w", err)
+		}
+		return nil
+	}
+	if err := d.DeleteProtectionGroup(ctx, vgs.ProtectionGroupID); err != nil {
+		return fmt.Errorf("failed to delete protection group: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreVolumesFromGroupSnapshot restore HyperMetroPairs from snapshot of volumes.
+// 1: suspend HyperMetroPairs, 2: rollback primary LUNs, 3: re-sync to secondary LUNs.
+// LUNs in snapshot must be primary in HyperMetroPairs.
+// HyperMetroPairs in consistency group are suspended and re-synchronized by group.
+func (c *Client) RestoreVolumesFromGroupSnapshot(ctx context.Context, hyperMetroPairIDs []string, u uuid.UUID, speed Speed) error {
+	vgs, err := c.GetVolumeGroupSnapshot(ctx, u)
+	if err != nil {
+		return fmt.Errorf("failed to get volume group snapshot: %w", err)
+	}
+	d, lunIDs, isLocal, err := c.groupPrimaryDevice(ctx, hyperMetroPairIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get primary device: %w", err)
+	}
+	if isLocal != vgs.IsLocal {
+		// re-sync will overwrite restored LUNs by secondary LUNs
+		return errors.New("LUNs in snapshot are not primary in HyperMetroPairs")
+	}
+
+	luns, err := d.GetProtectionGroupLUNs(ctx, vgs.ProtectionGroupID)
+	if err != nil {
+		return fmt.Errorf("failed to get LUNs in protection group: %w", err)
+	}
+	if !isSameLUNIDs(luns, lunIDs) {
+		return errors.New("LUNs in snapshot are not match LUNs in HyperMetroPairs")
+	}
+
+	if vgs.SnapshotConsistencyGroup.RUNNINGSTATUS != strconv.Itoa(StatusSnapshotActive) {
+		if err := d.ActivateSnapshotConsistencyGroup(ctx, vgs.SnapshotConsistencyGroup.ID); err != nil {
+			return fmt.Errorf("failed to activate snapshot consistency group: %w", err)
+		}
+	}
+
+	// 1: suspend HyperMetroPairs (or consistency groups that have HyperMetroPairs)
+	var hmps []*HyperMetroPair
+	suspendedCG := map[string]bool{}
+	for _, hyperMetroPairID := range hyperMetroPairIDs {
+		hmp, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+		if err != nil {
+			return fmt.Errorf("failed to get HyperMetro Pair: %w", err)
+		}
+		hmps = append(hmps, hmp)
+		if hmp.RUNNINGSTATUS == strconv.Itoa(StatusPause) || (hmp.ISINCG == "true" && suspendedCG[hmp.CGID]) {
+			continue
+		}
+		if err := c.suspendHyperMetroPairOrCG(ctx, hmp); err != nil {
+			return fmt.Errorf("failed to suspend HyperMetroPair: %w", err)
+		}
+		if hmp.ISINCG == "true" {
+			suspendedCG[hmp.CGID] = true
+		}
+	}
+
+	// 2: rollback primary LUNs
+	// NOTE: HyperMetroPairs are kept suspended if failed, primary LUNs may not be consistent.
+	err = d.RollbackSnapshotConsistencyGroupWithWait(ctx, vgs.SnapshotConsistencyGroup.ID, speed, 0)
+	if err != nil {
+		return fmt.Errorf("failed to rollback primary LUNs: %w", err)
+	}
+
+	// 3: re-sync to secondary LUNs
+	syncedCG := map[string]bool{}
+	for _, hmp := range hmps {
+		if hmp.ISINCG == "true" {
+			if syncedCG[hmp.CGID] {
+				continue
+			}
+			syncedCG[hmp.CGID] = true
+		}
+		if err := c.syncHyperMetroPairOrCG(ctx, hmp); err != nil {
+			return fmt.Errorf("failed to re-sync HyperMetro Pair: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func isSameLUNIDs(luns []LUN, lunIDs []int) bool {
+	if len(luns) != len(lunIDs) {
+		return false
+	}
+
+	ids := map[int]bool{}
+	for _, lun := range luns {
+		ids[lun.ID] = true
+	}
+	for _, lunID := range lunIDs {
+		if !ids[lunID] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,190 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestClient_CreateVolumeGroupSnapshot(t *testing.T) {
+	u := uuid.FromStringOrNil("3b0c6e52-4a43-4b4f-9d5d-7d3c1f0e8a11")
+
+	tests := []struct {
+		name                string
+		hasProtectionGroup  bool
+		wantProtectionGroup []string
+	}{
+		{
+			name:                "protection group is reused",
+			hasProtectionGroup:  true,
+			wantProtectionGroup: nil,
+		},
+		{
+			name:                "protection group is created",
+			hasProtectionGroup:  false,
+			wantProtectionGroup: []string{"POST /protectgroup", "POST /protectgroup/associate", "POST /protectgroup/associate"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			var got []string
+			for id, lunID := range map[string]int{"1": 11, "2": 12} {
+				body := fmt.Sprintf(`{"data": {"ID": "%s", "ISPRIMARY": "true", "LOCALOBJID": "%d", "REMOTEOBJID": "%d", "RUNNINGSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`, id, lunID, lunID+100)
+				mux.HandleFunc("/HyperMetroPair/"+id, func(w http.ResponseWriter, r *http.Request) {
+					testMethod(t, r, "GET")
+					fmt.Fprint(w, body)
+				})
+			}
+			mux.HandleFunc("/protectgroup/associate", func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+					if test.hasProtectionGroup {
+						fmt.Fprint(w, `{"data": [{"protectGroupId": "5", "protectGroupName": "pg", "lunNum": "2"}], "error": {"code": 0, "description": "0"}}`)
+						return
+					}
+					fmt.Fprint(w, `{"data": [], "error": {"code": 0, "description": "0"}}`)
+				case "POST":
+					got = append(got, "POST /protectgroup/associate")
+					fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+				default:
+					t.Errorf("Request method: %v, want GET or POST", r.Method)
+				}
+			})
+			mux.HandleFunc("/protectgroup", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "POST")
+				got = append(got, "POST /protectgroup")
+				fmt.Fprint(w, `{"data": {"protectGroupId": "5", "protectGroupName": "pg"}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/protectgroup/5", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "GET")
+				fmt.Fprint(w, `{"data": {"protectGroupId": "5", "protectGroupName": "pg", "lunNum": "2"}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/lun/associate", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "GET")
+				fmt.Fprint(w, `{"data": [{"ID": "11"}, {"ID": "12"}], "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/snapshot_consistency_group", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "POST")
+				testBody(t, r, map[string]interface{}{
+					"NAME":        EncodeSnapshotName(u),
+					"PARENTID":    "5",
+					"DESCRIPTION": u.String(),
+				})
+				fmt.Fprint(w, `{"data": {"ID": "3", "PARENTID": "5"}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/snapshot_consistency_group/3", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "GET")
+				fmt.Fprint(w, `{"data": {"ID": "3", "PARENTID": "5", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "43"}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/snapshot_consistency_group/activate", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "PUT")
+				testBody(t, r, map[string]interface{}{"ID": "3"})
+				fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			})
+
+			vgs, err := client.CreateVolumeGroupSnapshot(context.Background(), []string{"1", "2"}, u)
+			if err != nil {
+				t.Fatalf("CreateVolumeGroupSnapshot return err: %s", err)
+			}
+			if !vgs.IsLocal || vgs.ProtectionGroupID != 5 || vgs.SnapshotConsistencyGroup.ID != 3 {
+				t.Errorf("CreateVolumeGroupSnapshot return %+v", vgs)
+			}
+			if !reflect.DeepEqual(got, test.wantProtectionGroup) {
+				t.Errorf("CreateVolumeGroupSnapshot request %v, want %v", got, test.wantProtectionGroup)
+			}
+		})
+	}
+}
+
+func TestClient_CreateVolumeGroupSnapshot_OtherProtectionGroup(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/HyperMetroPair/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"ID": "1", "ISPRIMARY": "true", "LOCALOBJID": "11", "RUNNINGSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/protectgroup/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"protectGroupId": "5", "protectGroupName": "pg", "lunNum": "2"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/associate", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": [{"ID": "11"}, {"ID": "12"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/snapshot_consistency_group", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("snapshot consistency group must not be created")
+	})
+
+	u := uuid.FromStringOrNil("3b0c6e52-4a43-4b4f-9d5d-7d3c1f0e8a11")
+	if _, err := client.CreateVolumeGroupSnapshot(context.Background(), []string{"1"}, u); err == nil {
+		t.Errorf("CreateVolumeGroupSnapshot must return err if LUN is in protection group with other LUNs")
+	}
+}
+
+func TestClient_RestoreVolumesFromGroupSnapshot_InCG(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	u := uuid.FromStringOrNil("3b0c6e52-4a43-4b4f-9d5d-7d3c1f0e8a11")
+
+	var got []string
+	record := func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		got = append(got, r.URL.Path)
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	}
+	mux.HandleFunc("/snapshot_consistency_group", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"data": [{"ID": "3", "NAME": "%s", "PARENTID": "5", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "43"}], "error": {"code": 0, "description": "0"}}`, EncodeSnapshotName(u))
+	})
+	for id, lunID := range map[string]int{"1": 11, "2": 12} {
+		body := fmt.Sprintf(`{"data": {"ID": "%s", "ISPRIMARY": "true", "ISINCG": "true", "CGID": "7", "LOCALOBJID": "%d", "RUNNINGSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`, id, lunID)
+		mux.HandleFunc("/HyperMetroPair/"+id, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			fmt.Fprint(w, body)
+		})
+	}
+	mux.HandleFunc("/lun/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "11"}, {"ID": "12"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/snapshot/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"HEALTHSTATUS": "1", "ID": "21", "PARENTID": "11", "RUNNINGSTATUS": "43", "TYPE": 27}, {"HEALTHSTATUS": "1", "ID": "22", "PARENTID": "12", "RUNNINGSTATUS": "43", "TYPE": 27}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/HyperMetroPair/disable_hcpair", record)
+	mux.HandleFunc("/HyperMetroPair/synchronize_hcpair", record)
+	mux.HandleFunc("/HyperMetro_ConsistentGroup/stop", record)
+	mux.HandleFunc("/HyperMetro_ConsistentGroup/sync", record)
+	mux.HandleFunc("/snapshot_consistency_group/rollback", record)
+	rollingBack := 1
+	mux.HandleFunc("/snapshot_consistency_group/3", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		status := "43"
+		if rollingBack > 0 {
+			rollingBack--
+			status = "44"
+		}
+		fmt.Fprintf(w, `{"data": {"ID": "3", "PARENTID": "5", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "%s"}, "error": {"code": 0, "description": "0"}}`, status)
+	})
+
+	if err := client.RestoreVolumesFromGroupSnapshot(context.Background(), []string{"1", "2"}, u, SpeedHighest); err != nil {
+		t.Fatalf("RestoreVolumesFromGroupSnapshot return err: %s", err)
+	}
+
+	want := []string{
+		"/HyperMetro_ConsistentGroup/stop",
+		"/snapshot_consistency_group/rollback",
+		"/HyperMetro_ConsistentGroup/sync",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RestoreVolumesFromGroupSnapshot request %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,185 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// PrefixMigratedVolumeDescription is prefix of source LUN Description after migrated to other array
+var PrefixMigratedVolumeDescription = "migrated-"
+
+// MigrateVolumeOption is option of MigrateVolumeToArray
+type MigrateVolumeOption struct {
+	CopyOptions *LunCopyOptions
+	// DeleteSource delete source volume after migration.
+	// if false, source LUNs are kept with PrefixMigratedVolumeDescription (volume is not found by uuid).
+	DeleteSource bool
+}
+
+// parseVolumeDescription get uuid of volume from LUN Description
+func parseVolumeDescription(description string) (uuid.UUID, error) {
+	if !strings.HasPrefix(description, PrefixVolumeDescription) {
+		return uuid.Nil, fmt.Errorf("LUN is not volume (DESCRIPTION: %s)", description)
+	}
+
+	return uuid.FromString(strings.TrimPrefix(description, PrefixVolumeDescription))
+}
+
+// MigrateVolumeToArray migrate volume (= HyperMetroPair) to other HyperMetro arrays (dst).
+// 1: copy snapshot of primary LUN to dst local device by remote LUN Copy,
+// 2: create HyperMetroPair in dst with first sync, 3: swap volume record (source LUNs are renamed or deleted).
+// source volume must be detached (ErrVolumeIsAttached), so snapshot has all data of volume.
+// capacity of new volume is rounded up to GB.
+// dst local device must be registered as remote array in primary device of source volume.
+// created objects in dst are deleted if failed, except ErrMigratedSourceNotDeleted
+// (new HyperMetroPair is returned with error, and source volume is kept with PrefixMigratedVolumeDescription).
+func (c *Client) MigrateVolumeToArray(ctx context.Context, hyperMetroPairID string, dst *Client, storagePoolName, hyperMetroDomainID string, opt *MigrateVolumeOption) (*HyperMetroPair, error) {
+	if opt == nil {
+		opt = &MigrateVolumeOption{}
+	}
+
+	hmp, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HyperMetroPair: %w", err)
+	}
+	if err := c.requireVolumeDetached(ctx, hmp); err != nil {
+		return nil, err
+	}
+	source, sourceLUNID, _ := c.primaryDevice(hmp)
+
+	sourceLUN, err := source.GetLUN(ctx, sourceLUNID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source LUN: %w", err)
+	}
+	u, err := parseVolumeDescription(sourceLUN.DESCRIPTION)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse volume description: %w", err)
+	}
+	// target LUN of LUN Copy must not be smaller than source
+	capacityGB := (sourceLUN.CAPACITY + CapacityUnit - 1) / CapacityUnit
+
+	remoteArray, err := source.DiscoverRemoteArray(ctx, dst.LocalDevice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover remote array of destination: %w", err)
+	}
+
+	// 1: copy snapshot of primary LUN to dst local device
+	snapshot, err := source.CreateSnapshotWithWait(ctx, sourceLUN.ID, uuid.NewV4(), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer func() {
+		if err := source.StopSnapshot(ctx, snapshot.ID); err != nil {
+			source.Logger.Printf("failed to stop snapshot: %v\n", err)
+		}
+		if err := source.DeleteSnapshot(ctx, snapshot.ID); err != nil {
+			source.Logger.Printf("failed to delete snapshot: %v\n", err)
+		}
+	}()
+	if err = source.ActivateSnapshot(ctx, snapshot.ID); err != nil {
+		return nil, fmt.Errorf("failed to activate snapshot: %w", err)
+	}
+
+	localLUN, err := dst.LocalDevice.CreateLUNWithWait(ctx, u, capacityGB, storagePoolName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lun in destination local device: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if err := dst.LocalDevice.DeleteLUN(ctx, localLUN.ID); err != nil {
+				dst.LocalDevice.Logger.Printf("failed to delete LUN: %v", err)
+			}
+		}
+	}()
+
+	luncopy, err := source.CreateRemoteLUNCopy(ctx, snapshot.ID, remoteArray, []LUN{*localLUN}, opt.CopyOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote luncopy object: %w", err)
+	}
+	defer func() {
+		if err := source.DeleteLUNCopy(ctx, luncopy.ID); err != nil {
+			source.Logger.Printf("failed to delete lun copy: %v\n", err)
+		}
+	}()
+	if err = source.StartLUNCopyWithWait(ctx, luncopy.ID, 0); err != nil {
+		return nil, fmt.Errorf("failed to copy lun to destination: %w", err)
+	}
+
+	// 2: create HyperMetroPair in dst with first sync
+	remoteLUN, err := dst.RemoteDevice.CreateLUNWithWait(ctx, u, capacityGB, storagePoolName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lun in destination remote device: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if err := dst.RemoteDevice.DeleteLUN(ctx, remoteLUN.ID); err != nil {
+				dst.RemoteDevice.Logger.Printf("failed to delete LUN: %v", err)
+			}
+		}
+	}()
+
+	hmpOpt := NewHyperMetroPairOption()
+	hmpOpt.IsFirstSync = true // seed data from copied LUN
+	param := newHyperMetroPairParam(hyperMetroDomainID, localLUN.ID, remoteLUN.ID, hmpOpt)
+	newHMP, err := dst.LocalDevice.createHyperMetroPair(ctx, param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HyperMetroPair in destination: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if err := dst.SuspendHyperMetroPair(ctx, newHMP.ID); err != nil {
+				dst.LocalDevice.Logger.Printf("failed to suspend HyperMetroPair: %v", err)
+			}
+			if err := dst.DeleteHyperMetroPair(ctx, newHMP.ID); err != nil {
+				dst.LocalDevice.Logger.Printf("failed to delete HyperMetroPair: %v", err)
+			}
+		}
+	}()
+
+	// 3: swap volume record
+	// source LUNs are marked as migrated before delete, volume is not found in both arrays.
+	migratedParam := UpdateLUNParam{DESCRIPTION: PrefixMigratedVolumeDescription + u.String()}
+	if _, err = c.LocalDevice.UpdateLUN(ctx, hmp.LOCALOBJID, migratedParam); err != nil {
+		return nil, fmt.Errorf("failed to update description of source local LUN: %w", err)
+	}
+	if _, err = c.RemoteDevice.UpdateLUN(ctx, hmp.REMOTEOBJID, migratedParam); err != nil {
+		restoreParam := UpdateLUNParam{DESCRIPTION: PrefixVolumeDescription + u.String()}
+		if _, err := c.LocalDevice.UpdateLUN(ctx, hmp.LOCALOBJID, restoreParam); err != nil {
+			c.LocalDevice.Logger.Printf("failed to restore description of source local LUN: %v", err)
+		}
+		return nil, fmt.Errorf("failed to update description of source remote LUN: %w", err)
+	}
+
+	if opt.DeleteSource {
+		// volume is already swapped, new HyperMetroPair must be kept
+		if err := c.DeleteVolume(ctx, hmp.ID); err != nil {
+			return newHMP, fmt.Errorf("%w: %v", ErrMigratedSourceNotDeleted, err)
+		}
+	}
+
+	return newHMP, nil
+}
+
+// requireVolumeDetached return ErrVolumeIsAttached if LUNs of HyperMetroPair are mapped to host
+func (c *Client) requireVolumeDetached(ctx context.Context, hmp *HyperMetroPair) error {
+	for _, side := range []struct {
+		device *Device
+		lunID  int
+	}{
+		{device: c.LocalDevice, lunID: hmp.LOCALOBJID},
+		{device: c.RemoteDevice, lunID: hmp.REMOTEOBJID},
+	} {
+		lun, err := side.device.GetLUN(ctx, side.lunID)
+		if err != nil {
+			return fmt.Errorf("failed to get LUN: %w", err)
+		}
+		if lun.ISADD2LUNGROUP {
+			return fmt.Errorf("%w: LUN (ID: %d) is added to LUN group", ErrVolumeIsAttached, lun.ID)
+		}
+	}
+
+	return nil
+}
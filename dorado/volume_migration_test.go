@@ -0,0 +1,248 @@
+package dorado
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestClient_MigrateVolumeToArray_Attached(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/HyperMetroPair/3400a30d844d0007", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "3400a30d844d0007", "ISPRIMARY": "true", "LOCALOBJID": "148", "REMOTEOBJID": "151"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/148", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "148", "ISADD2LUNGROUP": "false", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun/151", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "151", "ISADD2LUNGROUP": "true", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	_, err := client.MigrateVolumeToArray(context.Background(), "3400a30d844d0007", client, "pool", "domain", nil)
+	if !errors.Is(err, ErrVolumeIsAttached) {
+		t.Errorf("MigrateVolumeToArray return err %v, want %v", err, ErrVolumeIsAttached)
+	}
+}
+
+// setupMigrateVolumeToArray register handlers of volume (HyperMetroPair 3400a30d844d0007, LUN 148 and 151) in src,
+// and destination array (SN: 2102351234, new LUN 30 and 31) in dst.
+// return function that get non-GET requests in order ("src" or "dst" prefixed).
+func setupMigrateVolumeToArray(t *testing.T, src, dst *http.ServeMux, u uuid.UUID, failHyperMetroPair bool) func() []string {
+	var mu sync.Mutex
+	var calls []string
+	record := func(side string, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, side+": "+r.Method+" "+r.URL.Path)
+	}
+	ok := func(side string) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			record(side, r)
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+		}
+	}
+
+	// source array
+	src.HandleFunc("/HyperMetroPair/3400a30d844d0007", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "3400a30d844d0007", "ISPRIMARY": "true", "LOCALOBJID": "148", "REMOTEOBJID": "151"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	for _, id := range []string{"148", "151"} {
+		body := fmt.Sprintf(`{"data": {"ID": "%s", "CAPACITY": "2097153", "DESCRIPTION": "%s", "ISADD2LUNGROUP": "false", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`, id, PrefixVolumeDescription+u.String())
+		src.HandleFunc("/lun/"+id, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				fmt.Fprint(w, body)
+			case "PUT":
+				record("src", r)
+				testBody(t, r, map[string]interface{}{"DESCRIPTION": PrefixMigratedVolumeDescription + u.String()})
+				fmt.Fprint(w, body)
+			default:
+				t.Errorf("Request method: %v, want GET or PUT", r.Method)
+			}
+		})
+	}
+	src.HandleFunc("/remote_device", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("filter"); got != "SN::2102351234" {
+			t.Errorf("filter is %s, want SN::2102351234", got)
+		}
+		fmt.Fprint(w, `{"data": [{"ID": "0", "SN": "2102351234", "WWN": "2100e0cc7b000000", "TYPE": 224}], "error": {"code": 0, "description": "0"}}`)
+	})
+	src.HandleFunc("/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		record("src", r)
+		fmt.Fprint(w, `{"data": {"ID": "15", "PARENTID": "148", "PARENTTYPE": 11, "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+	})
+	src.HandleFunc("/snapshot/15", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"data": {"ID": "15", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "45", "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+		case "DELETE":
+			ok("src")(w, r)
+		default:
+			t.Errorf("Request method: %v, want GET or DELETE", r.Method)
+		}
+	})
+	src.HandleFunc("/snapshot/activate", ok("src"))
+	src.HandleFunc("/snapshot/stop", ok("src"))
+	src.HandleFunc("/luncopy", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		record("src", r)
+		testBody(t, r, map[string]interface{}{
+			"NAME":        "LUNCopy_15_0_30",
+			"SOURCELUN":   "INVALID;15;INVALID;INVALID;INVALID",
+			"TARGETLUN":   "0;30;2100e0cc7b000000;6a400e210055e22650d557a00000001e;INVALID",
+			"COPYSPEED":   float64(SpeedHighest),
+			"LUNCOPYTYPE": float64(LunCopyFull),
+		})
+		fmt.Fprint(w, `{"data": {"ID": "5", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "36"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	src.HandleFunc("/luncopy/start", ok("src"))
+	src.HandleFunc("/luncopy/5", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"data": {"ID": "5", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "40"}, "error": {"code": 0, "description": "0"}}`)
+		case "DELETE":
+			ok("src")(w, r)
+		default:
+			t.Errorf("Request method: %v, want GET or DELETE", r.Method)
+		}
+	})
+
+	// destination array
+	dst.HandleFunc("/system/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "2102351234", "TYPE": 201}, "error": {"code": 0, "description": "0"}}`)
+	})
+	dst.HandleFunc("/storagepool", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "0", "NAME": "pool"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	createdLUNs := 0
+	dst.HandleFunc("/lun", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		record("dst", r)
+		body := testDecodeBody(t, r)
+		if body != nil && body["CAPACITY"] != float64(2*CapacityUnit) {
+			t.Errorf("CAPACITY is %v, want %d (rounded up to GB)", body["CAPACITY"], 2*CapacityUnit)
+		}
+		mu.Lock()
+		id := 30 + createdLUNs
+		createdLUNs++
+		mu.Unlock()
+		fmt.Fprintf(w, `{"data": {"ID": "%d", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`, id)
+	})
+	for _, id := range []int{30, 31} {
+		body := fmt.Sprintf(`{"data": {"ID": "%d", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "27", "ISCLONE": "false", "WWN": "6a400e210055e22650d557a0000000%x", "TYPE": 11}, "error": {"code": 0, "description": "0"}}`, id, id)
+		dst.HandleFunc(fmt.Sprintf("/lun/%d", id), func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				fmt.Fprint(w, body)
+			case "DELETE":
+				ok("dst")(w, r)
+			default:
+				t.Errorf("Request method: %v, want GET or DELETE", r.Method)
+			}
+		})
+	}
+	dst.HandleFunc("/HyperMetroPair", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		record("dst", r)
+		body := testDecodeBody(t, r)
+		if body != nil && (body["LOCALOBJID"] != "30" || body["REMOTEOBJID"] != "31" || body["ISFIRSTSYNC"] != true) {
+			t.Errorf("Request body: %+v, want LOCALOBJID 30, REMOTEOBJID 31 and ISFIRSTSYNC", body)
+		}
+		if failHyperMetroPair {
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 1077949001, "description": "internal error"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"ID": "4400a30d844d0001", "LOCALOBJID": "30", "REMOTEOBJID": "31"}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+}
+
+func TestClient_MigrateVolumeToArray(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	dst, dstMux, _, dstTeardown := setup()
+	defer dstTeardown()
+
+	u := uuid.FromStringOrNil("3b0c6e52-4a43-4b4f-9d5d-7d3c1f0e8a11")
+	calls := setupMigrateVolumeToArray(t, mux, dstMux, u, false)
+
+	hmp, err := client.MigrateVolumeToArray(context.Background(), "3400a30d844d0007", dst, "pool", "domain", nil)
+	if err != nil {
+		t.Fatalf("MigrateVolumeToArray return err: %s", err)
+	}
+	if hmp.ID != "4400a30d844d0001" {
+		t.Errorf("MigrateVolumeToArray return HyperMetroPair %s, want %s", hmp.ID, "4400a30d844d0001")
+	}
+
+	want := []string{
+		"src: POST /snapshot",
+		"src: POST /snapshot/activate",
+		"dst: POST /lun",
+		"src: POST /luncopy",
+		"src: PUT /luncopy/start",
+		"dst: POST /lun",
+		"dst: POST /HyperMetroPair",
+		"src: PUT /lun/148",
+		"src: PUT /lun/151",
+		"src: DELETE /luncopy/5",
+		"src: PUT /snapshot/stop",
+		"src: DELETE /snapshot/15",
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("MigrateVolumeToArray request %v, want %v", got, want)
+	}
+}
+
+func TestClient_MigrateVolumeToArray_HyperMetroPairError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	dst, dstMux, _, dstTeardown := setup()
+	defer dstTeardown()
+
+	u := uuid.FromStringOrNil("3b0c6e52-4a43-4b4f-9d5d-7d3c1f0e8a11")
+	calls := setupMigrateVolumeToArray(t, mux, dstMux, u, true)
+
+	if _, err := client.MigrateVolumeToArray(context.Background(), "3400a30d844d0007", dst, "pool", "domain", nil); err == nil {
+		t.Fatalf("MigrateVolumeToArray must return err if failed to create HyperMetroPair")
+	}
+
+	// created objects are deleted in reverse order, source LUNs are not updated
+	want := []string{
+		"src: POST /snapshot",
+		"src: POST /snapshot/activate",
+		"dst: POST /lun",
+		"src: POST /luncopy",
+		"src: PUT /luncopy/start",
+		"dst: POST /lun",
+		"dst: POST /HyperMetroPair",
+		"dst: DELETE /lun/31",
+		"src: DELETE /luncopy/5",
+		"dst: DELETE /lun/30",
+		"src: PUT /snapshot/stop",
+		"src: DELETE /snapshot/15",
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("MigrateVolumeToArray request %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,304 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// PrefixVolumeSnapshotDescription is prefix of volume snapshot Description
+var PrefixVolumeSnapshotDescription = "volumesnapshot;"
+
+// VolumeSnapshot is snapshot of volume (= HyperMetroPair).
+// a snapshot is created in device that has primary LUN.
+type VolumeSnapshot struct {
+	ID               uuid.UUID
+	HyperMetroPairID string
+	IsLocal          bool // true if snapshot is in local device
+	Snapshot         Snapshot
+}
+
+// SnapshotDescription is metadata of volume snapshot that stored in Snapshot.DESCRIPTION.
+type SnapshotDescription struct {
+	HyperMetroPairID string // empty if snapshot of LUN
+	VolumeSnapshotID uuid.UUID
+	Values           map[string]string // optional values (ex: policy)
+}
+
+// Description key
+const (
+	descriptionKeyHyperMetroPairID = "pair"
+	descriptionKeyVolumeSnapshotID = "uuid"
+)
+
+// MaxSnapshotDescriptionLength is max length of Snapshot.DESCRIPTION
+const MaxSnapshotDescriptionLength = 255
+
+// Validate check that SnapshotDescription can be encoded and parsed back.
+// keys must not contain separators (";" and "="), values must not contain ";",
+// and encoded description must be shorter than MaxSnapshotDescriptionLength.
+func (sd *SnapshotDescription) Validate() error {
+	if strings.ContainsAny(sd.HyperMetroPairID, ";=") {
+		return fmt.Errorf("invalid HyperMetroPair ID in description: %s", sd.HyperMetroPairID)
+	}
+	for k, v := range sd.Values {
+		if k == "" || strings.ContainsAny(k, ";=") || k == descriptionKeyHyperMetroPairID || k == descriptionKeyVolumeSnapshotID {
+			return fmt.Errorf("invalid key in description: %s", k)
+		}
+		if strings.Contains(v, ";") {
+			return fmt.Errorf("invalid value in description (key: %s): %s", k, v)
+		}
+	}
+
+	if l := len(sd.Encode()); l > MaxSnapshotDescriptionLength {
+		return fmt.Errorf("description is too long (length: %d, max: %d)", l, MaxSnapshotDescriptionLength)
+	}
+
+	return nil
+}
+
+// Encode encode to Snapshot.DESCRIPTION format.
+// ex: volumesnapshot;pair=e4c2d1eaf02c0001;uuid=77bea474-...
+// values are not escaped, call Validate before store to Snapshot.DESCRIPTION.
+func (sd *SnapshotDescription) Encode() string {
+	var keys []string
+	for k := range sd.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var kvs []string
+	if sd.HyperMetroPairID != "" {
+		kvs = append(kvs, descriptionKeyHyperMetroPairID+"="+sd.HyperMetroPairID)
+	}
+	kvs = append(kvs, descriptionKeyVolumeSnapshotID+"="+sd.VolumeSnapshotID.String())
+	for _, k := range keys {
+		kvs = append(kvs, k+"="+sd.Values[k])
+	}
+
+	return PrefixVolumeSnapshotDescription + strings.Join(kvs, ";")
+}
+
+// ParseSnapshotDescription parse Snapshot.DESCRIPTION that encoded by SnapshotDescription.Encode.
+func ParseSnapshotDescription(description string) (*SnapshotDescription, error) {
+	if !strings.HasPrefix(description, PrefixVolumeSnapshotDescription) {
+		return nil, errors.New("description is not a volume snapshot")
+	}
+
+	sd := &SnapshotDescription{
+		Values: map[string]string{},
+	}
+	for _, kv := range strings.Split(strings.TrimPrefix(description, PrefixVolumeSnapshotDescription), ";") {
+		s := strings.SplitN(kv, "=", 2)
+		if len(s) != 2 {
+			return nil, fmt.Errorf("invalid key value in description: %s", kv)
+		}
+
+		switch s[0] {
+		case descriptionKeyHyperMetroPairID:
+			sd.HyperMetroPairID = s[1]
+		case descriptionKeyVolumeSnapshotID:
+			u, err := uuid.FromString(s[1])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse uuid: %w", err)
+			}
+			sd.VolumeSnapshotID = u
+		default:
+			sd.Values[s[0]] = s[1]
+		}
+	}
+
+	if uuid.Equal(sd.VolumeSnapshotID, uuid.Nil) {
+		return nil, errors.New("description has not uuid")
+	}
+
+	return sd, nil
+}
+
+// primaryDevice return device and LUN ID that is primary in HyperMetroPair.
+func (c *Client) primaryDevice(hmp *HyperMetroPair) (*Device, int, bool) {
+	if hmp.ISPRIMARY == "true" {
+		return c.LocalDevice, hmp.LOCALOBJID, true
+	}
+
+	return c.RemoteDevice, hmp.REMOTEOBJID, false
+}
+
+// volumeSnapshotDevice return device that has snapshot of volume.
+func (c *Client) volumeSnapshotDevice(vs *VolumeSnapshot) *Device {
+	if vs.IsLocal {
+		return c.LocalDevice
+	}
+	return c.RemoteDevice
+}
+
+// CreateVolumeSnapshot create activated snapshot of volume in primary side.
+func (c *Client) CreateVolumeSnapshot(ctx context.Context, hyperMetroPairID string, u uuid.UUID) (*VolumeSnapshot, error) {
+	return c.createVolumeSnapshot(ctx, hyperMetroPairID, u, nil)
+}
+
+func (c *Client) createVolumeSnapshot(ctx context.Context, hyperMetroPairID string, u uuid.UUID, values map[string]string) (*VolumeSnapshot, error) {
+	hmp, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HyperMetro Pair: %w", err)
+	}
+	d, lunID, isLocal := c.primaryDevice(hmp)
+
+	description := &SnapshotDescription{
+		HyperMetroPairID: hmp.ID,
+		VolumeSnapshotID: u,
+		Values:           values,
+	}
+	if err := description.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate snapshot description: %w", err)
+	}
+	created, err := d.CreateSnapshotWithWait(ctx, lunID, u, description.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if err := d.StopSnapshot(ctx, created.ID); err != nil {
+				d.Logger.Printf("failed to stop snapshot: %v\n", err)
+			}
+			if err := d.DeleteSnapshot(ctx, created.ID); err != nil {
+				d.Logger.Printf("failed to delete snapshot: %v\n", err)
+			}
+		}
+	}()
+
+	if err = d.ActivateSnapshot(ctx, created.ID); err != nil {
+		return nil, fmt.Errorf("failed to activate snapshot: %w", err)
+	}
+	snapshot, err := d.GetSnapshot(ctx, created.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	return &VolumeSnapshot{
+		ID:               u,
+		HyperMetroPairID: hmp.ID,
+		IsLocal:          isLocal,
+		Snapshot:         *snapshot,
+	}, nil
+}
+
+// ListVolumeSnapshots list snapshots of volume in local device and remote device.
+func (c *Client) ListVolumeSnapshots(ctx context.Context, hyperMetroPairID string) ([]VolumeSnapshot, error) {
+	hmp, err := c.GetHyperMetroPair(ctx, hyperMetroPairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HyperMetro Pair: %w", err)
+	}
+
+	var volumeSnapshots []VolumeSnapshot
+	for _, side := range []struct {
+		device  *Device
+		lunID   int
+		isLocal bool
+	}{
+		{device: c.LocalDevice, lunID: hmp.LOCALOBJID, isLocal: true},
+		{device: c.RemoteDevice, lunID: hmp.REMOTEOBJID, isLocal: false},
+	} {
+		snapshots, err := side.device.ListSnapshotsForLUN(ctx, side.lunID)
+		if err != nil {
+			if err == ErrSnapshotNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get snapshots: %w", err)
+		}
+
+		for _, snapshot := range snapshots {
+			sd, err := ParseSnapshotDescription(snapshot.DESCRIPTION)
+			if err != nil || sd.HyperMetroPairID != hmp.ID {
+				// not a volume snapshot
+				continue
+			}
+
+			volumeSnapshots = append(volumeSnapshots, VolumeSnapshot{
+				ID:               sd.VolumeSnapshotID,
+				HyperMetroPairID: hmp.ID,
+				IsLocal:          side.isLocal,
+				Snapshot:         snapshot,
+			})
+		}
+	}
+
+	return volumeSnapshots, nil
+}
+
+// GetVolumeSnapshot get snapshot of volume by uuid.
+func (c *Client) GetVolumeSnapshot(ctx context.Context, hyperMetroPairID string, u uuid.UUID) (*VolumeSnapshot, error) {
+	volumeSnapshots, err := c.ListVolumeSnapshots(ctx, hyperMetroPairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume snapshots: %w", err)
+	}
+
+	for _, vs := range volumeSnapshots {
+		if uuid.Equal(vs.ID, u) {
+			return &vs, nil
+		}
+	}
+
+	return nil, ErrSnapshotNotFound
+}
+
+// DeleteVolumeSnapshot delete snapshot of volume.
+func (c *Client) DeleteVolumeSnapshot(ctx context.Context, hyperMetroPairID string, u uuid.UUID) error {
+	vs, err := c.GetVolumeSnapshot(ctx, hyperMetroPairID, u)
+	if err != nil {
+		return fmt.Errorf("failed to get volume snapshot: %w", err)
+	}
+
+	return c.deleteVolumeSnapshot(ctx, vs)
+}
+
+func (c *Client) deleteVolumeSnapshot(ctx context.Context, vs *VolumeSnapshot) error {
+	d := c.volumeSnapshotDevice(vs)
+
+	if vs.Snapshot.RUNNINGSTATUS == strconv.Itoa(StatusSnapshotActive) {
+		if err := d.StopSnapshot(ctx, vs.Snapshot.ID); err != nil {
+			return fmt.Errorf("failed to stop snapshot: %w", err)
+		}
+	}
+	if err := d.DeleteSnapshot(ctx, vs.Snapshot.ID); err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// AttachVolumeSnapshot create mapping snapshot of volume to hostname by iSCSI.
+// snapshot is mapped from the device that has snapshot.
+func (c *Client) AttachVolumeSnapshot(ctx context.Context, hyperMetroPairID string, u uuid.UUID, hostname, iqn string) error {
+	vs, err := c.GetVolumeSnapshot(ctx, hyperMetroPairID, u)
+	if err != nil {
+		return fmt.Errorf("failed to get volume snapshot: %w", err)
+	}
+
+	d := c.volumeSnapshotDevice(vs)
+	if err := d.AttachSnapshot(ctx, c.portGroupName(hostname), hostname, iqn, vs.Snapshot.ID); err != nil {
+		return fmt.Errorf("failed to attach snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// DetachVolumeSnapshot delete mapping from snapshot of volume.
+func (c *Client) DetachVolumeSnapshot(ctx context.Context, hyperMetroPairID string, u uuid.UUID) error {
+	vs, err := c.GetVolumeSnapshot(ctx, hyperMetroPairID, u)
+	if err != nil {
+		return fmt.Errorf("failed to get volume snapshot: %w", err)
+	}
+
+	d := c.volumeSnapshotDevice(vs)
+	if err := d.DetachSnapshot(ctx, vs.Snapshot.ID); err != nil {
+		return fmt.Errorf("failed to detach snapshot: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestSnapshotDescription(t *testing.T) {
+	u := uuid.FromStringOrNil("77bea474-0f5b-4f1a-9e6e-0b1f0a4b7c2d")
+	sd := &SnapshotDescription{
+		HyperMetroPairID: "e4c2d1eaf02c0001",
+		VolumeSnapshotID: u,
+		Values: map[string]string{
+			"policy": "daily",
+		},
+	}
+
+	encoded := sd.Encode()
+	want := "volumesnapshot;pair=e4c2d1eaf02c0001;uuid=77bea474-0f5b-4f1a-9e6e-0b1f0a4b7c2d;policy=daily"
+	if encoded != want {
+		t.Errorf("Encode return %s, want %s", encoded, want)
+	}
+
+	parsed, err := ParseSnapshotDescription(encoded)
+	if err != nil {
+		t.Fatalf("ParseSnapshotDescription return err: %s", err)
+	}
+	if !reflect.DeepEqual(parsed, sd) {
+		t.Errorf("ParseSnapshotDescription return %+v, want %+v", parsed, sd)
+	}
+
+	for _, invalid := range []string{"", "volume-77bea474", "volumesnapshot;pair=e4c2d1eaf02c0001", "volumesnapshot;uuid=invalid"} {
+		if _, err := ParseSnapshotDescription(invalid); err == nil {
+			t.Errorf("ParseSnapshotDescription(%s) must return err", invalid)
+		}
+	}
+}
+
+func TestSnapshotDescription_Validate(t *testing.T) {
+	u := uuid.FromStringOrNil("77bea474-0f5b-4f1a-9e6e-0b1f0a4b7c2d")
+
+	valid := &SnapshotDescription{HyperMetroPairID: "e4c2d1eaf02c0001", VolumeSnapshotID: u, Values: map[string]string{"note": "a=b"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate return err: %s", err)
+	}
+
+	for _, values := range []map[string]string{
+		{"note": "a;uuid=00000000-0000-0000-0000-000000000000"},
+		{"a;b": "c"},
+		{"a=b": "c"},
+		{"": "c"},
+		{"uuid": "c"},
+		{"note": strings.Repeat("x", MaxSnapshotDescriptionLength)},
+	} {
+		sd := &SnapshotDescription{HyperMetroPairID: "e4c2d1eaf02c0001", VolumeSnapshotID: u, Values: values}
+		if err := sd.Validate(); err == nil {
+			t.Errorf("Validate(%v) must return err", values)
+		}
+	}
+}
+
+func TestClient_CreateVolumeSnapshot_Error(t *testing.T) {
+	u := uuid.FromStringOrNil("77bea474-0f5b-4f1a-9e6e-0b1f0a4b7c2d")
+
+	tests := []struct {
+		name          string
+		activateError bool
+		getError      bool
+	}{
+		{name: "failed to activate", activateError: true},
+		{name: "failed to get activated snapshot", getError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			mux.HandleFunc("/HyperMetroPair/1", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "GET")
+				fmt.Fprint(w, `{"data": {"ID": "1", "ISPRIMARY": "true", "LOCALOBJID": "12", "REMOTEOBJID": "112", "RUNNINGSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/snapshot", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "POST")
+				fmt.Fprint(w, `{"data": {"ID": "15", "PARENTID": "12", "PARENTTYPE": 11, "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+			})
+			var got []string
+			activated := false
+			mux.HandleFunc("/snapshot/15", func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+					if activated && test.getError {
+						fmt.Fprint(w, `{"data": {}, "error": {"code": 1077949001, "description": "internal error"}}`)
+						return
+					}
+					fmt.Fprint(w, `{"data": {"ID": "15", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "45", "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+				case "DELETE":
+					got = append(got, "DELETE /snapshot/15")
+					fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+				default:
+					t.Errorf("Request method: %v, want GET or DELETE", r.Method)
+				}
+			})
+			mux.HandleFunc("/snapshot/activate", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "POST")
+				if test.activateError {
+					fmt.Fprint(w, `{"data": {}, "error": {"code": 1077949001, "description": "internal error"}}`)
+					return
+				}
+				activated = true
+				fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/snapshot/stop", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "PUT")
+				got = append(got, "PUT /snapshot/stop")
+				fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			})
+
+			if _, err := client.CreateVolumeSnapshot(context.Background(), "1", u); err == nil {
+				t.Fatalf("CreateVolumeSnapshot must return err")
+			}
+
+			want := []string{"PUT /snapshot/stop", "DELETE /snapshot/15"}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("CreateVolumeSnapshot request %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package dorado
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+const testIQN = "iqn.1993-08.org.debian:01:test"
+
+// handleMappedHost register handlers of host "host001" that is already mapped to port group "portgroup" (ID: 1).
+// handlers of /portgroup and iSCSI initiator are registered by caller.
+func handleMappedHost(t *testing.T, mux *http.ServeMux) {
+	mux.HandleFunc("/hostgroup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "2", "NAME": "host001", "ISADD2MAPPINGVIEW": "true"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/host", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "3", "NAME": "host001", "ISADD2HOSTGROUP": "true"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/iscsi_initiator", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"data": [{"ID": "%s", "PARENTID": "3", "PARENTTYPE": 21, "TYPE": 222}], "error": {"code": 0, "description": "0"}}`, testIQN)
+	})
+	mux.HandleFunc("/lungroup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "4", "NAME": "host001", "ISADD2MAPPINGVIEW": "true"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lungroup/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/mappingview", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "5", "NAME": "host001"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/portgroup/associate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "1", "NAME": "portgroup"}], "error": {"code": 0, "description": "0"}}`)
+	})
+}
+
+func TestClient_AttachVolumeWithCHAP_RemoteError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	handleMappedHost(t, mux)
+	mux.HandleFunc("/HyperMetroPair/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "1", "LOCALOBJID": "11", "REMOTEOBJID": "21"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	getPortGroup := 0
+	mux.HandleFunc("/portgroup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		getPortGroup++
+		if getPortGroup > 1 {
+			// Remote Device is failed
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 1077949001, "description": "internal error"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"ID": "1", "NAME": "portgroup"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	var updated []map[string]interface{}
+	mux.HandleFunc("/iscsi_initiator/"+testIQN, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		updated = append(updated, testDecodeBody(t, r))
+		fmt.Fprintf(w, `{"data": {"ID": "%s"}, "error": {"code": 0, "description": "0"}}`, testIQN)
+	})
+
+	chap, err := client.AttachVolumeWithCHAP(context.Background(), "1", "host001", testIQN, nil, true)
+	if err == nil {
+		t.Fatalf("AttachVolumeWithCHAP must return err if Remote Device is failed")
+	}
+	if chap == nil || !chap.IsMutual() {
+		t.Fatalf("AttachVolumeWithCHAP must return generated mutual CHAP credential with err, but return %+v", chap)
+	}
+	if len(updated) != 1 || updated[0]["CHAPPASSWORD"] != chap.Password || updated[0]["MUTUALCHAPPASSWORD"] != chap.MutualPassword {
+		t.Errorf("CHAP credential set to Local Device %+v, returned %+v", updated, chap)
+	}
+}
+
+func TestClient_AttachVolumeWithCHAP_MutualMismatch(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	chap := &CHAPParam{Name: "host001", Password: "Passw0rd!abc"}
+	if _, err := client.AttachVolumeWithCHAP(context.Background(), "1", "host001", testIQN, chap, true); err == nil {
+		t.Errorf("AttachVolumeWithCHAP must return err if isMutual is true and chap is not mutual")
+	}
+}
+
+func TestDevice_AttachSnapshot_KeepCHAP(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	handleMappedHost(t, mux)
+	mux.HandleFunc("/portgroup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "1", "NAME": "portgroup"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/snapshot/15", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "15", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "43", "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/iscsi_initiator/"+testIQN, func(w http.ResponseWriter, r *http.Request) {
+		// CHAP setting of initiator in host must not be changed
+		t.Errorf("initiator must not be updated: %s %s", r.Method, r.URL.Path)
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	if err := client.LocalDevice.AttachSnapshot(context.Background(), "portgroup", "host001", testIQN, 15); err != nil {
+		t.Fatalf("AttachSnapshot return err: %s", err)
+	}
+}
+
+func TestDevice_AttachVolume_KeepMappedPortGroup(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	handleMappedHost(t, mux)
+	mux.HandleFunc("/portgroup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": [{"ID": "7", "NAME": "PortGroup_B"}], "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/iscsi_initiator/"+testIQN, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		fmt.Fprintf(w, `{"data": {"ID": "%s"}, "error": {"code": 0, "description": "0"}}`, testIQN)
+	})
+	mux.HandleFunc("/mappingview/create_associate", func(w http.ResponseWriter, r *http.Request) {
+		// host is already mapped to "portgroup" (ID: 1), other port group can not be added to mapping view
+		t.Errorf("mapping view must not be changed: %+v", testDecodeBody(t, r))
+		fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+	})
+
+	if err := client.LocalDevice.AttachVolume(context.Background(), "PortGroup_B", "host001", testIQN, 11); err != nil {
+		t.Fatalf("AttachVolume return err: %s", err)
+	}
+}
+
+func TestClient_CreateVolumeFromSourceWithOption_RemoteError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/HyperMetroPair/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data": {"ID": "1", "LOCALOBJID": "11", "REMOTEOBJID": "21"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	mux.HandleFunc("/lun", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		body := testDecodeBody(t, r)
+		if body["CLONESOURCEID"] == float64(21) {
+			// Remote Device is failed
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 1077949001, "description": "internal error"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"ID": "9", "NAME": "clone", "CAPACITY": "2097152", "ISCLONE": "true"}, "error": {"code": 0, "description": "0"}}`)
+	})
+	deleted := false
+	mux.HandleFunc("/lun/9", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"data": {"ID": "9", "NAME": "clone", "CAPACITY": "2097152", "ISCLONE": "true", "SPLITSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`)
+		case "DELETE":
+			deleted = true
+			fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+		default:
+			t.Errorf("Request method: %v, want GET or DELETE", r.Method)
+		}
+	})
+
+	opt := &CloneOption{SplitMode: SplitLater}
+	if _, err := client.CreateVolumeFromSourceWithOption(context.Background(), uuid.NewV4(), 1, "pool", "1", "1", opt); err == nil {
+		t.Fatalf("CreateVolumeFromSourceWithOption must return err if Remote Device is failed")
+	}
+	if !deleted {
+		t.Errorf("clone LUN in Local Device must be deleted if Remote Device is failed")
+	}
+}
+
+func TestClient_CreateVolumeFromSnapshotWithOption_LUNClone(t *testing.T) {
+	tests := []struct {
+		name        string
+		failTarget  bool
+		wantDeleted bool
+		wantStopped bool
+	}{
+		{
+			name:        "target LUN is failed to create",
+			failTarget:  true,
+			wantDeleted: true,
+			wantStopped: true,
+		},
+		{
+			name:        "linked clone LUN needs activated snapshot",
+			failTarget:  false,
+			wantDeleted: false,
+			wantStopped: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			mux.HandleFunc("/snapshot/15", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "GET")
+				fmt.Fprint(w, `{"data": {"ID": "15", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "45", "TYPE": 27}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/snapshot/activate", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "POST")
+				fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			})
+			stopped := false
+			mux.HandleFunc("/snapshot/stop", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "PUT")
+				stopped = true
+				fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/lun", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "POST")
+				body := testDecodeBody(t, r)
+				if body["CLONESOURCEID"] != float64(15) {
+					fmt.Fprint(w, `{"data": {"ID": "10", "CAPACITY": "2097152"}, "error": {"code": 0, "description": "0"}}`)
+					return
+				}
+				fmt.Fprint(w, `{"data": {"ID": "9", "NAME": "clone", "CAPACITY": "2097152", "ISCLONE": "true"}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/storagepool", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "GET")
+				fmt.Fprint(w, `{"data": [{"ID": "0", "NAME": "pool"}], "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/lun/10", func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+					if test.failTarget {
+						fmt.Fprint(w, `{"data": {}, "error": {"code": 1077949001, "description": "internal error"}}`)
+						return
+					}
+					fmt.Fprint(w, `{"data": {"ID": "10", "HEALTHSTATUS": "1", "RUNNINGSTATUS": "27"}, "error": {"code": 0, "description": "0"}}`)
+				case "DELETE":
+					fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+				default:
+					t.Errorf("Request method: %v, want GET or DELETE", r.Method)
+				}
+			})
+			deleted := false
+			mux.HandleFunc("/lun/9", func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+					fmt.Fprint(w, `{"data": {"ID": "9", "NAME": "clone", "CAPACITY": "2097152", "ISCLONE": "true", "SPLITSTATUS": "1"}, "error": {"code": 0, "description": "0"}}`)
+				case "DELETE":
+					deleted = true
+					fmt.Fprint(w, `{"data": {}, "error": {"code": 0, "description": "0"}}`)
+				default:
+					t.Errorf("Request method: %v, want GET or DELETE", r.Method)
+				}
+			})
+			mux.HandleFunc("/HyperMetroPair", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "POST")
+				fmt.Fprint(w, `{"data": {"ID": "1", "LOCALOBJID": "9", "REMOTEOBJID": "10"}, "error": {"code": 0, "description": "0"}}`)
+			})
+			mux.HandleFunc("/HyperMetroPair/1", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "GET")
+				fmt.Fprint(w, `{"data": {"ID": "1", "LOCALOBJID": "9", "REMOTEOBJID": "10"}, "error": {"code": 0, "description": "0"}}`)
+			})
+
+			opt := &VolumeFromSnapshotOption{
+				Method: SnapshotCopyByLUNClone,
+				Clone:  &CloneOption{SplitMode: SplitLater},
+			}
+			_, err := client.CreateVolumeFromSnapshotWithOption(context.Background(), uuid.NewV4(), 15, 1, "pool", "1", opt)
+			if test.failTarget == (err == nil) {
+				t.Errorf("CreateVolumeFromSnapshotWithOption return err: %v", err)
+			}
+			if deleted != test.wantDeleted {
+				t.Errorf("clone LUN is deleted: %t, want %t", deleted, test.wantDeleted)
+			}
+			if stopped != test.wantStopped {
+				t.Errorf("snapshot is deactivated: %t, want %t", stopped, test.wantStopped)
+			}
+		})
+	}
+}